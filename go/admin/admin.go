@@ -131,11 +131,13 @@ func (a *Admin) Run() error {
 	// API
 	a.router.POST("/admin/executions/add", a.authMiddleware(), a.handleExecutionsAdd)
 	a.router.POST("/admin/executions/clear", a.authMiddleware(), a.handleClearQueue)
+	a.router.POST("/admin/executions/cancel", a.authMiddleware(), a.handleCancelExecution)
 
 	// Pages
 	a.router.GET("/admin/dashboard", a.authMiddleware(), a.homePage)
 	a.router.GET("/admin/dashboard/newexec", a.authMiddleware(), a.newExecutionsPage)
 	a.router.GET("/admin/dashboard/clearqueue", a.authMiddleware(), a.clearQueuePage)
+	a.router.GET("/admin/dashboard/cancelexec", a.authMiddleware(), a.cancelExecutionPage)
 
 	return a.router.Run(":" + a.port)
 }
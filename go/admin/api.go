@@ -70,6 +70,11 @@ type (
 		Auth                  string `json:"auth"`
 		RemoveAdminExecutions bool   `json:"remove_admin_executions"`
 	}
+
+	cancelExecutionRequest struct {
+		Auth string `json:"auth"`
+		UUID string `json:"uuid"`
+	}
 )
 
 func (a *Admin) login(c *gin.Context) {
@@ -88,6 +93,10 @@ func (a *Admin) clearQueuePage(c *gin.Context) {
 	a.render(c, gin.H{"Page": "clearqueue"}, "base.html")
 }
 
+func (a *Admin) cancelExecutionPage(c *gin.Context) {
+	a.render(c, gin.H{"Page": "cancelexec"}, "base.html")
+}
+
 func CreateGhClient(token *oauth2.Token) *goGithub.Client {
 	return goGithub.NewClient(oauthConf.Client(context.Background(), token))
 }
@@ -251,75 +260,92 @@ func (a *Admin) handleExecutionsAdd(c *gin.Context) {
 		return
 	}
 
-	tokenKey, err := c.Cookie("tk")
-	if err != nil {
-		slog.Error("Failed to get token from cookie: ", err)
-		c.AbortWithStatus(http.StatusUnauthorized)
+	var ok bool
+	requestPayload.Auth, ok = a.encryptedToken(c)
+	if !ok {
 		return
 	}
 
-	mu.Lock()
-	defer mu.Unlock()
-
-	token, exists := tokens[tokenKey]
+	statusCode, ok := a.proxyToServer(c, "/executions/add", requestPayload)
+	if !ok {
+		return
+	}
 
-	if !exists {
-		slog.Error("Failed to get token from map")
-		c.AbortWithStatus(http.StatusUnauthorized)
+	if statusCode != http.StatusCreated {
+		c.JSON(statusCode, gin.H{"error": "Failed to process request on server API"})
 		return
 	}
 
-	requestPayload.Auth, err = server.Encrypt(token.AccessToken, a.auth)
+	c.JSON(http.StatusCreated, gin.H{"message": "Execution(s) added successfully"})
+}
 
-	if err != nil {
-		slog.Error("Failed to encrypt token: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt token"})
+func (a *Admin) handleClearQueue(c *gin.Context) {
+	encryptedToken, ok := a.encryptedToken(c)
+	if !ok {
 		return
 	}
 
-	jsonData, err := json.Marshal(requestPayload)
+	requestPayload := clearQueueRequest{
+		Auth:                  encryptedToken,
+		RemoveAdminExecutions: c.PostForm("remove_admin") == "true",
+	}
 
-	if err != nil {
-		slog.Error("Failed to marshal request payload: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to marshal request payload"})
+	statusCode, ok := a.proxyToServer(c, "/executions/clear", requestPayload)
+	if !ok {
 		return
 	}
 
-	serverAPIURL := getAPIURL(a.Mode, "/executions/add")
+	if statusCode != http.StatusAccepted {
+		c.JSON(statusCode, gin.H{"error": "Failed to process request on server API"})
+		return
+	}
 
-	req, err := http.NewRequest("POST", serverAPIURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		slog.Error("Failed to create new HTTP request: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request to server API"})
+	c.JSON(http.StatusAccepted, gin.H{"message": "Execution(s) added successfully"})
+}
+
+func (a *Admin) handleCancelExecution(c *gin.Context) {
+	uuid := strings.TrimSpace(c.PostForm("uuid"))
+	if uuid == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required field: UUID"})
 		return
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		slog.Error("Failed to send request to server API: ", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send request to server API"})
+	encryptedToken, ok := a.encryptedToken(c)
+	if !ok {
 		return
 	}
 
-	defer resp.Body.Close()
+	requestPayload := cancelExecutionRequest{
+		Auth: encryptedToken,
+		UUID: uuid,
+	}
 
-	if resp.StatusCode != http.StatusCreated {
-		slog.Error("Server API returned an error: ", resp.Status)
-		c.JSON(resp.StatusCode, gin.H{"error": "Failed to process request on server API"})
+	statusCode, ok := a.proxyToServer(c, "/executions/cancel", requestPayload)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Execution(s) added successfully"})
+	if statusCode == http.StatusNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No execution with this UUID in the execution queue"})
+		return
+	}
+	if statusCode != http.StatusAccepted {
+		c.JSON(statusCode, gin.H{"error": "Failed to process request on server API"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Execution canceled successfully"})
 }
 
-func (a *Admin) handleClearQueue(c *gin.Context) {
+// encryptedToken returns the encrypted GitHub token of the user making the request, the server's
+// API uses it to authenticate the user. If the user has no token, the request is aborted and false
+// is returned.
+func (a *Admin) encryptedToken(c *gin.Context) (string, bool) {
 	tokenKey, err := c.Cookie("tk")
 	if err != nil {
 		slog.Error("Failed to get token from cookie: ", err)
 		c.AbortWithStatus(http.StatusUnauthorized)
-		return
+		return "", false
 	}
 
 	mu.Lock()
@@ -329,36 +355,36 @@ func (a *Admin) handleClearQueue(c *gin.Context) {
 	if !exists {
 		slog.Error("Failed to get token from map")
 		c.AbortWithStatus(http.StatusUnauthorized)
-		return
+		return "", false
 	}
 
 	encryptedToken, err := server.Encrypt(token.AccessToken, a.auth)
 	if err != nil {
 		slog.Error("Failed to encrypt token: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt token"})
-		return
+		return "", false
 	}
+	return encryptedToken, true
+}
 
-	requestPayload := clearQueueRequest{
-		Auth:                  encryptedToken,
-		RemoveAdminExecutions: c.PostForm("remove_admin") == "true",
-	}
-
-	jsonData, err := json.Marshal(requestPayload)
-
+// proxyToServer sends the given payload as JSON to the endpoint of the server's API and returns the
+// status code of its response. If the request could not be sent, an error is written to the response
+// and false is returned.
+func (a *Admin) proxyToServer(c *gin.Context, endpoint string, payload any) (int, bool) {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		slog.Error("Failed to marshal request payload: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to marshal request payload"})
-		return
+		return 0, false
 	}
 
-	serverAPIURL := getAPIURL(a.Mode, "/executions/clear")
+	serverAPIURL := getAPIURL(a.Mode, endpoint)
 
 	req, err := http.NewRequest("POST", serverAPIURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		slog.Error("Failed to create new HTTP request: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request to server API"})
-		return
+		return 0, false
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -367,18 +393,14 @@ func (a *Admin) handleClearQueue(c *gin.Context) {
 	if err != nil {
 		slog.Error("Failed to send request to server API: ", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send request to server API"})
-		return
+		return 0, false
 	}
-
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted {
+	if resp.StatusCode >= http.StatusBadRequest {
 		slog.Error("Server API returned an error: ", resp.Status)
-		c.JSON(resp.StatusCode, gin.H{"error": "Failed to process request on server API"})
-		return
 	}
-
-	c.JSON(http.StatusAccepted, gin.H{"message": "Execution(s) added successfully"})
+	return resp.StatusCode, true
 }
 
 func getAPIURL(mode server.Mode, endpoint string) string {
@@ -0,0 +1,174 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/dataset"
+	"github.com/vitessio/arewefastyet/go/storage/psdb"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func DatasetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dataset <command>",
+		Short: "Top level command to move executions between databases",
+		Long:  "Top level command to export executions with all their results to a dataset file, and to import dataset files into another database. A dataset is a gzip compressed file of JSON lines, its first line describes the dataset and the columns of each table, and each following line holds an execution with its rows.",
+	}
+
+	cmd.AddCommand(export())
+	cmd.AddCommand(importCmd())
+	return cmd
+}
+
+// newClient returns a client of the SQLite database if it is configured, and of PlanetScaleDB otherwise.
+func newClient(dbConfig *psdb.Config, sqliteConfig *sqlite.Config) (storage.Database, error) {
+	if sqliteConfig.IsValid() {
+		return sqliteConfig.NewClient()
+	}
+	return dbConfig.NewClient()
+}
+
+// parseDate parses a date written either as 2006-01-02 or as RFC 3339, an empty date is the zero time.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
+
+func export() *cobra.Command {
+	dbConfig := &psdb.Config{}
+	sqliteConfig := &sqlite.Config{}
+	var (
+		filter   dataset.Filter
+		from, to string
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Export the executions matching the filters with all their results",
+		Example: "arewefastyet dataset export --source cron_pr --from 2026-01-01 --to 2026-02-01 --output ./pull-requests.jsonl.gz",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if output == "" {
+				return errors.New("--output is required")
+			}
+			if filter.From, err = parseDate(from); err != nil {
+				return err
+			}
+			if filter.To, err = parseDate(to); err != nil {
+				return err
+			}
+
+			client, err := newClient(dbConfig, sqliteConfig)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			summary, err := dataset.Export(client, f, filter)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+			printSummary(cmd.OutOrStdout(), summary, "exported")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.GitRef, "git-ref", "", "Export the executions of this git ref.")
+	cmd.Flags().StringVar(&filter.Source, "source", "", "Export the executions of this source, for instance cron_pr.")
+	cmd.Flags().StringVar(&filter.Workload, "workload", "", "Export the executions of this workload.")
+	cmd.Flags().StringVar(&from, "from", "", "Export the executions started at or after this date, written as YYYY-MM-DD or RFC 3339.")
+	cmd.Flags().StringVar(&to, "to", "", "Export the executions started before this date, written as YYYY-MM-DD or RFC 3339.")
+	cmd.Flags().StringVar(&output, "output", "", "Path of the dataset file written by the export.")
+	dbConfig.AddToCommand(cmd)
+	sqliteConfig.AddToCommand(cmd)
+	return cmd
+}
+
+func importCmd() *cobra.Command {
+	dbConfig := &psdb.Config{}
+	sqliteConfig := &sqlite.Config{}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import the executions of a dataset file",
+		Long:  "Import the executions of a dataset file written by export. The executions that already exist in the database are skipped, and the rows of the other ones get new IDs.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			client, err := newClient(dbConfig, sqliteConfig)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			summary, err := dataset.Import(client, f)
+			printSummary(cmd.OutOrStdout(), summary, "imported")
+			if summary.Skipped > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d executions skipped because they already exist\n", summary.Skipped)
+			}
+			return err
+		},
+	}
+
+	dbConfig.AddToCommand(cmd)
+	sqliteConfig.AddToCommand(cmd)
+	return cmd
+}
+
+func printSummary(w io.Writer, summary dataset.Summary, verb string) {
+	tables := make([]string, 0, len(summary.Rows))
+	for table := range summary.Rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Fprintf(w, "%d rows of %s %s\n", summary.Rows[table], table, verb)
+	}
+	fmt.Fprintf(w, "%d executions %s\n", summary.Executions, verb)
+}
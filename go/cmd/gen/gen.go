@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vitessio/arewefastyet/go/cmd/gen/doc"
 	"github.com/vitessio/arewefastyet/go/cmd/gen/metrics"
+	"github.com/vitessio/arewefastyet/go/cmd/gen/prune"
 )
 
 func GenCmd() *cobra.Command {
@@ -33,5 +34,6 @@ func GenCmd() *cobra.Command {
 
 	cmd.AddCommand(doc.GenerateDoc())
 	cmd.AddCommand(metrics.GenExecMetricsCmd())
+	cmd.AddCommand(prune.PruneCmd())
 	return cmd
 }
@@ -24,26 +24,26 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vitessio/arewefastyet/go/exec"
 	"github.com/vitessio/arewefastyet/go/exec/metrics"
-	"github.com/vitessio/arewefastyet/go/storage/influxdb"
 	"github.com/vitessio/arewefastyet/go/storage/psdb"
 )
 
 func GenExecMetricsCmd() *cobra.Command {
 	dbConfig := &psdb.Config{}
-	metricsDBConfig := &influxdb.Config{}
+	metricsConfig := &metrics.Config{}
 
 	cmd := &cobra.Command{
 		Use:   "exec_metrics",
-		Short: "For each execution, fetches the metrics from influxDB and store them to SQL if not already present.",
+		Short: "For each execution, fetches the metrics from the metrics backend and store them to SQL if not already present.",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			clientSQL, err := dbConfig.NewClient()
 			if err != nil {
 				return err
 			}
-			clientMetrics, err := metricsDBConfig.NewClient()
+			metricsSource, err := metricsConfig.NewSource()
 			if err != nil {
 				return err
 			}
+			defer metricsSource.Close()
 
 			rowsExecUUIDs, err := clientSQL.Read("select uuid from execution where status = ?", exec.StatusFinished)
 			if err != nil {
@@ -68,7 +68,7 @@ func GenExecMetricsCmd() *cobra.Command {
 					continue
 				}
 
-				executionMetrics, err := metrics.GetExecutionMetrics(*clientMetrics, uuid, 0)
+				executionMetrics, err := metrics.GetExecutionMetrics(metricsSource, uuid, 0)
 				if err != nil {
 					return err
 				}
@@ -86,6 +86,6 @@ func GenExecMetricsCmd() *cobra.Command {
 	}
 
 	dbConfig.AddToCommand(cmd)
-	metricsDBConfig.AddToCommand(cmd)
+	metricsConfig.AddToCommand(cmd)
 	return cmd
 }
@@ -0,0 +1,123 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package prune
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/psdb"
+	"github.com/vitessio/arewefastyet/go/storage/retention"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func PruneCmd() *cobra.Command {
+	dbConfig := &psdb.Config{}
+	sqliteConfig := &sqlite.Config{}
+	var (
+		ruleFlags  []string
+		archiveDir string
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the rows of the database older than the retention rules, after archiving them",
+		Long: `Remove the rows of the database older than the retention rules, after archiving them as compressed JSON files in the archive directory.
+
+Each rule is written as [<table>:]<source>=<age>. The source may end with '*' to match every source starting with the same prefix. The age is "forever", a number of days such as "90d", or a duration such as "12h", and is counted from the start of the execution.
+A rule without a table removes the whole executions with all their rows, a rule with a table only removes the rows of that table, which must be one of query_plans, microbenchmark_details or metrics.
+When several rules match the source of an execution, the most specific one is used. The executions matching no rule are kept.`,
+		Example: `arewefastyet gen prune --dry-run --rule 'cron_tags_*=forever' --rule cron_pr=90d --rule 'query_plans:*=30d'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(ruleFlags) == 0 {
+				return errors.New("at least one --rule is required")
+			}
+			rules := make([]retention.Rule, 0, len(ruleFlags))
+			for _, s := range ruleFlags {
+				rule, err := retention.ParseRule(s)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, rule)
+			}
+
+			client, err := newClient(dbConfig, sqliteConfig)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			if dryRun {
+				report, err := retention.Plan(client, rules)
+				if err != nil {
+					return err
+				}
+				printReport(cmd.OutOrStdout(), report, "would be removed")
+				return nil
+			}
+			report, err := retention.Prune(client, rules, archiveDir)
+			printReport(cmd.OutOrStdout(), report, "removed")
+			return err
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&ruleFlags, "rule", nil, "Retention rule written as [<table>:]<source>=<age>, can be repeated.")
+	cmd.Flags().StringVar(&archiveDir, "archive-dir", "./archive", "Directory in which the removed rows are archived, each run writes its files to a new sub-directory.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be removed without archiving nor removing anything.")
+	dbConfig.AddToCommand(cmd)
+	sqliteConfig.AddToCommand(cmd)
+	return cmd
+}
+
+// newClient returns a client of the SQLite database if it is configured, and of PlanetScaleDB otherwise.
+func newClient(dbConfig *psdb.Config, sqliteConfig *sqlite.Config) (storage.Database, error) {
+	if sqliteConfig.IsValid() {
+		return sqliteConfig.NewClient()
+	}
+	return dbConfig.NewClient()
+}
+
+func printReport(w io.Writer, report retention.Report, verb string) {
+	rules := make([]retention.Rule, 0, len(report.Rules))
+	for rule := range report.Rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].String() < rules[j].String() })
+	for _, rule := range rules {
+		fmt.Fprintf(w, "rule %s applies to %d executions\n", rule, report.Rules[rule])
+	}
+
+	tables := make([]string, 0, len(report.Rows))
+	for table := range report.Rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Fprintf(w, "%d rows of %s %s\n", report.Rows[table], table, verb)
+	}
+	fmt.Fprintf(w, "%d executions %s\n", report.Executions, verb)
+	if report.Archive != "" {
+		fmt.Fprintf(w, "the removed rows are archived in %s\n", report.Archive)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vitessio/arewefastyet/go/storage/migrate"
+	"github.com/vitessio/arewefastyet/go/storage/psdb"
+)
+
+func MigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <command>",
+		Short: "Top level command to manage the schema of the database",
+		Long:  "Top level command to manage the schema of the database. The migrations are embedded in the binary and the version of the schema is recorded in the schema_version table of the database.",
+	}
+
+	cmd.AddCommand(up())
+	cmd.AddCommand(down())
+	cmd.AddCommand(status())
+	cmd.AddCommand(baseline())
+	return cmd
+}
+
+// newMigrator returns a migrate.Migrator connected to the database of the given configuration.
+func newMigrator(dbConfig *psdb.Config) (*migrate.Migrator, func(), error) {
+	client, err := dbConfig.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := migrate.New(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	return m, func() { _ = client.Close() }, nil
+}
+
+func up() *cobra.Command {
+	dbConfig := &psdb.Config{}
+	var to int
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply the migrations that were not applied yet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, closeClient, err := newMigrator(dbConfig)
+			if err != nil {
+				return err
+			}
+			defer closeClient()
+
+			applied, err := m.Up(to)
+			for _, migration := range applied {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied migration %d (%s)\n", migration.Version, migration.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "the schema is up to date")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Version up to which the migrations are applied, the latest version by default.")
+	dbConfig.AddToCommand(cmd)
+	return cmd
+}
+
+func down() *cobra.Command {
+	dbConfig := &psdb.Config{}
+	var to int
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert the migrations applied after a version",
+		Long:  "Revert the migrations applied after the version given with --to, or the last applied migration if it is not set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, closeClient, err := newMigrator(dbConfig)
+			if err != nil {
+				return err
+			}
+			defer closeClient()
+
+			if !cmd.Flags().Changed("to") {
+				version, err := m.Version()
+				if err != nil {
+					return err
+				}
+				if version == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "no migration to revert")
+					return nil
+				}
+				to = version - 1
+			}
+			reverted, err := m.Down(to)
+			for _, migration := range reverted {
+				fmt.Fprintf(cmd.OutOrStdout(), "reverted migration %d (%s)\n", migration.Version, migration.Name)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Version to which the schema is reverted.")
+	dbConfig.AddToCommand(cmd)
+	return cmd
+}
+
+func status() *cobra.Command {
+	dbConfig := &psdb.Config{}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the version of the schema and the migrations that are not applied yet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, closeClient, err := newMigrator(dbConfig)
+			if err != nil {
+				return err
+			}
+			defer closeClient()
+
+			version, err := m.Version()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "the database is at version %d, the latest version is %d\n", version, m.Latest())
+			for _, migration := range m.Migrations() {
+				if migration.Version > version {
+					fmt.Fprintf(cmd.OutOrStdout(), "pending migration %d (%s)\n", migration.Version, migration.Name)
+				}
+			}
+			return nil
+		},
+	}
+
+	dbConfig.AddToCommand(cmd)
+	return cmd
+}
+
+func baseline() *cobra.Command {
+	dbConfig := &psdb.Config{}
+
+	cmd := &cobra.Command{
+		Use:   "baseline <version>",
+		Short: "Record the migrations up to a version as applied without running them",
+		Long:  "Record the migrations up to the given version as applied without running them. It is used once on a database whose schema was created before the migrations existed. A database created from the former sql/schema.sql is at version 4, it is baselined with 'migrate baseline 4' and then upgraded with 'migrate up'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid version %q: %w", args[0], err)
+			}
+			m, closeClient, err := newMigrator(dbConfig)
+			if err != nil {
+				return err
+			}
+			defer closeClient()
+
+			if err := m.Baseline(version); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "the database is at version %d\n", version)
+			return nil
+		},
+	}
+
+	dbConfig.AddToCommand(cmd)
+	return cmd
+}
@@ -24,10 +24,12 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/vitessio/arewefastyet/go/cmd/admin"
 	"github.com/vitessio/arewefastyet/go/cmd/api"
+	"github.com/vitessio/arewefastyet/go/cmd/dataset"
 	"github.com/vitessio/arewefastyet/go/cmd/exec"
 	"github.com/vitessio/arewefastyet/go/cmd/gen"
 	"github.com/vitessio/arewefastyet/go/cmd/macrobench"
 	"github.com/vitessio/arewefastyet/go/cmd/microbench"
+	"github.com/vitessio/arewefastyet/go/cmd/migrate"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
@@ -69,6 +71,8 @@ func init() {
 	rootCmd.AddCommand(macrobench.MacroBenchCmd())
 	rootCmd.AddCommand(exec.ExecCmd())
 	rootCmd.AddCommand(gen.GenCmd())
+	rootCmd.AddCommand(migrate.MigrateCmd())
+	rootCmd.AddCommand(dataset.DatasetCmd())
 }
 
 // initConfig reads in config file and ENV variables if set.
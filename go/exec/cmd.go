@@ -50,8 +50,14 @@ func (e *Exec) AddToViper(v *viper.Viper) (err error) {
 	_ = v.UnmarshalKey(flagVitessConfig, &e.rawVitessConfig)
 	_ = v.UnmarshalKey(flagVitessSchema, &e.vitessSchemaPath)
 
+	e.Timeouts, err = TimeoutsFromConfig(v, e.Workload)
+	if err != nil {
+		return err
+	}
+
 	e.AnsibleConfig.AddToViper(v)
 	e.configDB.AddToViper(v)
+	e.configSQLite.AddToViper(v)
 	e.statsRemoteDBConfig.AddToViper(v)
 	return nil
 }
@@ -84,4 +90,5 @@ func (e *Exec) AddToCommand(cmd *cobra.Command) {
 	e.AnsibleConfig.AddToPersistentCommand(cmd)
 	e.statsRemoteDBConfig.AddToCommand(cmd)
 	e.configDB.AddToCommand(cmd)
+	e.configSQLite.AddToCommand(cmd)
 }
@@ -19,7 +19,7 @@
 package exec
 
 import (
-	"database/sql"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -31,12 +31,14 @@ import (
 
 	"github.com/vitessio/arewefastyet/go/storage"
 	"github.com/vitessio/arewefastyet/go/storage/psdb"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
 	"github.com/vitessio/arewefastyet/go/tools/git"
 
 	"github.com/google/uuid"
 	"github.com/spf13/viper"
 	"github.com/vitessio/arewefastyet/go/exec/stats"
 	"github.com/vitessio/arewefastyet/go/infra/ansible"
+	"github.com/vitessio/arewefastyet/go/storage/artifact"
 )
 
 const (
@@ -67,6 +69,11 @@ type Exec struct {
 	// Status defines the status of the execution (canceled, finished, failed, etc)
 	Status string
 
+	// FailureCategory and FailureReason explain why the execution failed,
+	// they are empty unless the status is failed.
+	FailureCategory FailureCategory
+	FailureReason   string
+
 	StartedAt  *time.Time
 	FinishedAt *time.Time
 
@@ -76,11 +83,16 @@ type Exec struct {
 	// PullNB defines the pull request number linked to this execution.
 	PullNB int
 
-	// Configuration used to interact with the SQL database.
-	configDB *psdb.Config
+	// Timeouts are the time budgets of the execution, read from its configuration.
+	Timeouts Timeouts
+
+	// Configuration used to interact with the SQL database. The SQLite database
+	// is used instead of PlanetScaleDB when it is configured.
+	configDB     *psdb.Config
+	configSQLite *sqlite.Config
 
 	// Client to communicate with the SQL database.
-	clientDB *psdb.Client
+	clientDB storage.Database
 
 	// Configuration used to authenticate and insert execution stats
 	// data to a remote database system.
@@ -161,10 +173,14 @@ type ProfileInformation struct {
 const (
 	MaximumBenchmarkWithSameConfig = 10
 
+	// HeartbeatInterval is the interval at which a started execution updates its heartbeat.
+	HeartbeatInterval = 30 * time.Second
+
 	SourceCron          = "cron"
 	SourcePullRequest   = "cron_pr"
 	SourceTag           = "cron_tags_"
 	SourceReleaseBranch = "cron_"
+	SourceBisection     = "bisect"
 )
 
 // NewExec creates a new *Exec given the string representation of an uuid.UUID.
@@ -188,6 +204,7 @@ func NewExec(uuidRaw string) (*Exec, error) {
 		stderr: os.Stderr,
 
 		configDB:      &psdb.Config{},
+		configSQLite:  &sqlite.Config{},
 		clientDB:      nil,
 		configPath:    viper.ConfigFileUsed(),
 		AnsibleConfig: ansible.NewConfig(),
@@ -222,6 +239,17 @@ func NewExecWithConfig(path, uuid string) (*Exec, error) {
 	return e, nil
 }
 
+// Dir returns the directory of the execution, it is empty until the execution is prepared.
+func (e *Exec) Dir() string {
+	return e.dirPath
+}
+
+// LogFiles returns the names of the files in which the outputs of the execution
+// are written, relative to the directory of the execution.
+func LogFiles() []string {
+	return []string{stdoutFile, stderrFile}
+}
+
 // SetStdout sets the standard output of Exec.
 func (e *Exec) SetStdout(stdout *os.File) {
 	e.stdout = stdout
@@ -257,21 +285,22 @@ func (e *Exec) SetOutputToDefaultPath() error {
 }
 
 // Prepare prepares the Exec for a future Execution.
-func (e *Exec) Prepare() error {
+// Once the execution is created in the database, a failure to prepare it marks it as
+// failed and is returned as a *Failure.
+func (e *Exec) Prepare() (err error) {
 	// Returns if the execution is already prepared
 	if e.prepared {
 		return nil
 	}
 
-	var err error
 	defer func() {
 		if !e.createdInDB {
 			return
 		}
-		e.handleStepEnd(err)
+		err = e.handleStepEnd(err)
 	}()
 
-	e.clientDB, err = e.configDB.NewClient()
+	e.clientDB, err = e.newDatabaseClient()
 	if err != nil {
 		return err
 	}
@@ -314,47 +343,91 @@ func (e *Exec) Prepare() error {
 	return nil
 }
 
-// ExecuteWithTimeout will call execution's Execute method with the given timeout.
-func (e Exec) ExecuteWithTimeout(timeout time.Duration) (err error) {
-	defer func() {
-		e.handleStepEnd(err)
-	}()
-	errs := make(chan error)
-
-	go func() {
-		errs <- e.Execute()
-	}()
-
-	select {
-	case err = <-errs:
-		return
-	case <-time.After(timeout):
-		err = errors.New(ErrorExecutionTimeout)
-		return
+// ExecuteWithTimeout will call execution's Execute method with the given timeout, or with
+// the total timeout of Exec.Timeouts if the given timeout is zero.
+// The execution is stopped as soon as the timeout expires or the given context is canceled,
+// an expired timeout results in a *Failure with the FailureTimeout category.
+func (e *Exec) ExecuteWithTimeout(ctx context.Context, timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = e.Timeouts.Total
+	}
+	if timeout == 0 {
+		timeout = DefaultTimeout(e.Workload)
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return e.Execute(ctx)
 }
 
 // Execute will provision infra, configure Ansible files, and run the given Ansible config.
-func (e *Exec) Execute() (err error) {
+// Canceling the given context stops Ansible and marks the execution as canceled, unless the
+// cause of the cancellation is a *Failure which is then returned. Ansible is also stopped if
+// a phase of the execution exceeds its budget in Exec.Timeouts, which results in a *Failure
+// with the FailureTimeout category.
+func (e *Exec) Execute(ctx context.Context) (err error) {
 	defer func() {
-		e.handleStepEnd(err)
+		err = e.handleStepEnd(err)
 	}()
 
 	if !e.prepared {
 		return errors.New(ErrorNotPrepared)
 	}
-	if _, err := e.clientDB.Write("UPDATE execution SET started_at = CURRENT_TIME, status = ? WHERE uuid = ?", StatusStarted, e.UUID.String()); err != nil {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := e.clientDB.Write("UPDATE execution SET started_at = CURRENT_TIMESTAMP, heartbeat_at = CURRENT_TIMESTAMP, status = ? WHERE uuid = ?", StatusStarted, e.UUID.String()); err != nil {
 		return err
 	}
 
+	ansibleCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Follow the phases of the execution to enforce their budget
+	if len(e.Timeouts.Phases) > 0 {
+		budget := newPhaseBudget(e.Timeouts.Phases, cancel)
+		defer budget.stop()
+		e.AnsibleConfig.SetStdoutWriter(io.MultiWriter(e.stdout, budget))
+		defer e.AnsibleConfig.SetStdoutWriter(e.stdout)
+	}
+
+	// Prove that the execution is still running until Ansible returns, even if
+	// Ansible does not print anything during its long tasks
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+	go e.heartbeat(heartbeatCtx, HeartbeatInterval)
+
 	// Run the given config on Ansible
-	err = ansible.Run(&e.AnsibleConfig)
+	err = ansible.Run(ansibleCtx, &e.AnsibleConfig)
 	if err != nil {
+		var phaseErr *PhaseTimeoutError
+		if errors.As(context.Cause(ansibleCtx), &phaseErr) {
+			return &Failure{Category: FailureTimeout, Reason: phaseErr.Error(), Err: phaseErr}
+		}
+		var failure *Failure
+		if errors.As(context.Cause(ansibleCtx), &failure) {
+			return failure
+		}
 		return err
 	}
 	return nil
 }
 
+// heartbeat updates the heartbeat of the execution at every interval until the given
+// context is done. Executions that stopped sending heartbeats are reaped by the server.
+func (e *Exec) heartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = e.clientDB.Write("UPDATE execution SET heartbeat_at = CURRENT_TIMESTAMP WHERE uuid = ? AND status = ?", e.UUID.String(), StatusStarted)
+		}
+	}
+}
+
 // prepareAnsibleForExecution adds all the required values to run the benchmark with Ansible.
 // These values are stored using a key/value map.
 func (e *Exec) prepareAnsibleForExecution() error {
@@ -391,6 +464,8 @@ func (e *Exec) prepareAnsibleForExecution() error {
 	e.AnsibleConfig.AddExtraVar(ansible.KeyVitessSchema, e.vitessSchemaPath)
 	e.vitessConfig.addToAnsible(&e.AnsibleConfig)
 
+	e.AnsibleConfig.AddExtraVar(ansible.KeyArtifactsDir, path.Join(e.dirPath, artifact.DirName))
+
 	if e.ProfileInformation != nil {
 		e.AnsibleConfig.AddExtraVar(ansible.KeyProfileBinary, e.ProfileInformation.Binary)
 		e.AnsibleConfig.AddExtraVar(ansible.KeyProfileMode, e.ProfileInformation.Mode)
@@ -405,8 +480,8 @@ func (e *Exec) prepareAnsibleForExecution() error {
 }
 
 func (e *Exec) Success() error {
-	// checking if the execution has not already failed
-	rows, err := e.clientDB.Read("SELECT uuid FROM execution WHERE uuid = ? AND status = ?", e.UUID.String(), StatusFailed)
+	// checking if the execution has not already failed or been canceled
+	rows, err := e.clientDB.Read("SELECT uuid FROM execution WHERE uuid = ? AND status IN (?, ?)", e.UUID.String(), StatusFailed, StatusCanceled)
 	if err != nil {
 		return err
 	}
@@ -414,14 +489,70 @@ func (e *Exec) Success() error {
 	if rows.Next() {
 		return nil
 	}
-	_, err = e.clientDB.Write("UPDATE execution SET finished_at = CURRENT_TIME, status = ? WHERE uuid = ?", StatusFinished, e.UUID.String())
+	_, err = e.clientDB.Write("UPDATE execution SET finished_at = CURRENT_TIMESTAMP, status = ? WHERE uuid = ?", StatusFinished, e.UUID.String())
 	return err
 }
 
-func (e *Exec) handleStepEnd(err error) {
+// handleStepEnd marks the execution as canceled or failed if the given error is not nil.
+// Failures are classified using the error and Ansible's logs, the returned error is
+// a *Failure in that case.
+func (e *Exec) handleStepEnd(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) {
+		e.Status = StatusCanceled
+		_, _ = e.clientDB.Write("UPDATE execution SET finished_at = CURRENT_TIMESTAMP, status = ? WHERE uuid = ? AND status IN (?, ?)", StatusCanceled, e.UUID.String(), StatusCreated, StatusStarted)
+		return err
+	}
+
+	failure := ClassifyFailure(err, e.readStdoutTail())
+	e.Status = StatusFailed
+	e.FailureCategory = failure.Category
+	e.FailureReason = failure.Reason
+	_, _ = e.clientDB.Write("UPDATE execution SET finished_at = CURRENT_TIMESTAMP, status = ?, failure_category = ?, failure_reason = ? WHERE uuid = ?",
+		StatusFailed, failure.Category, failure.Reason, e.UUID.String())
+	return failure
+}
+
+// readStdoutTail returns the end of the default stdout file of the execution, which
+// contains Ansible's logs. Nil is returned if the file cannot be read.
+func (e *Exec) readStdoutTail() []byte {
+	const maxTail = 256 * 1024
+
+	file, err := os.Open(path.Join(e.dirPath, stdoutFile))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := stat.Size() - maxTail
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, stat.Size()-offset)
+	n, _ := file.ReadAt(tail, offset)
+	return tail[:n]
+}
+
+// newDatabaseClient connects to the SQLite database if one is configured, and to PlanetScaleDB otherwise.
+func (e *Exec) newDatabaseClient() (storage.Database, error) {
+	if e.configSQLite.IsValid() {
+		client, err := e.configSQLite.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+	client, err := e.configDB.NewClient()
 	if err != nil {
-		_, _ = e.clientDB.Write("UPDATE execution SET finished_at = CURRENT_TIME, status = ? WHERE uuid = ?", StatusFailed, e.UUID.String())
+		return nil, err
 	}
+	return client, nil
 }
 
 func (e *Exec) insert() error {
@@ -442,7 +573,8 @@ func (e *Exec) insert() error {
 
 func GetRecentExecutions(client storage.SQLClient) ([]*Exec, error) {
 	var res []*Exec
-	query := "SELECT uuid, status, git_ref, started_at, finished_at, source, workload, pull_nb, go_version, IFNULL(profile_binary, ''), IFNULL(profile_mode, '') FROM execution ORDER BY started_at DESC LIMIT 1000"
+	query := "SELECT uuid, status, git_ref, started_at, finished_at, source, workload, pull_nb, go_version, IFNULL(profile_binary, ''), IFNULL(profile_mode, ''), " +
+		"IFNULL(failure_category, ''), IFNULL(failure_reason, '') FROM execution ORDER BY started_at DESC LIMIT 1000"
 	result, err := client.Read(query)
 	if err != nil {
 		return nil, err
@@ -452,7 +584,8 @@ func GetRecentExecutions(client storage.SQLClient) ([]*Exec, error) {
 		exec := &Exec{
 			ProfileInformation: &ProfileInformation{},
 		}
-		err = result.Scan(&exec.RawUUID, &exec.Status, &exec.GitRef, &exec.StartedAt, &exec.FinishedAt, &exec.Source, &exec.Workload, &exec.PullNB, &exec.GolangVersion, &exec.ProfileInformation.Binary, &exec.ProfileInformation.Mode)
+		err = result.Scan(&exec.RawUUID, &exec.Status, &exec.GitRef, &exec.StartedAt, &exec.FinishedAt, &exec.Source, &exec.Workload, &exec.PullNB, &exec.GolangVersion, &exec.ProfileInformation.Binary, &exec.ProfileInformation.Mode,
+			&exec.FailureCategory, &exec.FailureReason)
 		if err != nil {
 			return nil, err
 		}
@@ -463,7 +596,7 @@ func GetRecentExecutions(client storage.SQLClient) ([]*Exec, error) {
 
 func GetFinishedExecution(client storage.SQLClient, gitRef, source, workload, plannerVersion string, pullNb int) (string, error) {
 	var eUUID string
-	var result *sql.Rows
+	var result storage.Rows
 	var err error
 	query := ""
 	if plannerVersion == "" {
@@ -642,6 +775,61 @@ func DeleteExecution(client storage.SQLClient, gitRef, UUID, source string) erro
 	return nil
 }
 
+// MarkAsCanceled sets the status of the given execution to canceled, unless the
+// execution has already reached the finished, failed or canceled status.
+func MarkAsCanceled(client storage.SQLClient, UUID string) error {
+	_, err := client.Write("UPDATE execution SET finished_at = CURRENT_TIMESTAMP, status = ? WHERE uuid = ? AND status IN (?, ?)", StatusCanceled, UUID, StatusCreated, StatusStarted)
+	return err
+}
+
+// MarkAsFailed sets the status of the given execution to failed with the given failure category
+// and reason, unless the execution has already reached the finished, failed or canceled status.
+func MarkAsFailed(client storage.SQLClient, UUID string, category FailureCategory, reason string) error {
+	_, err := client.Write("UPDATE execution SET finished_at = CURRENT_TIMESTAMP, status = ?, failure_category = ?, failure_reason = ? WHERE uuid = ? AND status IN (?, ?)",
+		StatusFailed, category, reason, UUID, StatusCreated, StatusStarted)
+	return err
+}
+
+// GetExecutionsWithoutHeartbeat returns the UUIDs of the started executions that did not send
+// a heartbeat for longer than the given duration, including the ones that never sent one.
+func GetExecutionsWithoutHeartbeat(client storage.SQLClient, since time.Duration) ([]string, error) {
+	rows, err := client.Read("SELECT uuid FROM execution WHERE status = ? AND (heartbeat_at IS NULL OR heartbeat_at < "+storage.DialectOf(client).SecondsAgo()+")",
+		StatusStarted, int(since.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, rows.Err()
+}
+
+// GetStartedExecutions returns the UUIDs of the executions that have the started status.
+func GetStartedExecutions(client storage.SQLClient) ([]string, error) {
+	rows, err := client.Read("SELECT uuid FROM execution WHERE status = ?", StatusStarted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, rows.Err()
+}
+
 type History struct {
 	SHA                  string     `json:"sha"`
 	Source               string     `json:"source"`
@@ -0,0 +1,56 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+)
+
+func TestExec_heartbeat(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	e := &Exec{UUID: uuid.New(), clientDB: client}
+	_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, heartbeat_at) VALUES(?, ?, ?, ?, ?)",
+		e.UUID.String(), StatusStarted, SourceCron, "abcd", time.Now().UTC().Add(-time.Hour))
+	c.Assert(err, qt.IsNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.heartbeat(ctx, 10*time.Millisecond)
+
+	// The heartbeat is sent even if Ansible does not print anything.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		uuids, err := GetExecutionsWithoutHeartbeat(client, 10*time.Minute)
+		c.Assert(err, qt.IsNil)
+		if len(uuids) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			c.Fatal("no heartbeat was sent while the execution was running")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
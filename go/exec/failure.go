@@ -0,0 +1,171 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FailureCategory describes why an execution failed.
+type FailureCategory string
+
+const (
+	// FailureInfrastructure is used when the benchmark host or the network failed,
+	// like an unreachable host or a task provisioning the host that failed.
+	FailureInfrastructure FailureCategory = "infrastructure"
+
+	// FailureVitessBuild is used when Vitess could not be fetched or built.
+	FailureVitessBuild FailureCategory = "vitess_build"
+
+	// FailureBenchmarkTool is used when the benchmark itself failed to run,
+	// like sysbench or the arewefastyet CLI on the benchmark host.
+	FailureBenchmarkTool FailureCategory = "benchmark_tool"
+
+	// FailureTimeout is used when the execution did not finish in time.
+	FailureTimeout FailureCategory = "timeout"
+
+	// FailureUnknown is used when the failure could not be classified.
+	FailureUnknown FailureCategory = "unknown"
+
+	// maxFailureReasonLength is the maximum length of the reason stored in the database.
+	maxFailureReasonLength = 1024
+)
+
+// FailureCategories lists all the categories a failure can be classified in.
+var FailureCategories = []FailureCategory{FailureInfrastructure, FailureVitessBuild, FailureBenchmarkTool, FailureTimeout, FailureUnknown}
+
+var (
+	// ansibleTaskRegexp matches the header Ansible prints before running a task,
+	// for instance: "TASK [vitess_build : Install Vitess Binaries] ****".
+	ansibleTaskRegexp = regexp.MustCompile(`^TASK \[(.+?)\]`)
+
+	// ansibleFailureRegexp matches the lines Ansible prints when a task fails on a host.
+	ansibleFailureRegexp = regexp.MustCompile(`^(fatal|failed): \[[^\]]*\]`)
+
+	benchmarkToolRoles = []string{"macrobench", "microbench", "sysbench", "arewefastyet"}
+
+	infrastructureErrors = []string{"unreachable", "failed to connect to the host", "connection refused", "connection timed out", "no route to host", "connection reset"}
+)
+
+// Failure is an error annotated with the category of the failure and
+// a short human-readable reason explaining what failed.
+type Failure struct {
+	Category FailureCategory
+	Reason   string
+	Err      error
+}
+
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s failure: %v", f.Category, f.Err)
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// ClassifyFailure determines the category of a failed execution given the error it
+// returned and the logs of Ansible. The logs are used to find which task failed.
+func ClassifyFailure(err error, logs []byte) *Failure {
+	if err == nil {
+		return nil
+	}
+	var failure *Failure
+	if errors.As(err, &failure) {
+		return failure
+	}
+	failure = &Failure{Category: FailureUnknown, Reason: err.Error(), Err: err}
+
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), ErrorExecutionTimeout) {
+		failure.Category = FailureTimeout
+		failure.Reason = ErrorExecutionTimeout
+		return failure
+	}
+
+	if task, line, found := findFailedAnsibleTask(logs); found {
+		failure.Category = classifyAnsibleTask(task, line)
+		failure.Reason = truncateReason(fmt.Sprintf("TASK [%s]: %s", task, line))
+		return failure
+	}
+
+	if containsAny(err.Error(), infrastructureErrors) {
+		failure.Category = FailureInfrastructure
+	}
+	failure.Reason = truncateReason(failure.Reason)
+	return failure
+}
+
+// findFailedAnsibleTask returns the name of the first task that failed in the given
+// Ansible logs, along with the line describing the failure.
+func findFailedAnsibleTask(logs []byte) (task, line string, found bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if match := ansibleTaskRegexp.FindStringSubmatch(text); match != nil {
+			task = match[1]
+			continue
+		}
+		if ansibleFailureRegexp.MatchString(text) {
+			return task, text, true
+		}
+	}
+	return "", "", false
+}
+
+// classifyAnsibleTask returns the category of a failure given the task that failed,
+// formatted as "role : task name", and the line Ansible printed for the failure.
+func classifyAnsibleTask(task, line string) FailureCategory {
+	if containsAny(line, infrastructureErrors) {
+		return FailureInfrastructure
+	}
+
+	role, name, _ := strings.Cut(task, " : ")
+	role = strings.TrimSpace(role)
+	name = strings.ToLower(name)
+	switch {
+	case role == "vitess_build" && (strings.Contains(name, "fetch updated vitess") || strings.Contains(name, "install vitess")):
+		return FailureVitessBuild
+	case containsAny(role, benchmarkToolRoles):
+		return FailureBenchmarkTool
+	}
+	return FailureInfrastructure
+}
+
+func containsAny(s string, substrings []string) bool {
+	s = strings.ToLower(s)
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateReason(reason string) string {
+	if len(reason) <= maxFailureReasonLength {
+		return reason
+	}
+	return strings.ToValidUTF8(reason[:maxFailureReasonLength-3], "") + "..."
+}
@@ -0,0 +1,122 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		logs         string
+		wantCategory FailureCategory
+		wantReason   string
+	}{
+		{
+			name:         "Timeout",
+			err:          fmt.Errorf("ansible-playbook interrupted: %w", context.DeadlineExceeded),
+			logs:         "TASK [macrobench : Run macrobenchmarks] ***\n",
+			wantCategory: FailureTimeout,
+			wantReason:   ErrorExecutionTimeout,
+		},
+		{
+			name: "Unreachable host",
+			err:  errors.New("exit status 4"),
+			logs: "PLAY [all] ***\n" +
+				"TASK [Gathering Facts] ***\n" +
+				"fatal: [10.0.0.1]: UNREACHABLE! => {\"changed\": false, \"msg\": \"Failed to connect to the host via ssh\"}\n",
+			wantCategory: FailureInfrastructure,
+			wantReason:   "TASK [Gathering Facts]: fatal: [10.0.0.1]: UNREACHABLE! => {\"changed\": false, \"msg\": \"Failed to connect to the host via ssh\"}",
+		},
+		{
+			name: "Vitess build",
+			err:  errors.New("exit status 2"),
+			logs: "TASK [vitess_build : Stop existing Vitess to build a new one] ***\n" +
+				"ok: [10.0.0.1]\n" +
+				"TASK [vitess_build : Install Vitess Binaries] ***\n" +
+				"fatal: [10.0.0.1]: FAILED! => {\"msg\": \"go/vt/vtgate/engine/route.go:42: undefined: foo\"}\n" +
+				"TASK [vitess_build : Install Vitess Other Binaries] ***\n",
+			wantCategory: FailureVitessBuild,
+			wantReason:   "TASK [vitess_build : Install Vitess Binaries]: fatal: [10.0.0.1]: FAILED! => {\"msg\": \"go/vt/vtgate/engine/route.go:42: undefined: foo\"}",
+		},
+		{
+			name: "Benchmark tool",
+			err:  errors.New("exit status 2"),
+			logs: "TASK [macrobench : Run macrobenchmarks] ***\n" +
+				"failed: [10.0.0.1] (item=oltp) => {\"rc\": 1}\n",
+			wantCategory: FailureBenchmarkTool,
+			wantReason:   "TASK [macrobench : Run macrobenchmarks]: failed: [10.0.0.1] (item=oltp) => {\"rc\": 1}",
+		},
+		{
+			name: "Host provisioning",
+			err:  errors.New("exit status 2"),
+			logs: "TASK [host : Install packages] ***\n" +
+				"fatal: [10.0.0.1]: FAILED! => {\"msg\": \"apt failed\"}\n",
+			wantCategory: FailureInfrastructure,
+			wantReason:   "TASK [host : Install packages]: fatal: [10.0.0.1]: FAILED! => {\"msg\": \"apt failed\"}",
+		},
+		{
+			name:         "Connection error without logs",
+			err:          errors.New("dial tcp 10.0.0.1:22: connect: connection refused"),
+			wantCategory: FailureInfrastructure,
+			wantReason:   "dial tcp 10.0.0.1:22: connect: connection refused",
+		},
+		{
+			name:         "Unknown",
+			err:          errors.New("prepare error: invalid configuration"),
+			wantCategory: FailureUnknown,
+			wantReason:   "prepare error: invalid configuration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			failure := ClassifyFailure(tt.err, []byte(tt.logs))
+			c.Assert(failure, qt.IsNotNil)
+			c.Assert(failure.Category, qt.Equals, tt.wantCategory)
+			c.Assert(failure.Reason, qt.Equals, tt.wantReason)
+			c.Assert(errors.Is(failure, tt.err), qt.IsTrue)
+		})
+	}
+}
+
+func TestClassifyFailure_AlreadyClassified(t *testing.T) {
+	c := qt.New(t)
+
+	failure := &Failure{Category: FailureVitessBuild, Reason: "build failed", Err: errors.New("exit status 2")}
+	got := ClassifyFailure(fmt.Errorf("execute with timeout error: %w", failure), nil)
+	c.Assert(got, qt.Equals, failure)
+	c.Assert(ClassifyFailure(nil, nil) == nil, qt.IsTrue)
+}
+
+func TestClassifyFailure_LongReason(t *testing.T) {
+	c := qt.New(t)
+
+	failure := ClassifyFailure(errors.New(strings.Repeat("a", 2*maxFailureReasonLength)), nil)
+	c.Assert(failure.Reason, qt.HasLen, maxFailureReasonLength)
+}
@@ -0,0 +1,85 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitessio/arewefastyet/go/storage/influxdb"
+)
+
+const (
+	influxCounterStart = `from(bucket:"%s")
+			|> range(start: 0, stop: now())
+			|> filter(fn:(r) => r._measurement == "%s" and r.exec_uuid == "%s" and r.component == "%s")
+			|> filter(fn: (r) => r._value > 0)
+			|> min()`
+
+	influxCounterEnd = `from(bucket:"%s")
+			|> range(start: 0, stop: now())
+			|> filter(fn:(r) => r._measurement == "%s" and r.exec_uuid == "%s" and r.component == "%s")
+			|> max()`
+)
+
+// influxDBSource reads the counters from InfluxDB, in which each counter
+// is a measurement tagged with exec_uuid and component.
+type influxDBSource struct {
+	client *influxdb.Client
+}
+
+// NewInfluxDBSource returns a MetricsSource reading the counters from the given InfluxDB client.
+func NewInfluxDBSource(client *influxdb.Client) MetricsSource {
+	return &influxDBSource{client: client}
+}
+
+func (s *influxDBSource) Counter(execUUID, component, counter string) (start, end float64, err error) {
+	start, err = getSumFloatValueForQuery(s.client, fmt.Sprintf(influxCounterStart, s.client.Config.Database, counter, execUUID, component))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err = getSumFloatValueForQuery(s.client, fmt.Sprintf(influxCounterEnd, s.client.Config.Database, counter, execUUID, component))
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func (s *influxDBSource) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx)
+}
+
+func (s *influxDBSource) Close() {
+	s.client.Close()
+}
+
+// getSumFloatValueForQuery return the sum of a float value based on the given query, for
+// each row.
+func getSumFloatValueForQuery(client *influxdb.Client, query string) (float64, error) {
+	result, err := client.Select(query)
+	if err != nil {
+		return 0, err
+	}
+
+	res := 0.0
+	for _, value := range result {
+		res += value["_value"].(float64)
+	}
+	return res, nil
+}
@@ -19,35 +19,17 @@
 package metrics
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/vitessio/arewefastyet/go/storage"
-	"github.com/vitessio/arewefastyet/go/storage/influxdb"
 )
 
 const (
-	cpuSecondsPerComponentStart = `from(bucket:"%s")
-			|> range(start: 0, stop: now())
-			|> filter(fn:(r) => r._measurement == "process_cpu_seconds_total" and r.exec_uuid == "%s" and r.component == "%s")
-			|> filter(fn: (r) => r._value > 0)
-			|> min()`
-
-	cpuSecondsPerComponentEnd = `from(bucket:"%s")
-			|> range(start: 0, stop: now())
-			|> filter(fn:(r) => r._measurement == "process_cpu_seconds_total" and r.exec_uuid == "%s" and r.component == "%s")
-			|> max()`
-
-	memAllocBytesPerComponentStart = `from(bucket:"%s")
-			|> range(start: 0, stop: now())
-			|> filter(fn:(r) => r._measurement == "go_memstats_alloc_bytes_total" and r.exec_uuid == "%s" and r.component == "%s")
-			|> filter(fn: (r) => r._value > 0)
-			|> min()`
-
-	memAllocBytesPerComponentEnd = `from(bucket:"%s")
-			|> range(start: 0, stop: now())
-			|> filter(fn:(r) => r._measurement == "go_memstats_alloc_bytes_total" and r.exec_uuid == "%s" and r.component == "%s")
-			|> max()`
+	// counterCPUSeconds is the CPU time consumed by a process, in seconds.
+	counterCPUSeconds = "process_cpu_seconds_total"
+
+	// counterMemAllocBytes is the number of bytes allocated by a Go process, even if freed.
+	counterMemAllocBytes = "go_memstats_alloc_bytes_total"
 )
 
 var (
@@ -84,17 +66,13 @@ type (
 )
 
 // GetExecutionMetrics fetches and computes a single execution's metrics.
-// Metrics are fetched using the given influxdb.Client and execUUID.
-func GetExecutionMetrics(client influxdb.Client, execUUID string, queries int) (ExecutionMetrics, error) {
+// Metrics are fetched using the given MetricsSource and execUUID.
+func GetExecutionMetrics(source MetricsSource, execUUID string, queries int) (ExecutionMetrics, error) {
 	execMetrics := NewExecMetrics()
 
 	for _, component := range components {
 		// CPU time
-		endValue, err := getSumFloatValueForQuery(client, fmt.Sprintf(cpuSecondsPerComponentEnd, client.Config.Database, execUUID, component))
-		if err != nil {
-			return ExecutionMetrics{}, err
-		}
-		startValue, err := getSumFloatValueForQuery(client, fmt.Sprintf(cpuSecondsPerComponentStart, client.Config.Database, execUUID, component))
+		startValue, endValue, err := source.Counter(execUUID, component, counterCPUSeconds)
 		if err != nil {
 			return ExecutionMetrics{}, err
 		}
@@ -102,16 +80,10 @@ func GetExecutionMetrics(client influxdb.Client, execUUID string, queries int) (
 		execMetrics.TotalComponentsCPUTime += execMetrics.ComponentsCPUTime[component]
 
 		// Memory
-		endValue, err = getSumFloatValueForQuery(client, fmt.Sprintf(memAllocBytesPerComponentEnd, client.Config.Database, execUUID, component))
-		if err != nil {
-			return ExecutionMetrics{}, err
-		}
-
-		startValue, err = getSumFloatValueForQuery(client, fmt.Sprintf(memAllocBytesPerComponentStart, client.Config.Database, execUUID, component))
+		startValue, endValue, err = source.Counter(execUUID, component, counterMemAllocBytes)
 		if err != nil {
 			return ExecutionMetrics{}, err
 		}
-
 		execMetrics.ComponentsMemStatsAllocBytes[component] = endValue - startValue
 		execMetrics.TotalComponentsMemStatsAllocBytes += execMetrics.ComponentsMemStatsAllocBytes[component]
 	}
@@ -143,26 +115,21 @@ func NewExecMetrics() ExecutionMetrics {
 	}
 }
 
+// InsertExecutionMetrics inserts all the metrics of the given execution in a single transaction.
 func InsertExecutionMetrics(client storage.SQLClient, execUUID string, execMetrics ExecutionMetrics) error {
-	query := "INSERT INTO metrics(exec_uuid, `name`, `value`) VALUES (?, ?, ?), (?, ?, ?)"
-	args := []interface{}{
-		execUUID, "TotalComponentsCPUTime", execMetrics.TotalComponentsCPUTime,
-		execUUID, "TotalComponentsMemStatsAllocBytes", execMetrics.TotalComponentsMemStatsAllocBytes,
+	rows := [][]interface{}{
+		{execUUID, "TotalComponentsCPUTime", execMetrics.TotalComponentsCPUTime},
+		{execUUID, "TotalComponentsMemStatsAllocBytes", execMetrics.TotalComponentsMemStatsAllocBytes},
 	}
 	for k, v := range execMetrics.ComponentsCPUTime {
-		query += ", (?,?,?)"
-		args = append(args, []interface{}{
-			execUUID, "ComponentsCPUTime." + k, v,
-		}...)
+		rows = append(rows, []interface{}{execUUID, "ComponentsCPUTime." + k, v})
 	}
 	for k, v := range execMetrics.ComponentsMemStatsAllocBytes {
-		query += ", (?,?,?)"
-		args = append(args, []interface{}{
-			execUUID, "ComponentsMemStatsAllocBytes." + k, v,
-		}...)
+		rows = append(rows, []interface{}{execUUID, "ComponentsMemStatsAllocBytes." + k, v})
 	}
-	_, err := client.Write(query, args...)
-	return err
+	return storage.WithTransaction(client, func(tx storage.SQLClient) error {
+		return storage.BulkInsert(tx, "metrics", []string{"exec_uuid", "name", "value"}, rows)
+	})
 }
 
 func GetExecutionMetricsSQL(client storage.SQLClient, execUUID string) (ExecutionMetrics, error) {
@@ -196,18 +163,3 @@ func GetExecutionMetricsSQL(client storage.SQLClient, execUUID string) (Executio
 	}
 	return result, nil
 }
-
-// getSumFloatValueForQuery return the sum of a float value based on the given query, for
-// each row.
-func getSumFloatValueForQuery(client influxdb.Client, query string) (float64, error) {
-	result, err := client.Select(query)
-	if err != nil {
-		return 0, err
-	}
-
-	res := 0.0
-	for _, value := range result {
-		res += value["_value"].(float64)
-	}
-	return res, nil
-}
@@ -0,0 +1,76 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vitessio/arewefastyet/go/storage/prometheus"
+)
+
+const (
+	// The counters of a component are summed over its instances, each of them being a series.
+	// The execution is searched in the samples of the lookback period of the client.
+	promCounterStart = `sum(min_over_time(%s{exec_uuid=%q, component=%q}[%ds]))`
+	promCounterEnd   = `sum(max_over_time(%s{exec_uuid=%q, component=%q}[%ds]))`
+)
+
+// prometheusSource reads the counters from Prometheus, whose scrape configuration
+// adds the exec_uuid and component labels to the metrics of the components.
+type prometheusSource struct {
+	client *prometheus.Client
+}
+
+// NewPrometheusSource returns a MetricsSource reading the counters from the given Prometheus client.
+func NewPrometheusSource(client *prometheus.Client) MetricsSource {
+	return &prometheusSource{client: client}
+}
+
+func (s *prometheusSource) Counter(execUUID, component, counter string) (start, end float64, err error) {
+	lookback := int64(s.client.Config.Lookback.Seconds())
+	start, err = s.query(fmt.Sprintf(promCounterStart, counter, execUUID, component, lookback))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err = s.query(fmt.Sprintf(promCounterEnd, counter, execUUID, component, lookback))
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// query returns the value of a query summing its series, or zero if there is no series.
+func (s *prometheusSource) query(query string) (float64, error) {
+	samples, err := s.client.Query(context.Background(), query)
+	if err != nil {
+		return 0, err
+	}
+	res := 0.0
+	for _, sample := range samples {
+		res += sample.Value
+	}
+	return res, nil
+}
+
+func (s *prometheusSource) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx)
+}
+
+func (s *prometheusSource) Close() {}
@@ -0,0 +1,115 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/storage/influxdb"
+	"github.com/vitessio/arewefastyet/go/storage/prometheus"
+)
+
+const (
+	flagMetricsBackend = "metrics-backend"
+
+	// BackendInfluxDB reads the metrics from InfluxDB, to which Prometheus writes the
+	// metrics it scrapes. It is the default backend.
+	BackendInfluxDB = "influxdb"
+
+	// BackendPrometheus reads the metrics from the HTTP API of Prometheus.
+	BackendPrometheus = "prometheus"
+)
+
+// MetricsSource gives the counters exported by the components of the executions, such as
+// vtgate and vttablet. The samples of the counters are labeled with the UUID of the execution
+// and the name of the component.
+type MetricsSource interface {
+	// Counter returns the value of the given counter, such as process_cpu_seconds_total,
+	// summed over all the instances of a component at the start and at the end of an execution.
+	Counter(execUUID, component, counter string) (start, end float64, err error)
+
+	// Ping checks that the source can be reached.
+	Ping(ctx context.Context) error
+
+	// Close releases the resources used by the source.
+	Close()
+}
+
+// Config selects the backend from which the metrics are read, and defines its configuration.
+type Config struct {
+	Backend    string
+	InfluxDB   influxdb.Config
+	Prometheus prometheus.Config
+}
+
+// Name returns the name of the selected backend, InfluxDB if none is set.
+func (cfg *Config) Name() string {
+	if cfg.Backend == "" {
+		return BackendInfluxDB
+	}
+	return cfg.Backend
+}
+
+// IsValid returns true if the configuration of the selected backend is ready to be used.
+func (cfg *Config) IsValid() bool {
+	switch cfg.Name() {
+	case BackendInfluxDB:
+		return cfg.InfluxDB.IsValid()
+	case BackendPrometheus:
+		return cfg.Prometheus.IsValid()
+	}
+	return false
+}
+
+// NewSource returns a MetricsSource reading from the selected backend.
+func (cfg *Config) NewSource() (MetricsSource, error) {
+	switch cfg.Name() {
+	case BackendInfluxDB:
+		client, err := cfg.InfluxDB.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewInfluxDBSource(client), nil
+	case BackendPrometheus:
+		client, err := cfg.Prometheus.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewPrometheusSource(client), nil
+	}
+	return nil, fmt.Errorf("unknown metrics backend %q, expected %s or %s", cfg.Backend, BackendInfluxDB, BackendPrometheus)
+}
+
+func (cfg *Config) AddToViper(v *viper.Viper) {
+	_ = v.UnmarshalKey(flagMetricsBackend, &cfg.Backend)
+	cfg.InfluxDB.AddToViper(v)
+	cfg.Prometheus.AddToViper(v)
+}
+
+// AddToCommand adds Config and the configuration of every backend to the given cobra.Command.
+func (cfg *Config) AddToCommand(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cfg.Backend, flagMetricsBackend, BackendInfluxDB, fmt.Sprintf("Backend from which the metrics of the executions are read, either %s or %s.", BackendInfluxDB, BackendPrometheus))
+	_ = viper.BindPFlag(flagMetricsBackend, cmd.Flags().Lookup(flagMetricsBackend))
+
+	cfg.InfluxDB.AddToCommand(cmd)
+	cfg.Prometheus.AddToCommand(cmd)
+}
@@ -0,0 +1,160 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage/influxdb"
+	"github.com/vitessio/arewefastyet/go/storage/prometheus"
+)
+
+// counterValues are the values of the counters of the execution "exec" at its start and at its
+// end, summed over the instances of each component. They are indexed by counter and component.
+var counterValues = map[string]map[string][2]float64{
+	counterCPUSeconds:    {"vtgate": {10, 30}, "vttablet": {5, 45}},
+	counterMemAllocBytes: {"vtgate": {1000, 5000}, "vttablet": {2000, 4000}},
+}
+
+// wantMetrics are the metrics of the execution "exec" with 10 queries.
+var wantMetrics = ExecutionMetrics{
+	TotalComponentsCPUTime:            6,
+	ComponentsCPUTime:                 map[string]float64{"vtgate": 2, "vttablet": 4},
+	TotalComponentsMemStatsAllocBytes: 600,
+	ComponentsMemStatsAllocBytes:      map[string]float64{"vtgate": 400, "vttablet": 200},
+}
+
+// counterValue returns the value of a counter for the fake servers, the values of the counters
+// are split in two instances to check that the sources sum them.
+func counterValue(counter, execUUID, component string, end bool) []float64 {
+	values, ok := counterValues[counter][component]
+	if !ok || execUUID != "exec" {
+		return nil
+	}
+	value := values[0]
+	if end {
+		value = values[1]
+	}
+	return []float64{value / 4, value * 3 / 4}
+}
+
+var promQueryRegexp = regexp.MustCompile(`^sum\((min|max)_over_time\((\w+)\{exec_uuid="([^"]*)", component="([^"]*)"\}\[(\d+)s\]\)\)$`)
+
+func TestPrometheusSource(t *testing.T) {
+	c := qt.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m := promQueryRegexp.FindStringSubmatch(r.FormValue("query"))
+		if m == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":"unexpected query %s"}`, r.FormValue("query"))
+			return
+		}
+		c.Check(m[5], qt.Equals, "86400")
+
+		var result []map[string]interface{}
+		for i, value := range counterValue(m[2], m[3], m[4], m[1] == "max") {
+			result = append(result, map[string]interface{}{
+				"metric": map[string]string{"instance": fmt.Sprint(i)},
+				"value":  []interface{}{1700000000, fmt.Sprint(value)},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   map[string]interface{}{"resultType": "vector", "result": result},
+		})
+	}))
+	defer server.Close()
+
+	cfg := Config{Backend: BackendPrometheus, Prometheus: prometheus.Config{URL: server.URL, Lookback: 24 * time.Hour}}
+	c.Assert(cfg.IsValid(), qt.IsTrue)
+	source, err := cfg.NewSource()
+	c.Assert(err, qt.IsNil)
+	defer source.Close()
+
+	got, err := GetExecutionMetrics(source, "exec", 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, wantMetrics)
+
+	// An execution without samples has no metrics.
+	got, err = GetExecutionMetrics(source, "unknown", 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, NewExecMetrics())
+}
+
+var fluxQueryRegexp = regexp.MustCompile(`(?s)^from\(bucket:"benchmarks"\).*r\._measurement == "(\w+)" and r\.exec_uuid == "([^"]*)" and r\.component == "([^"]*)".*\|> (min|max)\(\)$`)
+
+func TestInfluxDBSource(t *testing.T) {
+	c := qt.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, qt.Equals, "/api/v2/query")
+		var body struct {
+			Query string `json:"query"`
+		}
+		c.Check(json.NewDecoder(r.Body).Decode(&body), qt.IsNil)
+		m := fluxQueryRegexp.FindStringSubmatch(body.Query)
+		if m == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"code":"invalid","message":"unexpected query %q"}`, body.Query)
+			return
+		}
+
+		// Each instance of the component is a table of the annotated CSV of the response.
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		_, _ = fmt.Fprint(w, "#datatype,string,long,string,string,string,double\n#group,false,false,true,true,true,false\n#default,_result,,,,,\n,result,table,_measurement,component,exec_uuid,_value\n")
+		for i, value := range counterValue(m[1], m[2], m[3], m[4] == "max") {
+			_, _ = fmt.Fprintf(w, ",,%d,%s,%s,%s,%v\n", i, m[1], m[3], m[2], value)
+		}
+		_, _ = fmt.Fprint(w, "\n")
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	c.Assert(err, qt.IsNil)
+	cfg := Config{InfluxDB: influxdb.Config{Host: serverURL.Hostname(), Port: serverURL.Port(), Database: "benchmarks"}}
+	c.Assert(cfg.Name(), qt.Equals, BackendInfluxDB)
+	source, err := cfg.NewSource()
+	c.Assert(err, qt.IsNil)
+	defer source.Close()
+
+	got, err := GetExecutionMetrics(source, "exec", 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, wantMetrics)
+}
+
+func TestConfig_NewSource(t *testing.T) {
+	c := qt.New(t)
+	cfg := Config{Backend: "graphite"}
+	c.Assert(cfg.IsValid(), qt.IsFalse)
+	_, err := cfg.NewSource()
+	c.Assert(err, qt.ErrorMatches, `unknown metrics backend "graphite", expected influxdb or prometheus`)
+
+	cfg = Config{Backend: BackendPrometheus}
+	c.Assert(cfg.IsValid(), qt.IsFalse)
+	_, err = cfg.NewSource()
+	c.Assert(err, qt.ErrorMatches, prometheus.ErrorInvalidConfiguration)
+}
@@ -26,7 +26,7 @@ import (
 )
 
 const (
-	execDir      = "./exec/"
+	execDir    = "./exec/"
 	ansibleDir = "./ansible"
 )
 
@@ -86,4 +86,11 @@ func (e *Exec) prepareDirectories() error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// OutputFiles returns the paths of the files containing the standard output and the standard
+// error of the execution with the given UUID, root is the root directory of the executions.
+func OutputFiles(root string, id uuid.UUID) (stdout, stderr string) {
+	dirPath := path.Join(root, execDir, id.String())
+	return path.Join(dirPath, stdoutFile), path.Join(dirPath, stderrFile)
+}
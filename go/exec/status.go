@@ -19,6 +19,8 @@
 package exec
 
 import (
+	"time"
+
 	"github.com/vitessio/arewefastyet/go/storage"
 )
 
@@ -27,6 +29,7 @@ const (
 	StatusStarted  = "started"
 	StatusFailed   = "failed"
 	StatusFinished = "finished"
+	StatusCanceled = "canceled"
 )
 
 type BenchmarkStats struct {
@@ -38,11 +41,12 @@ type BenchmarkStats struct {
 }
 
 func GetBenchmarkStats(client storage.SQLClient) (BenchmarkStats, error) {
+	dialect := storage.DialectOf(client)
 	rows, err := client.Read(`SELECT
 			(SELECT COUNT(uuid) FROM execution) AS count_status,
 			(SELECT COUNT(DISTINCT git_ref) FROM execution) AS count_commits,
-			(SELECT COUNT(*) FROM execution WHERE started_at >= DATE_SUB(CURDATE(), INTERVAL 30 DAY)) AS count_all,
-			(SELECT IFNULL(AVG(TIMESTAMPDIFF(MINUTE, started_at, finished_at)), 0) AS avg_duration_minutes FROM execution WHERE profile_binary IS NULL AND started_at IS NOT NULL AND finished_at IS NOT NULL AND status NOT IN ('failed', 'started') ORDER BY avg_duration_minutes ASC) AS avg_duration_minutes
+			(SELECT COUNT(*) FROM execution WHERE started_at >= ` + dialect.DaysAgo(30) + `) AS count_all,
+			(SELECT IFNULL(AVG(` + dialect.TimestampDiff("MINUTE", "started_at", "finished_at") + `), 0) AS avg_duration_minutes FROM execution WHERE profile_binary IS NULL AND started_at IS NOT NULL AND finished_at IS NOT NULL AND status NOT IN ('failed', 'started', 'canceled') ORDER BY avg_duration_minutes ASC) AS avg_duration_minutes
 		FROM 
 			execution
 		LIMIT 1;`)
@@ -68,11 +72,11 @@ func GetBenchmarkStats(client storage.SQLClient) (BenchmarkStats, error) {
 		FROM
 			execution
 		WHERE
-			started_at >= DATE_SUB(CURDATE(), INTERVAL 7 DAY) 
+			started_at >= ` + dialect.DaysAgo(7) + ` 
 		GROUP BY
-			DATE_FORMAT(started_at, '%Y%m%d')
+			` + dialect.FormatDay("started_at") + `
 		ORDER BY
-			DATE_FORMAT(started_at, '%Y%m%d') ASC
+			` + dialect.FormatDay("started_at") + ` ASC
 		LIMIT 7;`)
 
 	if err != nil {
@@ -90,3 +94,33 @@ func GetBenchmarkStats(client storage.SQLClient) (BenchmarkStats, error) {
 
 	return res, nil
 }
+
+// GetAverageDurationPerWorkload returns the average duration of the finished executions
+// of each workload over the last 30 days. The workload names are lowercase.
+func GetAverageDurationPerWorkload(client storage.SQLClient) (map[string]time.Duration, error) {
+	dialect := storage.DialectOf(client)
+	rows, err := client.Read(`SELECT
+			LOWER(workload), IFNULL(AVG(`+dialect.TimestampDiff("SECOND", "started_at", "finished_at")+`), 0)
+		FROM
+			execution
+		WHERE
+			profile_binary IS NULL AND started_at IS NOT NULL AND finished_at IS NOT NULL AND status = ? AND started_at >= `+dialect.DaysAgo(30)+`
+		GROUP BY
+			LOWER(workload)`, StatusFinished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[string]time.Duration{}
+	for rows.Next() {
+		var workload string
+		var seconds float64
+		err := rows.Scan(&workload, &seconds)
+		if err != nil {
+			return nil, err
+		}
+		res[workload] = time.Duration(seconds * float64(time.Second))
+	}
+	return res, nil
+}
@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func newTestDatabase(c *qt.C) *sqlite.Client {
+	cfg := sqlite.Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}
+	client, err := cfg.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestGetBenchmarkStats(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	now := time.Now()
+	for _, e := range []struct {
+		uuid, gitRef, status, workload string
+		startedAt, finishedAt          time.Time
+	}{
+		{uuid: "1", gitRef: "a", status: StatusFinished, workload: "OLTP", startedAt: now.Add(-time.Hour), finishedAt: now.Add(-30 * time.Minute)},
+		{uuid: "2", gitRef: "a", status: StatusFinished, workload: "TPCC", startedAt: now.Add(-2 * time.Hour), finishedAt: now.Add(-time.Hour)},
+		{uuid: "3", gitRef: "b", status: StatusFailed, workload: "OLTP", startedAt: now.Add(-time.Hour), finishedAt: now},
+		{uuid: "4", gitRef: "c", status: StatusFinished, workload: "OLTP", startedAt: now.Add(-60 * 24 * time.Hour), finishedAt: now.Add(-60 * 24 * time.Hour).Add(time.Hour)},
+	} {
+		_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, workload, started_at, finished_at) VALUES(?, ?, ?, ?, ?, ?, ?)",
+			e.uuid, e.status, SourceCron, e.gitRef, e.workload, e.startedAt, e.finishedAt)
+		c.Assert(err, qt.IsNil)
+	}
+
+	stats, err := GetBenchmarkStats(client)
+	c.Assert(err, qt.IsNil)
+	c.Assert(stats.Total, qt.Equals, 4)
+	c.Assert(stats.Commits, qt.Equals, 3)
+	c.Assert(stats.Last30Days, qt.Equals, 3)
+	c.Assert(stats.AvgDuration, qt.Equals, float64(50))
+	c.Assert(stats.Last7Days, qt.Not(qt.HasLen), 0)
+
+	durations, err := GetAverageDurationPerWorkload(client)
+	c.Assert(err, qt.IsNil)
+	c.Assert(durations, qt.DeepEquals, map[string]time.Duration{
+		"oltp": 30 * time.Minute,
+		"tpcc": time.Hour,
+	})
+}
+
+func TestGetExecutionsWithoutHeartbeat(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	now := time.Now()
+	for _, e := range []struct {
+		uuid, status string
+		heartbeatAt  interface{}
+	}{
+		{uuid: "recent", status: StatusStarted, heartbeatAt: now.Add(-time.Minute)},
+		{uuid: "stale", status: StatusStarted, heartbeatAt: now.Add(-time.Hour)},
+		{uuid: "never", status: StatusStarted, heartbeatAt: nil},
+		{uuid: "finished", status: StatusFinished, heartbeatAt: now.Add(-time.Hour)},
+	} {
+		_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, heartbeat_at) VALUES(?, ?, ?, ?, ?)",
+			e.uuid, e.status, SourceCron, "abcd", e.heartbeatAt)
+		c.Assert(err, qt.IsNil)
+	}
+
+	uuids, err := GetExecutionsWithoutHeartbeat(client, 10*time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(uuids, qt.ContentEquals, []string{"stale", "never"})
+
+	c.Assert(MarkAsFailed(client, "stale", FailureInfrastructure, "orphaned"), qt.IsNil)
+	uuids, err = GetExecutionsWithoutHeartbeat(client, 10*time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(uuids, qt.DeepEquals, []string{"never"})
+}
+
+func TestMarkAsCanceled(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	for _, e := range []struct{ uuid, status string }{
+		{uuid: "created", status: StatusCreated},
+		{uuid: "started", status: StatusStarted},
+		{uuid: "failed", status: StatusFailed},
+	} {
+		_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref) VALUES(?, ?, ?, ?)", e.uuid, e.status, SourceCron, "abcd")
+		c.Assert(err, qt.IsNil)
+		c.Assert(MarkAsCanceled(client, e.uuid), qt.IsNil)
+	}
+
+	// Executions that already reached a terminal status keep it.
+	for uuid, want := range map[string]string{"created": StatusCanceled, "started": StatusCanceled, "failed": StatusFailed} {
+		rows, err := client.Read("SELECT status FROM execution WHERE uuid = ?", uuid)
+		c.Assert(err, qt.IsNil)
+		c.Assert(rows.Next(), qt.IsTrue)
+		var status string
+		c.Assert(rows.Scan(&status), qt.IsNil)
+		c.Assert(rows.Close(), qt.IsNil)
+		c.Assert(status, qt.Equals, want, qt.Commentf("execution %s", uuid))
+	}
+}
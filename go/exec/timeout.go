@@ -0,0 +1,228 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	keyTimeout          = "exec-timeout"
+	keyTimeoutProvision = "exec-timeout-provision"
+	keyTimeoutBuild     = "exec-timeout-build"
+	keyTimeoutRun       = "exec-timeout-run"
+
+	defaultTimeout      = 1 * time.Hour
+	defaultTimeoutMicro = 4 * time.Hour
+)
+
+// Phase is a step of an execution, each phase can have its own time budget.
+type Phase string
+
+const (
+	// PhaseProvision sets up the cluster used by the benchmark: hosts, etcd, vtctld, vtgate, vttablet...
+	PhaseProvision Phase = "provision"
+
+	// PhaseBuild builds Vitess and installs the benchmark tools.
+	PhaseBuild Phase = "build"
+
+	// PhaseRun runs the benchmark itself.
+	PhaseRun Phase = "run"
+)
+
+var (
+	buildRoles = []string{"vitess_build", "sysbench"}
+	runRoles   = []string{"macrobench", "microbench", "profile", "arewefastyet"}
+)
+
+// Timeouts are the time budgets of an execution. Total is the maximum duration of the
+// whole execution, Phases optionally limits the time spent in each phase.
+type Timeouts struct {
+	Total  time.Duration
+	Phases map[Phase]time.Duration
+}
+
+// DefaultTimeout returns the timeout used by the given workload when its configuration
+// does not declare one.
+func DefaultTimeout(workload string) time.Duration {
+	if workload == "micro" {
+		return defaultTimeoutMicro
+	}
+	return defaultTimeout
+}
+
+// TimeoutsFromConfig reads the timeouts declared in the configuration of a benchmark:
+//
+//	exec-timeout: 2h
+//	exec-timeout-provision: 20m
+//	exec-timeout-build: 30m
+//	exec-timeout-run: 1h
+//
+// The total timeout defaults to DefaultTimeout, phases without budget are not limited.
+func TimeoutsFromConfig(v *viper.Viper, workload string) (Timeouts, error) {
+	timeouts := Timeouts{Total: DefaultTimeout(workload)}
+	keys := []struct {
+		key   string
+		phase Phase
+	}{
+		{key: keyTimeout},
+		{key: keyTimeoutProvision, phase: PhaseProvision},
+		{key: keyTimeoutBuild, phase: PhaseBuild},
+		{key: keyTimeoutRun, phase: PhaseRun},
+	}
+	for _, k := range keys {
+		raw := v.GetString(k.key)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return Timeouts{}, fmt.Errorf("invalid %s: %s", k.key, raw)
+		}
+		if k.phase == "" {
+			timeouts.Total = d
+			continue
+		}
+		if timeouts.Phases == nil {
+			timeouts.Phases = map[Phase]time.Duration{}
+		}
+		timeouts.Phases[k.phase] = d
+	}
+	for phase, budget := range timeouts.Phases {
+		if budget > timeouts.Total {
+			return Timeouts{}, fmt.Errorf("the budget of the %s phase (%s) exceeds the timeout of the execution (%s)", phase, budget, timeouts.Total)
+		}
+	}
+	return timeouts, nil
+}
+
+// phaseOfAnsibleTask returns the phase of the given Ansible task, formatted as "role : task name".
+func phaseOfAnsibleTask(task string) Phase {
+	role, _, found := strings.Cut(task, " : ")
+	if !found {
+		return PhaseProvision
+	}
+	role = strings.TrimSpace(role)
+	switch {
+	case containsAny(role, buildRoles):
+		return PhaseBuild
+	case containsAny(role, runRoles):
+		return PhaseRun
+	}
+	return PhaseProvision
+}
+
+// PhaseTimeoutError is the cause of the cancellation of an execution that spent
+// more time than its budget in a phase.
+type PhaseTimeoutError struct {
+	Phase  Phase
+	Budget time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s: the %s phase exceeded its budget of %s", ErrorExecutionTimeout, e.Phase, e.Budget)
+}
+
+// phaseBudget follows the output of Ansible to know in which phase the execution is,
+// and cancels the execution once it spent more time than the budget of a phase. The
+// time spent in a phase is cumulated, even if the execution goes back and forth.
+type phaseBudget struct {
+	mu      sync.Mutex
+	budgets map[Phase]time.Duration
+	cancel  context.CancelCauseFunc
+
+	phase   Phase
+	since   time.Time
+	spent   map[Phase]time.Duration
+	timer   *time.Timer
+	partial []byte
+}
+
+func newPhaseBudget(budgets map[Phase]time.Duration, cancel context.CancelCauseFunc) *phaseBudget {
+	return &phaseBudget{
+		budgets: budgets,
+		cancel:  cancel,
+		spent:   map[Phase]time.Duration{},
+	}
+}
+
+// Write reads the task headers printed by Ansible, it never fails.
+func (b *phaseBudget) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.partial = append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(b.partial[:i]))
+		b.partial = b.partial[i+1:]
+		if match := ansibleTaskRegexp.FindStringSubmatch(line); match != nil {
+			b.enter(phaseOfAnsibleTask(match[1]), time.Now())
+		}
+	}
+	return len(p), nil
+}
+
+// enter switches to the given phase, the caller must hold the mutex.
+func (b *phaseBudget) enter(phase Phase, now time.Time) {
+	if phase == b.phase {
+		return
+	}
+	if b.phase != "" {
+		b.spent[b.phase] += now.Sub(b.since)
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.phase = phase
+	b.since = now
+
+	budget, ok := b.budgets[phase]
+	if !ok {
+		return
+	}
+	remaining := budget - b.spent[phase]
+	if remaining < 0 {
+		remaining = 0
+	}
+	b.timer = time.AfterFunc(remaining, func() {
+		b.cancel(&PhaseTimeoutError{Phase: phase, Budget: budget})
+	})
+}
+
+// stop stops following the phases of the execution.
+func (b *phaseBudget) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
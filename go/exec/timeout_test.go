@@ -0,0 +1,113 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/viper"
+)
+
+func TestTimeoutsFromConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload string
+		config   map[string]interface{}
+		want     Timeouts
+		wantErr  string
+	}{
+		{name: "Default", workload: "oltp", want: Timeouts{Total: time.Hour}},
+		{name: "Default micro", workload: "micro", want: Timeouts{Total: 4 * time.Hour}},
+		{name: "Total", workload: "tpcc", config: map[string]interface{}{"exec-timeout": "2h"}, want: Timeouts{Total: 2 * time.Hour}},
+		{
+			name:     "Phases",
+			workload: "oltp-readonly",
+			config:   map[string]interface{}{"exec-timeout": "90m", "exec-timeout-build": "30m", "exec-timeout-run": "15m"},
+			want:     Timeouts{Total: 90 * time.Minute, Phases: map[Phase]time.Duration{PhaseBuild: 30 * time.Minute, PhaseRun: 15 * time.Minute}},
+		},
+		{name: "Invalid duration", workload: "oltp", config: map[string]interface{}{"exec-timeout": "one hour"}, wantErr: "invalid exec-timeout: one hour"},
+		{name: "Negative duration", workload: "oltp", config: map[string]interface{}{"exec-timeout-run": "-1m"}, wantErr: "invalid exec-timeout-run: -1m"},
+		{name: "Phase longer than the total", workload: "oltp", config: map[string]interface{}{"exec-timeout-provision": "2h"}, wantErr: "the budget of the provision phase \\(2h0m0s\\) exceeds the timeout of the execution \\(1h0m0s\\)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			v := viper.New()
+			for key, value := range tt.config {
+				v.Set(key, value)
+			}
+			got, err := TimeoutsFromConfig(v, tt.workload)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestPhaseOfAnsibleTask(t *testing.T) {
+	tests := []struct {
+		task string
+		want Phase
+	}{
+		{task: "Gathering Facts", want: PhaseProvision},
+		{task: "vttablet : Start vttablet", want: PhaseProvision},
+		{task: "vitess_build : Install Vitess Binaries", want: PhaseBuild},
+		{task: "sysbench : Install sysbench", want: PhaseBuild},
+		{task: "macrobench : Run Macrobench", want: PhaseRun},
+		{task: "profile : Start profiling", want: PhaseRun},
+	}
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			qt.Assert(t, phaseOfAnsibleTask(tt.task), qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestPhaseBudget(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	budget := newPhaseBudget(map[Phase]time.Duration{PhaseBuild: 50 * time.Millisecond}, cancel)
+	defer budget.stop()
+
+	// The provision phase has no budget and the output of a task can be split in several writes.
+	_, _ = fmt.Fprint(budget, "PLAY [all] ****\n\nTASK [vttablet : Start vttablet] ****\nok: [10.0.0.1]\nTASK [vitess_build ")
+	time.Sleep(100 * time.Millisecond)
+	c.Assert(ctx.Err(), qt.IsNil)
+
+	_, _ = fmt.Fprint(budget, ": Install Vitess Binaries] ****\n")
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		c.Fatal("the build phase exceeded its budget but the execution was not canceled")
+	}
+	var phaseErr *PhaseTimeoutError
+	c.Assert(errors.As(context.Cause(ctx), &phaseErr), qt.IsTrue)
+	c.Assert(phaseErr.Phase, qt.Equals, PhaseBuild)
+	c.Assert(ClassifyFailure(phaseErr, nil).Category, qt.Equals, FailureTimeout)
+}
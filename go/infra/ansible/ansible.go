@@ -25,7 +25,6 @@ import (
 	"os"
 	"path"
 
-	"github.com/apenella/go-ansible/pkg/execute"
 	"github.com/apenella/go-ansible/pkg/options"
 	"github.com/apenella/go-ansible/pkg/playbook"
 	"github.com/otiai10/copy"
@@ -89,7 +88,9 @@ func applyRootToFiles(root string, file *string) {
 	}
 }
 
-func Run(c *Config) error {
+// Run executes the playbook of the given configuration. The Ansible process
+// tree is stopped if the given context is canceled before the playbook ends.
+func Run(ctx context.Context, c *Config) error {
 	applyRootToFiles(c.RootDir, &c.PlaybookFile)
 	applyRootToFiles(c.RootDir, &c.InventoryFile)
 
@@ -112,14 +113,13 @@ func Run(c *Config) error {
 		ConnectionOptions:          ansiblePlaybookConnectionOptions,
 		PrivilegeEscalationOptions: ansiblePlaybookPrivilegeEscalationOptions,
 		Options:                    ansiblePlaybookOptions,
-		Exec: execute.NewDefaultExecute(
-			execute.WithShowDuration(),
-			execute.WithWrite(c.stdout),
-			execute.WithWriteError(c.stderr),
-		),
+		Exec: &processGroupExecute{
+			stdout: c.stdout,
+			stderr: c.stderr,
+		},
 	}
 
-	err := plb.Run(context.TODO())
+	err := plb.Run(ctx)
 	if err != nil {
 		return err
 	}
@@ -147,6 +147,11 @@ func (c *Config) SetStderr(stderr *os.File) {
 	c.stderr = stderr
 }
 
+// SetStdoutWriter sets the writer receiving the standard output of Ansible.
+func (c *Config) SetStdoutWriter(stdout io.Writer) {
+	c.stdout = stdout
+}
+
 func (c *Config) SetOutputs(stdout, stderr *os.File) {
 	c.stdout = stdout
 	c.stderr = stderr
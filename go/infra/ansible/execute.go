@@ -0,0 +1,91 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package ansible
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/apenella/go-ansible/pkg/execute"
+	"github.com/apenella/go-ansible/pkg/stdoutcallback"
+)
+
+// killGracePeriod is the time given to the Ansible process tree to stop
+// after being interrupted, before it gets killed.
+const killGracePeriod = 10 * time.Second
+
+// processGroupExecute is an execute.Executor that runs Ansible in its own process group.
+// Contrary to go-ansible's DefaultExecute, which only kills the ansible-playbook process,
+// canceling the context stops the whole process tree, including the SSH connections and
+// the commands started by Ansible.
+type processGroupExecute struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var _ execute.Executor = (*processGroupExecute)(nil)
+
+func (e *processGroupExecute) Execute(ctx context.Context, command []string, _ stdoutcallback.StdoutCallbackResultsFunc, _ ...execute.ExecuteOptions) error {
+	stdout, stderr := e.stdout, e.stderr
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = killGracePeriod
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not start %s: %w", command[0], err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		_, _ = fmt.Fprintf(stdout, "Duration: %s\n", time.Since(start))
+		if err != nil {
+			return fmt.Errorf("%s failed: %w", command[0], err)
+		}
+		return nil
+	case <-ctx.Done():
+		interruptProcessGroup(cmd)
+		select {
+		case <-done:
+		case <-time.After(killGracePeriod):
+			killProcessGroup(cmd)
+			<-done
+		}
+		_, _ = fmt.Fprintf(stdout, "Interrupted after: %s (%v)\n", time.Since(start), ctx.Err())
+		return fmt.Errorf("%s interrupted: %w", command[0], ctx.Err())
+	}
+}
@@ -0,0 +1,38 @@
+//go:build !unix
+
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package ansible
+
+import (
+	"os/exec"
+)
+
+// Process groups are not available on this platform, only the
+// ansible-playbook process itself is stopped.
+
+func setProcessGroup(_ *exec.Cmd) {}
+
+func interruptProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,96 @@
+//go:build unix
+
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package ansible
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestProcessGroupExecute_Execute(t *testing.T) {
+	c := qt.New(t)
+
+	var stdout bytes.Buffer
+	e := &processGroupExecute{stdout: &stdout}
+	err := e.Execute(context.Background(), []string{"sh", "-c", "echo hello"}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(stdout.String(), qt.Contains, "hello")
+
+	err = e.Execute(context.Background(), []string{"sh", "-c", "exit 2"}, nil)
+	c.Assert(err, qt.IsNotNil)
+}
+
+func TestProcessGroupExecute_ExecuteCanceled(t *testing.T) {
+	c := qt.New(t)
+
+	// The shell starts a child process in the background and writes its PID to a file,
+	// the child must be stopped along with its parent when the context is canceled.
+	pidFile := path.Join(c.TempDir(), "child.pid")
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	e := &processGroupExecute{stdout: &stdout}
+	start := time.Now()
+	err := e.Execute(ctx, []string{"sh", "-c", "sleep 60 & echo $! > " + pidFile + "; wait"}, nil)
+	c.Assert(errors.Is(err, context.DeadlineExceeded), qt.IsTrue)
+	c.Assert(time.Since(start) < killGracePeriod, qt.IsTrue)
+
+	rawPID, err := os.ReadFile(pidFile)
+	c.Assert(err, qt.IsNil)
+	var pid int
+	_, err = fmt.Sscan(string(rawPID), &pid)
+	c.Assert(err, qt.IsNil)
+
+	// The child may take a moment to handle the signal.
+	stopped := processStopped(pid)
+	for i := 0; i < 20 && !stopped; i++ {
+		time.Sleep(100 * time.Millisecond)
+		stopped = processStopped(pid)
+	}
+	c.Assert(stopped, qt.IsTrue)
+}
+
+// processStopped returns true if the given process does not exist anymore, or if it
+// is a zombie waiting to be reaped by its new parent.
+func processStopped(pid int) bool {
+	// Signal 0 only checks if the process exists.
+	if syscall.Kill(pid, 0) == syscall.ESRCH {
+		return true
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	// The state of the process is the field following the command name, which is between parenthesis.
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	return len(fields) > 0 && fields[0] == "Z"
+}
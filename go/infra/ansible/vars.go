@@ -83,6 +83,10 @@ const (
 	// KeyProfileMode sets which mode of pprof we want to use.
 	KeyProfileMode = "vitess_profile_mode"
 
+	// KeyArtifactsDir is the local directory in which the files produced by the
+	// execution, such as profiles, are fetched to be collected as artifacts.
+	KeyArtifactsDir = "arewefastyet_artifacts_dir"
+
 	// KeyExtraFlagsVTGate represents the list of VTGate flag that will be passed down
 	// to VTGate during startup. The flags are represented as follows in the string:
 	// 		"--flag1 --flag2"
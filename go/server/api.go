@@ -43,26 +43,45 @@ type ErrorAPI struct {
 }
 
 type ExecutionQueue struct {
-	Source        string `json:"source"`
-	GitRef        string `json:"git_ref"`
-	Workload      string `json:"workload"`
-	PullNb        int    `json:"pull_nb"`
-	ProfileBinary string `json:"profile_binary"`
-	ProfileMode   string `json:"profile_mode"`
+	Source           string     `json:"source"`
+	GitRef           string     `json:"git_ref"`
+	Workload         string     `json:"workload"`
+	PullNb           int        `json:"pull_nb"`
+	ProfileBinary    string     `json:"profile_binary"`
+	ProfileMode      string     `json:"profile_mode"`
+	Position         int        `json:"position"`
+	Priority         float64    `json:"priority"`
+	AddedAt          *time.Time `json:"added_at"`
+	EstimatedStartAt *time.Time `json:"estimated_start_at"`
+
+	// Failures counts the previous failures of this element per category, and RetryAt
+	// is the time before which a failed element will not be executed again.
+	Failures map[exec.FailureCategory]int `json:"failures,omitempty"`
+	RetryAt  *time.Time                   `json:"retry_at,omitempty"`
+}
+
+// WorkloadTimeouts are the time budgets of the executions of a workload, formatted as
+// durations (i.e. "1h30m0s"). Phases only lists the phases that have a budget.
+type WorkloadTimeouts struct {
+	Workload string                `json:"workload"`
+	Timeout  string                `json:"timeout"`
+	Phases   map[exec.Phase]string `json:"phases,omitempty"`
 }
 
 type RecentExecutions struct {
-	UUID          string     `json:"uuid"`
-	Source        string     `json:"source"`
-	GitRef        string     `json:"git_ref"`
-	Status        string     `json:"status"`
-	Workload      string     `json:"workload"`
-	PullNb        int        `json:"pull_nb"`
-	GolangVersion string     `json:"golang_version"`
-	StartedAt     *time.Time `json:"started_at"`
-	FinishedAt    *time.Time `json:"finished_at"`
-	ProfileBinary string     `json:"profile_binary"`
-	ProfileMode   string     `json:"profile_mode"`
+	UUID            string               `json:"uuid"`
+	Source          string               `json:"source"`
+	GitRef          string               `json:"git_ref"`
+	Status          string               `json:"status"`
+	Workload        string               `json:"workload"`
+	PullNb          int                  `json:"pull_nb"`
+	GolangVersion   string               `json:"golang_version"`
+	StartedAt       *time.Time           `json:"started_at"`
+	FinishedAt      *time.Time           `json:"finished_at"`
+	ProfileBinary   string               `json:"profile_binary"`
+	ProfileMode     string               `json:"profile_mode"`
+	FailureCategory exec.FailureCategory `json:"failure_category"`
+	FailureReason   string               `json:"failure_reason"`
 }
 
 type ExecutionMetadatas struct {
@@ -85,8 +104,29 @@ func (s *Server) getWorkloadList(c *gin.Context) {
 	c.JSON(http.StatusOK, s.workloads)
 }
 
+func (s *Server) getWorkloadTimeouts(c *gin.Context) {
+	response := make([]WorkloadTimeouts, 0, len(s.benchmarkConfig))
+	for workload, config := range s.benchmarkConfig {
+		timeouts := WorkloadTimeouts{
+			Workload: workload,
+			Timeout:  config.timeouts.Total.String(),
+		}
+		for phase, budget := range config.timeouts.Phases {
+			if timeouts.Phases == nil {
+				timeouts.Phases = map[exec.Phase]string{}
+			}
+			timeouts.Phases[phase] = budget.String()
+		}
+		response = append(response, timeouts)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Workload < response[j].Workload
+	})
+	c.JSON(http.StatusOK, response)
+}
+
 func (s *Server) getRecentExecutions(c *gin.Context) {
-	execs, err := exec.GetRecentExecutions(s.dbClient)
+	execs, err := exec.GetRecentExecutions(s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -97,17 +137,19 @@ func (s *Server) getRecentExecutions(c *gin.Context) {
 	}
 	for _, e := range execs {
 		response.Executions = append(response.Executions, RecentExecutions{
-			UUID:          e.RawUUID,
-			Source:        e.Source,
-			GitRef:        e.GitRef,
-			Status:        e.Status,
-			Workload:      e.Workload,
-			PullNb:        e.PullNB,
-			GolangVersion: e.GolangVersion,
-			StartedAt:     e.StartedAt,
-			FinishedAt:    e.FinishedAt,
-			ProfileBinary: e.ProfileInformation.Binary,
-			ProfileMode:   e.ProfileInformation.Mode,
+			UUID:            e.RawUUID,
+			Source:          e.Source,
+			GitRef:          e.GitRef,
+			Status:          e.Status,
+			Workload:        e.Workload,
+			PullNb:          e.PullNB,
+			GolangVersion:   e.GolangVersion,
+			StartedAt:       e.StartedAt,
+			FinishedAt:      e.FinishedAt,
+			ProfileBinary:   e.ProfileInformation.Binary,
+			ProfileMode:     e.ProfileInformation.Mode,
+			FailureCategory: e.FailureCategory,
+			FailureReason:   e.FailureReason,
 		})
 		if !slices.Contains(response.Workloads, e.Workload) {
 			response.Workloads = append(response.Workloads, e.Workload)
@@ -123,25 +165,58 @@ func (s *Server) getRecentExecutions(c *gin.Context) {
 }
 
 func (s *Server) getExecutionsQueue(c *gin.Context) {
+	// The estimation still works without the history of durations, we just use a default duration.
+	durations, err := exec.GetAverageDurationPerWorkload(s.db(c))
+	if err != nil {
+		slog.Error(err)
+	}
+
+	mtx.RLock()
+	defer mtx.RUnlock()
+	now := time.Now()
+	estimates := estimateQueue(s.priorityPolicy, pendingQueueElements(s.priorityPolicy, now), s.hosts, durations, now)
+
 	response := ExecutionQueueResponse{
-		Executions: make([]ExecutionQueue, 0, len(queue)),
+		Executions: make([]ExecutionQueue, 0, len(estimates)),
 	}
-	for _, e := range queue {
-		if e.Executing {
-			continue
-		}
+	for _, estimate := range estimates {
+		e := estimate.element
 		var profileBinary, profileMode string
 		if e.identifier.Profile != nil {
 			profileBinary = e.identifier.Profile.Binary
 			profileMode = e.identifier.Profile.Mode
 		}
+		var addedAt *time.Time
+		if !e.addedAt.IsZero() {
+			t := e.addedAt
+			addedAt = &t
+		}
+		estimatedStartAt := estimate.estimatedStartAt
+		var retryAt *time.Time
+		if !e.retryAt.IsZero() {
+			t := e.retryAt
+			retryAt = &t
+		}
+		var failures map[exec.FailureCategory]int
+		if len(e.failures) > 0 {
+			failures = make(map[exec.FailureCategory]int, len(e.failures))
+			for category, count := range e.failures {
+				failures[category] = count
+			}
+		}
 		response.Executions = append(response.Executions, ExecutionQueue{
-			Source:        e.identifier.Source,
-			GitRef:        e.identifier.GitRef,
-			Workload:      e.identifier.Workload,
-			PullNb:        e.identifier.PullNb,
-			ProfileBinary: profileBinary,
-			ProfileMode:   profileMode,
+			Source:           e.identifier.Source,
+			GitRef:           e.identifier.GitRef,
+			Workload:         e.identifier.Workload,
+			PullNb:           e.identifier.PullNb,
+			ProfileBinary:    profileBinary,
+			ProfileMode:      profileMode,
+			Position:         estimate.position,
+			Priority:         estimate.priority,
+			AddedAt:          addedAt,
+			EstimatedStartAt: &estimatedStartAt,
+			Failures:         failures,
+			RetryAt:          retryAt,
 		})
 		if !slices.Contains(response.Workloads, e.identifier.Workload) {
 			response.Workloads = append(response.Workloads, e.identifier.Workload)
@@ -150,9 +225,6 @@ func (s *Server) getExecutionsQueue(c *gin.Context) {
 			response.Sources = append(response.Sources, e.identifier.Source)
 		}
 	}
-	sort.Slice(response.Executions, func(i, j int) bool {
-		return response.Executions[i].GitRef > response.Executions[j].GitRef && response.Executions[i].Source > response.Executions[j].Source
-	})
 	c.JSON(http.StatusOK, response)
 }
 
@@ -169,7 +241,7 @@ func (s *Server) getLatestVitessGitRef(c *gin.Context) {
 		slog.Error(err)
 		return
 	}
-	lastrunDailySHA, err := exec.GetLatestDailyJobForMacrobenchmarks(s.dbClient)
+	lastrunDailySHA, err := exec.GetLatestDailyJobForMacrobenchmarks(s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -202,7 +274,7 @@ func (s *Server) compareMacroBenchmarks(c *gin.Context) {
 	oldSHA := c.Query("old")
 	newSHA := c.Query("new")
 
-	results, err := macrobench.Compare(s.dbClient, oldSHA, newSHA, s.workloads, macrobench.Gen4Planner)
+	results, err := macrobench.Compare(s.db(c), oldSHA, newSHA, s.workloads, macrobench.Gen4Planner)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -229,14 +301,14 @@ func (s *Server) compareMicrobenchmarks(c *gin.Context) {
 	rightSHA := c.Query("rtag")
 
 	// Get the results from the SHAs
-	leftMbd, err := microbench.GetResultsForGitRef(leftSHA, s.dbClient)
+	leftMbd, err := microbench.GetResultsForGitRef(leftSHA, s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
 		return
 	}
 	leftMbd = leftMbd.ReduceSimpleMedianByName()
-	rightMbd, err := microbench.GetResultsForGitRef(rightSHA, s.dbClient)
+	rightMbd, err := microbench.GetResultsForGitRef(rightSHA, s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -255,7 +327,7 @@ type searchResult struct {
 func (s *Server) searchBenchmark(c *gin.Context) {
 	sha := c.Query("sha")
 
-	results, err := macrobench.Search(s.dbClient, sha, s.workloads, macrobench.Gen4Planner)
+	results, err := macrobench.Search(s.db(c), sha, s.workloads, macrobench.Gen4Planner)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -305,13 +377,13 @@ func (s *Server) fkQueriesCompareMacrobenchmarks(c *gin.Context) {
 }
 
 func (s *Server) queriesCompare(c *gin.Context, oldGitRef, newGitRef string, oldWorkload, newWorkload macrobench.Workload) []macrobench.VTGateQueryPlanComparer {
-	oldPlans, err := macrobench.GetVTGateSelectQueryPlansWithFilter(oldGitRef, oldWorkload, macrobench.Gen4Planner, s.dbClient)
+	oldPlans, err := macrobench.GetVTGateSelectQueryPlansWithFilter(oldGitRef, oldWorkload, macrobench.Gen4Planner, s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
 		return nil
 	}
-	newPlans, err := macrobench.GetVTGateSelectQueryPlansWithFilter(newGitRef, newWorkload, macrobench.Gen4Planner, s.dbClient)
+	newPlans, err := macrobench.GetVTGateSelectQueryPlansWithFilter(newGitRef, newWorkload, macrobench.Gen4Planner, s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -321,7 +393,7 @@ func (s *Server) queriesCompare(c *gin.Context, oldGitRef, newGitRef string, old
 }
 
 func (s *Server) getPullRequest(c *gin.Context) {
-	prNumbers, err := exec.GetPullRequestList(s.dbClient)
+	prNumbers, err := exec.GetPullRequestList(s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -331,6 +403,7 @@ func (s *Server) getPullRequest(c *gin.Context) {
 	for _, prNumber := range prNumbers {
 		newPRInfo, err := s.ghApp.GetPullRequestInfo(prNumber)
 		if err != nil {
+			githubAPIErrors.WithLabelValues("get_pull_request").Inc()
 			c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 			slog.Error(err)
 			return
@@ -350,12 +423,13 @@ func (s *Server) getPullRequestInfo(c *gin.Context) {
 	}
 	prInfo, err := s.ghApp.GetPullRequestInfo(pullNb)
 	if err != nil {
+		githubAPIErrors.WithLabelValues("get_pull_request").Inc()
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
 		return
 	}
 
-	gitPRInfo, err := exec.GetPullRequestInfo(s.dbClient, pullNb, prInfo)
+	gitPRInfo, err := exec.GetPullRequestInfo(s.db(c), pullNb, prInfo)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -387,7 +461,7 @@ func (s *Server) getDailySummary(c *gin.Context) {
 			}
 		}
 	}
-	results, err := macrobench.SearchForLast30DaysQPSOnly(s.dbClient, workloads, macrobench.Gen4Planner)
+	results, err := macrobench.SearchForLast30DaysQPSOnly(s.db(c), workloads, macrobench.Gen4Planner)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -409,7 +483,7 @@ func (s *Server) getDailySummary(c *gin.Context) {
 
 func (s *Server) getDaily(c *gin.Context) {
 	workload := c.Query("workload")
-	data, err := macrobench.SearchForLast30Days(s.dbClient, workload, macrobench.Gen4Planner)
+	data, err := macrobench.SearchForLast30Days(s.db(c), workload, macrobench.Gen4Planner)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -419,7 +493,7 @@ func (s *Server) getDaily(c *gin.Context) {
 }
 
 func (s *Server) getStatusStats(c *gin.Context) {
-	stats, err := exec.GetBenchmarkStats(s.dbClient)
+	stats, err := exec.GetBenchmarkStats(s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -519,7 +593,7 @@ func (s *Server) deleteRun(c *gin.Context) {
 		return
 	}
 
-	err := exec.DeleteExecution(s.dbClient, sha, uuid, "custom_run")
+	err := exec.DeleteExecution(s.db(c), sha, uuid, "custom_run")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -533,7 +607,7 @@ func (s *Server) compareBenchmarkFKs(c *gin.Context) {
 	newWorkload := c.Query("newWorkload")
 	oldWorkload := c.Query("oldWorkload")
 
-	results, err := macrobench.CompareFKs(s.dbClient, oldWorkload, newWorkload, sha, macrobench.Gen4Planner)
+	results, err := macrobench.CompareFKs(s.db(c), oldWorkload, newWorkload, sha, macrobench.Gen4Planner)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -544,7 +618,7 @@ func (s *Server) compareBenchmarkFKs(c *gin.Context) {
 }
 
 func (s *Server) getHistory(c *gin.Context) {
-	results, err := exec.GetHistory(s.dbClient)
+	results, err := exec.GetHistory(s.db(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
 		slog.Error(err)
@@ -595,6 +669,7 @@ func (s *Server) addExecutions(c *gin.Context) {
 		url := "https://api.github.com/repos/vitessio/vitess/pulls/" + req.PR
 		prInfo, err := git.GetPullRequestHeadAndBase(url)
 		if err != nil {
+			githubAPIErrors.WithLabelValues("get_pull_request").Inc()
 			c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "unable to get PR information"})
 		}
 		req.SHA = prInfo.SHA
@@ -661,6 +736,30 @@ func (s *Server) clearExecutionQueue(c *gin.Context) {
 	c.JSON(http.StatusAccepted, "")
 }
 
+func (s *Server) cancelExecution(c *gin.Context) {
+	var req struct {
+		Auth string `json:"auth"`
+		UUID string `json:"uuid"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil || req.UUID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := s.handleAuthentication(c, req.Auth); err != nil {
+		c.JSON(http.StatusUnauthorized, &ErrorAPI{Error: err.Error()})
+		return
+	}
+
+	if !s.cancelQueueElement(req.UUID) {
+		c.JSON(http.StatusNotFound, &ErrorAPI{Error: fmt.Sprintf("no execution with UUID %s in the execution queue", req.UUID)})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, "")
+}
+
 func (s *Server) handleAuthentication(c *gin.Context, auth string) error {
 	decryptedToken, err := server.Decrypt(auth, s.ghTokenSalt)
 	if err != nil {
@@ -688,6 +787,7 @@ func IsUserAuthenticated(accessToken string) (bool, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
+		githubAPIErrors.WithLabelValues("get_user").Inc()
 		slog.Error("Error making request to Github: %v", err)
 		return false, err
 	}
@@ -695,3 +795,56 @@ func IsUserAuthenticated(accessToken string) (bool, error) {
 
 	return resp.StatusCode == http.StatusOK, nil
 }
+
+func (s *Server) getBisections(c *gin.Context) {
+	c.JSON(http.StatusOK, getBisections())
+}
+
+func (s *Server) getBisection(c *gin.Context) {
+	b, ok := getBisection(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, &ErrorAPI{Error: fmt.Sprintf("no bisection with ID %s", c.Param("id"))})
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+func (s *Server) startBisectionRequest(c *gin.Context) {
+	var req struct {
+		Auth           string `json:"auth"`
+		Workload       string `json:"workload"`
+		PlannerVersion string `json:"planner_version"`
+		GoodGitRef     string `json:"good_git_ref"`
+		BadGitRef      string `json:"bad_git_ref"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil || req.Workload == "" || req.GoodGitRef == "" || req.BadGitRef == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := s.handleAuthentication(c, req.Auth); err != nil {
+		c.JSON(http.StatusUnauthorized, &ErrorAPI{Error: err.Error()})
+		return
+	}
+
+	if req.PlannerVersion == "" {
+		req.PlannerVersion = string(macrobench.Gen4Planner)
+	}
+	if _, ok := s.getConfigFiles()[req.Workload]; !ok || req.Workload == "micro" {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: fmt.Sprintf("cannot bisect the workload %s", req.Workload)})
+		return
+	}
+
+	b, err := s.startBisection(req.Workload, req.PlannerVersion, req.GoodGitRef, req.BadGitRef)
+	if errors.Is(err, errBisected) {
+		c.JSON(http.StatusConflict, &ErrorAPI{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+		slog.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, b.ID)
+}
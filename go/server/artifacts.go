@@ -0,0 +1,133 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/storage/artifact"
+)
+
+// artifactsPruneInterval is the interval at which the expired artifacts are pruned.
+const artifactsPruneInterval = 1 * time.Hour
+
+// collectArtifacts saves the logs and the files collected by the given execution in
+// the artifact store. Errors are logged, they never fail the execution.
+func (s *Server) collectArtifacts(e *exec.Exec) {
+	if s.artifacts == nil || e.Dir() == "" {
+		return
+	}
+	artifacts, err := artifact.Collect(s.dbClient, s.artifacts, e.UUID.String(), e.Dir(), exec.LogFiles())
+	if err != nil {
+		slog.Errorf("could not collect the artifacts of %s: %v", e.UUID.String(), err)
+		return
+	}
+	slog.Infof("%d artifacts were collected for %s", len(artifacts), e.UUID.String())
+}
+
+// artifactsPruner removes the artifacts older than the retention period until the given context is done.
+func (s *Server) artifactsPruner(ctx context.Context) {
+	if s.artifacts == nil || s.artifactsRetention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(artifactsPruneInterval)
+	defer ticker.Stop()
+	for {
+		removed, err := artifact.Prune(s.dbClient, s.artifacts, time.Now().Add(-s.artifactsRetention))
+		if err != nil {
+			slog.Error(err)
+		} else if removed > 0 {
+			slog.Infof("%d expired artifacts were removed", removed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) getExecutionArtifacts(c *gin.Context) {
+	execUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "invalid execution UUID"})
+		return
+	}
+	artifacts, err := artifact.GetForExecution(s.db(c), execUUID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+		slog.Error(err)
+		return
+	}
+	if artifacts == nil {
+		artifacts = []artifact.Artifact{}
+	}
+	c.JSON(http.StatusOK, artifacts)
+}
+
+// downloadExecutionArtifact serves the content of an artifact given the UUID of its execution and its name.
+func (s *Server) downloadExecutionArtifact(c *gin.Context) {
+	if s.artifacts == nil {
+		c.JSON(http.StatusNotFound, &ErrorAPI{Error: "the artifact store is not enabled"})
+		return
+	}
+	execUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "invalid execution UUID"})
+		return
+	}
+	name := strings.TrimPrefix(c.Param("name"), "/")
+
+	artifacts, err := artifact.GetForExecution(s.db(c), execUUID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+		slog.Error(err)
+		return
+	}
+	for _, a := range artifacts {
+		if a.Name != name {
+			continue
+		}
+		f, err := s.artifacts.Open(a.Digest)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+			slog.Error(err)
+			return
+		}
+		defer f.Close()
+		c.DataFromReader(http.StatusOK, a.Size, "application/octet-stream", f, map[string]string{
+			"Content-Disposition": "attachment; filename=\"" + path.Base(a.Name) + "\"",
+		})
+		return
+	}
+	c.JSON(http.StatusNotFound, &ErrorAPI{Error: "no artifact named " + name + " for execution " + execUUID.String()})
+}
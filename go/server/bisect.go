@@ -0,0 +1,360 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/slack"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+)
+
+const (
+	bisectionStatusRunning  = "running"
+	bisectionStatusFinished = "finished"
+	bisectionStatusFailed   = "failed"
+)
+
+var (
+	// bisectMtx protects bisections and all the bisections it contains.
+	// It must always be acquired before the queue's mutex.
+	bisectMtx   sync.Mutex
+	bisections  = map[string]*bisection{}
+	errBisected = errors.New("a bisection of this regression already exists")
+)
+
+type (
+	// bisection finds the commit that introduced a macrobenchmark regression between a
+	// good and a bad git ref. It benchmarks the commit in the middle of the remaining commits
+	// and compares it with the good git ref, halving the range until a single commit is left.
+	bisection struct {
+		ID             string `json:"id"`
+		Workload       string `json:"workload"`
+		PlannerVersion string `json:"planner_version"`
+		GoodGitRef     string `json:"good_git_ref"`
+		BadGitRef      string `json:"bad_git_ref"`
+		Status         string `json:"status"`
+
+		// Commits are all the commits after GoodGitRef up to BadGitRef, from the oldest to the newest.
+		Commits []string `json:"commits"`
+
+		// Current is the commit being benchmarked.
+		Current string `json:"current,omitempty"`
+
+		// Culprit is the commit that introduced the regression, once the bisection is finished.
+		Culprit string `json:"culprit,omitempty"`
+
+		Error      string          `json:"error,omitempty"`
+		Steps      []bisectionStep `json:"steps"`
+		StartedAt  time.Time       `json:"started_at"`
+		FinishedAt *time.Time      `json:"finished_at,omitempty"`
+
+		// good is the index in Commits of the newest commit known to be good, -1 meaning
+		// GoodGitRef. bad is the index of the oldest commit known to be bad.
+		good, bad int
+	}
+
+	// bisectionStep is the result of the benchmark of one commit during a bisection.
+	bisectionStep struct {
+		GitRef    string `json:"git_ref"`
+		Regressed bool   `json:"regressed"`
+		Reason    string `json:"reason,omitempty"`
+	}
+)
+
+func newBisection(workload, plannerVersion, goodGitRef, badGitRef string, commits []string) *bisection {
+	return &bisection{
+		ID:             uuid.NewString(),
+		Workload:       workload,
+		PlannerVersion: plannerVersion,
+		GoodGitRef:     goodGitRef,
+		BadGitRef:      badGitRef,
+		Status:         bisectionStatusRunning,
+		Commits:        commits,
+		Steps:          []bisectionStep{},
+		StartedAt:      time.Now(),
+		good:           -1,
+		bad:            len(commits) - 1,
+	}
+}
+
+// next selects the next commit to benchmark and sets it as the current commit.
+// It returns false once a single commit remains, which is then the culprit.
+func (b *bisection) next() bool {
+	if b.bad-b.good <= 1 {
+		b.Current = ""
+		b.Culprit = b.Commits[b.bad]
+		b.finish(bisectionStatusFinished, "")
+		return false
+	}
+	b.Current = b.Commits[(b.good+b.bad)/2]
+	return true
+}
+
+// record saves the result of the benchmark of the current commit and halves the range of commits.
+func (b *bisection) record(regressed bool, reason string) {
+	mid := (b.good + b.bad) / 2
+	b.Steps = append(b.Steps, bisectionStep{GitRef: b.Current, Regressed: regressed, Reason: reason})
+	if regressed {
+		b.bad = mid
+	} else {
+		b.good = mid
+	}
+}
+
+func (b *bisection) finish(status, reason string) {
+	now := time.Now()
+	b.Status = status
+	b.Error = reason
+	b.FinishedAt = &now
+}
+
+// startBisection starts the bisection of a regression of the given workload between the two git refs.
+func (s *Server) startBisection(workload, plannerVersion, goodGitRef, badGitRef string) (*bisection, error) {
+	bisectMtx.Lock()
+	defer bisectMtx.Unlock()
+
+	for _, b := range bisections {
+		if b.Status == bisectionStatusRunning && b.Workload == workload && b.PlannerVersion == plannerVersion && b.GoodGitRef == goodGitRef && b.BadGitRef == badGitRef {
+			return nil, errBisected
+		}
+	}
+
+	s.vitessPathMu.Lock()
+	commits, err := git.GetCommitsBetween(s.getVitessPath(), goodGitRef, badGitRef)
+	s.vitessPathMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("no commits between %s and %s", goodGitRef, badGitRef)
+	}
+
+	b := newBisection(workload, plannerVersion, goodGitRef, badGitRef, commits)
+	bisections[b.ID] = b
+	slog.Infof("starting bisection %s of %s between %s and %s, %d commits", b.ID, workload, goodGitRef, badGitRef, len(commits))
+	s.advanceBisection(b)
+	return b, nil
+}
+
+// advanceBisection queues the benchmark of the next commit of the bisection, or announces
+// the culprit if the bisection is over. The caller must hold bisectMtx.
+func (s *Server) advanceBisection(b *bisection) {
+	defer s.saveBisection(b)
+
+	if !b.next() {
+		slog.Infof("bisection %s finished, culprit: %s", b.ID, b.Culprit)
+		s.announceBisection(b)
+		return
+	}
+
+	config, ok := s.getConfigFiles()[b.Workload]
+	if !ok {
+		b.finish(bisectionStatusFailed, fmt.Sprintf("unknown workload %s", b.Workload))
+		return
+	}
+	s.vitessPathMu.Lock()
+	version, err := git.GetVersionForCommitSHA(s.getVitessPath(), b.Current)
+	s.vitessPathMu.Unlock()
+	if err != nil {
+		b.finish(bisectionStatusFailed, err.Error())
+		return
+	}
+
+	element := s.createSimpleExecutionQueueElement(config, exec.SourceBisection, b.Current, b.Workload, b.PlannerVersion, false, 0, version, nil)
+	s.addToQueue(element)
+
+	// The commit might already have been benchmarked enough times, in which case nothing
+	// was added to the queue and we can directly look at the results.
+	if !hasPendingDuplicate(element) {
+		s.evaluateBisection(b)
+	}
+}
+
+// evaluateBisection compares the current commit of the bisection with the good git ref
+// and moves on to the next commit. The caller must hold bisectMtx.
+func (s *Server) evaluateBisection(b *bisection) {
+	results, err := macrobench.Compare(s.dbClient, b.GoodGitRef, b.Current, []string{b.Workload}, macrobench.PlannerVersion(b.PlannerVersion))
+	if err != nil {
+		b.finish(bisectionStatusFailed, err.Error())
+		s.saveBisection(b)
+		return
+	}
+	result := results[b.Workload]
+	if result.MissingResults {
+		b.finish(bisectionStatusFailed, fmt.Sprintf("no benchmark results for %s", b.Current))
+		s.saveBisection(b)
+		return
+	}
+	reason := result.Regression(s.regressionThreshold)
+	b.record(reason != "", reason)
+	s.advanceBisection(b)
+}
+
+// bisectionElementDone is called once an element of the queue is done, successfully or not.
+// If the element was the last execution of the current commit of a bisection, the bisection
+// moves on.
+func (s *Server) bisectionElementDone(element *executionQueueElement) {
+	id := element.identifier
+	if id.Source != exec.SourceBisection || hasPendingDuplicate(element) {
+		return
+	}
+
+	bisectMtx.Lock()
+	defer bisectMtx.Unlock()
+	for _, b := range bisections {
+		if b.Status == bisectionStatusRunning && b.Current == id.GitRef && b.Workload == id.Workload && b.PlannerVersion == id.PlannerVersion {
+			s.evaluateBisection(b)
+		}
+	}
+}
+
+// startBisectionOfRegression starts a bisection if the regression is between two nightly
+// macrobenchmarks of main, there is no point in bisecting against a release.
+func (s *Server) startBisectionOfRegression(old, new executionIdentifier) {
+	if !s.bisectRegressions || new.Workload == "micro" || old.Source != exec.SourceCron || new.Source != exec.SourceCron {
+		return
+	}
+	_, err := s.startBisection(new.Workload, new.PlannerVersion, old.GitRef, new.GitRef)
+	if err != nil && !errors.Is(err, errBisected) {
+		slog.Errorf("could not bisect the regression of %+v: %v", new, err)
+	}
+}
+
+func (s *Server) announceBisection(b *bisection) {
+	if !s.slackConfig.IsValid() {
+		return
+	}
+	msg := slack.TextMessage{Content: fmt.Sprintf(":mag: Bisection of the regression of %s (%s planner) between `%s` and `%s` is over, the culprit is `%s`",
+		b.Workload, b.PlannerVersion, git.ShortenSHA(b.GoodGitRef), git.ShortenSHA(b.BadGitRef), b.Culprit)}
+	if err := msg.Send(s.slackConfig); err != nil {
+		slog.Error(err)
+	}
+}
+
+// getBisections returns a copy of all the bisections, from the most recent to the oldest.
+func getBisections() []bisection {
+	bisectMtx.Lock()
+	defer bisectMtx.Unlock()
+
+	res := make([]bisection, 0, len(bisections))
+	for _, b := range bisections {
+		res = append(res, b.copy())
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartedAt.After(res[j].StartedAt)
+	})
+	return res
+}
+
+func getBisection(id string) (bisection, bool) {
+	bisectMtx.Lock()
+	defer bisectMtx.Unlock()
+
+	b, ok := bisections[id]
+	if !ok {
+		return bisection{}, false
+	}
+	return b.copy(), true
+}
+
+func (b *bisection) copy() bisection {
+	res := *b
+	res.Commits = append([]string(nil), b.Commits...)
+	res.Steps = append([]bisectionStep{}, b.Steps...)
+	return res
+}
+
+// saveBisection saves the state of the bisection in the database.
+func (s *Server) saveBisection(b *bisection) {
+	if s.dbClient == nil {
+		return
+	}
+	commits, err := json.Marshal(b.Commits)
+	if err != nil {
+		slog.Error(err)
+		return
+	}
+	steps, err := json.Marshal(b.Steps)
+	if err != nil {
+		slog.Error(err)
+		return
+	}
+	var finishedAt sql.NullTime
+	if b.FinishedAt != nil {
+		finishedAt = nullTime(*b.FinishedAt)
+	}
+	_, err = s.dbClient.Write("INSERT INTO bisection(id, workload, planner_version, good_git_ref, bad_git_ref, status, commits, good_index, bad_index, current_git_ref, culprit, error, steps, started_at, finished_at) "+
+		"VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+		storage.DialectOf(s.dbClient).OnDuplicateKeyUpdate([]string{"id"}, "status", "good_index", "bad_index", "current_git_ref", "culprit", "error", "steps", "finished_at"),
+		b.ID, b.Workload, b.PlannerVersion, b.GoodGitRef, b.BadGitRef, b.Status, string(commits), b.good, b.bad, b.Current, b.Culprit, b.Error, string(steps), b.StartedAt.UTC(), finishedAt)
+	if err != nil {
+		slog.Error(err)
+	}
+}
+
+// loadBisectionsFromDB reads all the bisections saved in the database.
+func (s *Server) loadBisectionsFromDB() error {
+	rows, err := s.dbClient.Read("SELECT id, workload, planner_version, good_git_ref, bad_git_ref, status, IFNULL(commits, ''), good_index, bad_index, " +
+		"IFNULL(current_git_ref, ''), IFNULL(culprit, ''), IFNULL(error, ''), IFNULL(steps, ''), started_at, finished_at FROM bisection")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bisectMtx.Lock()
+	defer bisectMtx.Unlock()
+	for rows.Next() {
+		var b bisection
+		var commits, steps string
+		var startedAt, finishedAt sql.NullTime
+		err = rows.Scan(&b.ID, &b.Workload, &b.PlannerVersion, &b.GoodGitRef, &b.BadGitRef, &b.Status, &commits, &b.good, &b.bad,
+			&b.Current, &b.Culprit, &b.Error, &steps, &startedAt, &finishedAt)
+		if err != nil {
+			return err
+		}
+		if commits != "" {
+			if err = json.Unmarshal([]byte(commits), &b.Commits); err != nil {
+				return err
+			}
+		}
+		b.Steps = []bisectionStep{}
+		if steps != "" {
+			if err = json.Unmarshal([]byte(steps), &b.Steps); err != nil {
+				return err
+			}
+		}
+		b.StartedAt = startedAt.Time
+		if finishedAt.Valid {
+			b.FinishedAt = &finishedAt.Time
+		}
+		bisections[b.ID] = &b
+	}
+	return rows.Err()
+}
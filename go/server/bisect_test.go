@@ -0,0 +1,90 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestBisection(t *testing.T) {
+	commits := make([]string, 0, 9)
+	for i := 1; i <= 9; i++ {
+		commits = append(commits, fmt.Sprintf("c%d", i))
+	}
+
+	tests := []struct {
+		name     string
+		commits  []string
+		culprit  int
+		maxSteps int
+	}{
+		{name: "Single commit", commits: commits[:1], culprit: 0, maxSteps: 0},
+		{name: "First commit", commits: commits, culprit: 0, maxSteps: 4},
+		{name: "Middle commit", commits: commits, culprit: 4, maxSteps: 4},
+		{name: "Last commit", commits: commits, culprit: 8, maxSteps: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			b := newBisection("oltp", "Gen4", "good", tt.commits[len(tt.commits)-1], tt.commits)
+			for b.next() {
+				c.Assert(b.Status, qt.Equals, bisectionStatusRunning)
+				index := -1
+				for i, commit := range tt.commits {
+					if commit == b.Current {
+						index = i
+					}
+				}
+				c.Assert(index, qt.Not(qt.Equals), -1)
+				b.record(index >= tt.culprit, "")
+			}
+			c.Assert(b.Status, qt.Equals, bisectionStatusFinished)
+			c.Assert(b.Culprit, qt.Equals, tt.commits[tt.culprit])
+			c.Assert(b.Current, qt.Equals, "")
+			c.Assert(len(b.Steps) <= tt.maxSteps, qt.IsTrue, qt.Commentf("%d steps", len(b.Steps)))
+			c.Assert(b.FinishedAt, qt.IsNotNil)
+		})
+	}
+}
+
+func TestGetBisections(t *testing.T) {
+	c := qt.New(t)
+
+	older := newBisection("oltp", "Gen4", "a", "c", []string{"b", "c"})
+	newer := newBisection("tpcc", "Gen4", "a", "c", []string{"b", "c"})
+	newer.StartedAt = older.StartedAt.Add(1)
+	bisections = map[string]*bisection{older.ID: older, newer.ID: newer}
+	defer func() { bisections = map[string]*bisection{} }()
+
+	got := getBisections()
+	c.Assert(got, qt.HasLen, 2)
+	c.Assert(got[0].ID, qt.Equals, newer.ID)
+	c.Assert(got[1].ID, qt.Equals, older.ID)
+
+	// The returned bisections are copies that can be used without holding the mutex.
+	got[0].Commits[0] = "changed"
+	c.Assert(newer.Commits[0], qt.Equals, "b")
+
+	_, ok := getBisection("unknown")
+	c.Assert(ok, qt.IsFalse)
+}
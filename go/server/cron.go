@@ -19,6 +19,7 @@
 package server
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -32,10 +33,33 @@ import (
 type (
 	executionQueueElement struct {
 		config                  benchmarkConfig
-		retry                   int
 		identifier              executionIdentifier
 		compareWith             []executionIdentifier
 		notifyAlways, Executing bool
+
+		// baseline is set when the element is only created to be compared with another,
+		// more recent, element. The regressions of a baseline element are not evaluated
+		// since its comparers are newer than it.
+		baseline bool
+
+		// addedAt is the time at which the element was added to the queue,
+		// it is used to prioritize elements that have been waiting for long.
+		addedAt time.Time
+
+		// failures counts how many times the element failed for each category of failure,
+		// and retryAt is set when the element failed and is put back in the queue, the
+		// element will not be executed again before that time.
+		failures map[exec.FailureCategory]int
+		retryAt  time.Time
+
+		// cancel stops the execution of the element, it is set while the element is executing.
+		// A nil cause cancels the execution, a *exec.Failure cause makes the execution fail.
+		cancel context.CancelCauseFunc
+
+		// queueID is the ID of the row that persists this element in the
+		// execution_queue table, it is zero if the element is not persisted.
+		// It is guarded by queueFlushMtx instead of the queue's mutex.
+		queueID int64
 	}
 
 	executionIdentifier struct {
@@ -50,17 +74,24 @@ type (
 	executionQueue map[executionIdentifier]*executionQueueElement
 )
 
-const (
-	// maxConcurJob is the maximum number of concurrent jobs that we can execute
-	maxConcurJob = 1
-)
-
 var (
-	currentCountExec int
-	mtx              sync.RWMutex
-	queue            executionQueue
+	mtx   sync.RWMutex
+	queue executionQueue
+
+	// queueSignal wakes up the queue watcher when something changed in the queue
+	// or when a benchmark host became available.
+	queueSignal = make(chan struct{}, 1)
 )
 
+// signalQueue wakes up the queue watcher. It never blocks: if the watcher
+// already has a pending signal, the new one is dropped.
+func signalQueue() {
+	select {
+	case queueSignal <- struct{}{}:
+	default:
+	}
+}
+
 func (ei executionIdentifier) equalWithoutUUID(id executionIdentifier) bool {
 	ei.UUID = ""
 	id.UUID = ""
@@ -80,7 +111,7 @@ func (ei executionIdentifier) equalWithoutUUID(id executionIdentifier) bool {
 	return ei == id
 }
 
-func createIndividualCRON(schedule string, job func()) error {
+func createIndividualCRON(ctx context.Context, schedule string, job func()) error {
 	if schedule == "" {
 		return nil
 	}
@@ -91,32 +122,48 @@ func createIndividualCRON(schedule string, job func()) error {
 		return err
 	}
 	c.Start()
+	go func() {
+		<-ctx.Done()
+		c.Stop()
+	}()
 	return nil
 }
 
-func (s *Server) createCrons() error {
+// createCrons reloads the queue, recovers the orphaned executions, starts the CRONs, the
+// queue watcher, the queue writer, the reaper of stuck executions and the pruner of expired artifacts.
+// They all stop once the given context is done.
+func (s *Server) createCrons(ctx context.Context) error {
 	queue = make(executionQueue)
 
-	crons := []struct {
-		schedule string
-		f        func()
-		name     string
-	}{
-		{name: "branch", schedule: s.cronSchedule, f: s.branchCronHandler},
-		{name: "pull_requests", schedule: s.cronSchedulePullRequests, f: s.pullRequestsCronHandler},
-		{name: "tags", schedule: s.cronScheduleTags, f: s.tagsCronHandler},
-	}
-	for _, c := range crons {
-		if c.schedule == "none" {
+	// Reload the elements that were in the queue before the server stopped.
+	err := s.loadQueueFromDB()
+	if err != nil {
+		return err
+	}
+	err = s.loadBisectionsFromDB()
+	if err != nil {
+		return err
+	}
+	err = s.recoverOrphanedExecutions()
+	if err != nil {
+		return err
+	}
+
+	for _, sc := range s.schedules {
+		if sc.Cron == "" {
 			continue
 		}
-		slog.Info("Starting the CRON ", c.name, " with schedule: ", c.schedule)
-		err := createIndividualCRON(c.schedule, c.f)
+		slog.Info("Starting the CRON of the schedule ", sc.Name, " with schedule: ", sc.Cron)
+		err := createIndividualCRON(ctx, sc.Cron, s.scheduleHandler(sc))
 		if err != nil {
 			return err
 		}
+		s.cronRuns.start(sc.Name, time.Now())
 	}
-	go s.cronExecutionQueueWatcher()
+	go s.cronExecutionQueueWatcher(ctx)
+	go s.queueWriter(ctx)
+	go s.executionReaper(ctx)
+	go s.artifactsPruner(ctx)
 	return nil
 }
 
@@ -132,7 +179,27 @@ func (s *Server) removePRFromQueue(element *executionQueueElement) {
 		if !e.Executing && id.PullNb == element.identifier.PullNb && id.Workload == element.identifier.Workload && id.Source == element.identifier.Source && id.GitRef != element.identifier.GitRef {
 			slog.Infof("%+v is removed from the queue", id)
 			delete(queue, id)
+			persistQueueElement(queueDelete, e)
+		}
+	}
+}
+
+// removePullRequestFromQueue removes the pending elements benchmarking the given pull request.
+// If plannerVersions is not empty, only the elements using one of these planner versions are removed.
+func (s *Server) removePullRequestFromQueue(pullNb int, plannerVersions ...string) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	for id, e := range queue {
+		if e.Executing || id.Source != exec.SourcePullRequest || id.PullNb != pullNb {
+			continue
 		}
+		if len(plannerVersions) > 0 && !slices.Contains(plannerVersions, id.PlannerVersion) {
+			continue
+		}
+		slog.Infof("%+v is removed from the queue", id)
+		delete(queue, id)
+		persistQueueElement(queueDelete, e)
 	}
 }
 
@@ -187,6 +254,10 @@ func (s *Server) addToQueue(element *executionQueueElement) {
 }
 
 func (s *Server) appendToQueue(execElements []*executionQueueElement) {
+	// The caller holds the queue's mutex, the watcher will only look at
+	// the new elements once all of them are added and the mutex is released.
+	defer signalQueue()
+
 	for _, execElement := range execElements {
 		// Check if the exact same benchmark is already in the queue
 		_, found := queue[execElement.identifier]
@@ -195,11 +266,13 @@ func (s *Server) appendToQueue(execElements []*executionQueueElement) {
 			continue
 		}
 
+		if execElement.addedAt.IsZero() {
+			execElement.addedAt = time.Now()
+		}
 		queue[execElement.identifier] = execElement
+		persistQueueElement(queueInsert, execElement)
+		queueEvents.publish(newQueueEvent(queueEventAdded, execElement.identifier))
 		slog.Infof("%+v is added to the queue", execElement.identifier)
-
-		// We sleep here to avoid adding too many similar elements to the queue at the same time.
-		time.Sleep(100 * time.Millisecond)
 	}
 }
 
@@ -214,5 +287,69 @@ func (s *Server) clearQueue(removeAdmin bool) {
 			continue
 		}
 		delete(queue, id)
+		persistQueueElement(queueDelete, e)
+	}
+}
+
+// removeFromQueue removes the given element from the queue and from the database.
+func (s *Server) removeFromQueue(element *executionQueueElement) {
+	mtx.Lock()
+	defer mtx.Unlock()
+	if _, found := queue[element.identifier]; found {
+		delete(queue, element.identifier)
+	}
+	persistQueueElement(queueDelete, element)
+}
+
+// cancelQueueElement cancels the element of the queue with the given UUID. If the element
+// is executing its execution is stopped, otherwise it is simply removed from the queue.
+// It returns false if no element of the queue has the given UUID.
+func (s *Server) cancelQueueElement(UUID string) bool {
+	mtx.Lock()
+	defer mtx.Unlock()
+	for id, e := range queue {
+		if id.UUID != UUID {
+			continue
+		}
+		if e.Executing {
+			// The element is removed from the queue by executeElement once the execution stopped.
+			if e.cancel != nil {
+				e.cancel(nil)
+			}
+			slog.Infof("canceling the execution of %+v", id)
+			return true
+		}
+		delete(queue, id)
+		persistQueueElement(queueDelete, e)
+		slog.Infof("%+v was canceled before being executed, removing it from the queue", id)
+		return true
+	}
+	return false
+}
+
+// prepareQueueElementForRetry records a failure of the given category for the element.
+// If the retry policy of that category allows it, the element gets a new UUID and is put
+// back in the queue to be executed again once its backoff expires. It returns false if the
+// element has no retry left, in which case the element is left untouched in the queue.
+func (s *Server) prepareQueueElementForRetry(element *executionQueueElement, category exec.FailureCategory) bool {
+	mtx.Lock()
+	defer mtx.Unlock()
+	if !s.scheduleRetry(element, category, time.Now()) {
+		return false
+	}
+	executionRetries.WithLabelValues(element.identifier.Workload, string(category)).Inc()
+	_, found := queue[element.identifier]
+	if found {
+		delete(queue, element.identifier)
+	}
+	element.identifier.UUID = uuid.NewString()
+	element.Executing = false
+	element.cancel = nil
+	if found {
+		queue[element.identifier] = element
+		queueEvents.publish(newQueueEvent(queueEventAdded, element.identifier))
 	}
+	persistQueueElement(queueUpdate, element)
+	signalQueue()
+	return true
 }
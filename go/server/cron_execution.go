@@ -19,20 +19,27 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/storage"
 )
 
-func (s *Server) executeSingle(config benchmarkConfig, identifier executionIdentifier, nextIsSame, lastIsSame bool) (err error) {
+const (
+	// compareElementPollInterval is how often compareElement checks if the executions it
+	// waits for are finished, and compareElementTimeout how long it waits for them at most.
+	compareElementPollInterval = time.Second
+	compareElementTimeout      = 24 * time.Hour
+)
+
+func (s *Server) executeSingle(ctx context.Context, config benchmarkConfig, identifier executionIdentifier, host *benchmarkHost, nextIsSame, lastIsSame bool) (err error) {
 	var e *exec.Exec
 	defer func() {
 		if e != nil {
-			if err != nil {
-				err = fmt.Errorf("%v", err)
-			}
+			s.collectArtifacts(e)
 			if errSuccess := e.Success(); errSuccess != nil {
 				err = errSuccess
 				return
@@ -44,7 +51,7 @@ func (s *Server) executeSingle(config benchmarkConfig, identifier executionIdent
 	e, err = exec.NewExecWithConfig(config.file, identifier.UUID)
 
 	if err != nil {
-		nErr := fmt.Errorf("new exec error: %v", err)
+		nErr := fmt.Errorf("new exec error: %w", err)
 		slog.Error(nErr.Error())
 		return nErr
 	}
@@ -56,101 +63,127 @@ func (s *Server) executeSingle(config benchmarkConfig, identifier executionIdent
 	e.NextBenchmarkIsTheSame = nextIsSame
 	e.ProfileInformation = identifier.Profile
 	e.RepoDir = s.getVitessPath()
+	if host.address != "" {
+		e.ServerAddress = host.address
+	}
 
-	// Check if the previous benchmark is the same and if it is
-	// safe to execute this new benchmark without a preparatory cleanup phase.
+	// lastIsSame is only true if the previous benchmark on this host had the same
+	// configuration and finished successfully, in which case it is safe to execute
+	// this new benchmark without a preparatory cleanup phase.
 	e.PreviousBenchmarkIsTheSame = lastIsSame
-	if lastIsSame {
-		lastBenchmarkWasClean, err := exec.IsLastExecutionFinished(s.dbClient)
-		if err != nil {
-			return err
-		}
-		if !lastBenchmarkWasClean {
-			e.PreviousBenchmarkIsTheSame = false
-		}
-	}
 
-	slog.Info("Starting execution: UUID: [", e.UUID.String(), "], Git Ref: [", identifier.GitRef, "], Workload: [", identifier.Workload, "]")
+	slog.Info("Starting execution: UUID: [", e.UUID.String(), "], Git Ref: [", identifier.GitRef, "], Workload: [", identifier.Workload, "], Host: [", e.ServerAddress, "]")
 	err = e.Prepare()
 	if err != nil {
-		nErr := fmt.Errorf("prepare error: %v", err)
+		nErr := fmt.Errorf("prepare error: %w", err)
 		slog.Error(nErr.Error())
 		return nErr
 	}
 
 	err = e.SetOutputToDefaultPath()
 	if err != nil {
-		nErr := fmt.Errorf("prepare output error: %v", err)
+		nErr := fmt.Errorf("prepare output error: %w", err)
 		slog.Error(nErr.Error())
 		return nErr
 	}
 
-	timeout := 1 * time.Hour
-	if identifier.Workload == "micro" {
-		timeout = 4 * time.Hour
-	}
-	err = e.ExecuteWithTimeout(timeout)
+	e.Timeouts = config.timeouts
+	err = e.ExecuteWithTimeout(ctx, config.timeouts.Total)
 	if err != nil {
-		nErr := fmt.Errorf("execute with timeout error: %v", err)
+		nErr := fmt.Errorf("execute with timeout error: %w", err)
 		slog.Error(nErr.Error())
 		return nErr
 	}
 	return nil
 }
 
-func (s *Server) executeElement(element *executionQueueElement, nextIsSame bool, lastIsSame bool) {
-	if element.retry < 0 {
-		if _, found := queue[element.identifier]; found {
-			// removing the element from the queue since we are done with it
-			mtx.Lock()
-			delete(queue, element.identifier)
-			mtx.Unlock()
-		}
-		decrementNumberOfOnGoingExecution()
-		return
-	}
-
+func (s *Server) executeElement(ctx context.Context, cancel context.CancelCauseFunc, element *executionQueueElement, host *benchmarkHost, nextIsSame bool, lastIsSame bool) {
 	// execute with the given configuration file and exec identifier
-	err := s.executeSingle(element.config, element.identifier, nextIsSame, lastIsSame)
+	workload := element.identifier.Workload
+	start := time.Now()
+	err := s.executeSingle(ctx, element.config, element.identifier, host, nextIsSame, lastIsSame)
+	cause := context.Cause(ctx)
+	cancel(nil)
+	executionDuration.WithLabelValues(workload).Observe(time.Since(start).Seconds())
 	if err != nil {
 		slog.Error(err.Error())
 
-		// execution failed, we retry
-		element.retry -= 1
-		element.identifier.UUID = uuid.NewString()
+		// The host is released as failed, the next benchmark executed on
+		// it will have to clean it up before running.
+		releaseHost(host, element.identifier, false)
 
-		// Here we set lastIsSame as false since the previous benchmark has failed
-		// That allows us to avoid executing one more database request to check if
-		// the previous benchmark was successful or not.
-		s.executeElement(element, nextIsSame, false)
+		// A canceled execution is never retried, unlike a stuck execution stopped by the reaper.
+		var reaped *exec.Failure
+		if cause != nil && !errors.As(cause, &reaped) {
+			// The execution may have been canceled before it could mark itself as canceled.
+			if err := exec.MarkAsCanceled(s.dbClient, element.identifier.UUID); err != nil {
+				slog.Error(err)
+			}
+			executionsFailed.WithLabelValues(workload, "canceled").Inc()
+			queueEvents.publish(newQueueEvent(queueEventCanceled, element.identifier))
+			s.removeFromQueue(element)
+			s.bisectionElementDone(element)
+			return
+		}
+
+		// execution failed, the element goes back to the queue and will be retried
+		// if the retry policy of this category of failure allows it
+		failure := exec.ClassifyFailure(err, nil)
+		executionsFailed.WithLabelValues(workload, string(failure.Category)).Inc()
+		failedEvent := newQueueEvent(queueEventFailed, element.identifier)
+		failedEvent.Error = failure.Reason
+		queueEvents.publish(failedEvent)
+		if !s.prepareQueueElementForRetry(element, failure.Category) {
+			slog.Infof("%+v failed with a %s failure and has no retry left, removing it from the queue", element.identifier, failure.Category)
+
+			// removing the element from the queue since we are done with it
+			s.removeFromQueue(element)
+			s.bisectionElementDone(element)
+		}
 		return
 	}
+	executionsFinished.WithLabelValues(workload).Inc()
+	queueEvents.publish(newQueueEvent(queueEventFinished, element.identifier))
 
 	go func() {
 		// removing the element from the queue since we are done with it
-		mtx.Lock()
-		delete(queue, element.identifier)
-		mtx.Unlock()
+		s.removeFromQueue(element)
+		s.bisectionElementDone(element)
 
 		// we will wait for the benchmarks we need to compare it against and notify users if needed
 		s.compareElement(element)
 	}()
 
-	decrementNumberOfOnGoingExecution()
+	releaseHost(host, element.identifier, true)
 }
 
+// compareElement waits for the executions the element is compared with to finish, then
+// comments the pull request of the element and handles its regressions. It gives up after
+// compareElementTimeout, since the executions it waits for may never finish.
 func (s *Server) compareElement(element *executionQueueElement) {
+	ctx, cancel := context.WithTimeout(context.Background(), compareElementTimeout)
+	defer cancel()
+	client := storage.WithContext(ctx, s.dbClient)
+
+	ticker := time.NewTicker(compareElementPollInterval)
+	defer ticker.Stop()
+
 	// map that contains all the comparison we saw and analyzed
 	seen := map[executionIdentifier]bool{}
 	done := 0
 	for done != len(element.compareWith) {
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+			slog.Errorf("stopped waiting for the executions %+v is compared with: %v", element.identifier, ctx.Err())
+			return
+		case <-ticker.C:
+		}
 		for _, comparer := range element.compareWith {
 			// checking if we have already seen this comparison, if we did, we can skip it.
 			if _, ok := seen[comparer]; ok {
 				continue
 			}
-			comparerUUID, err := exec.GetFinishedExecution(s.dbClient, comparer.GitRef, comparer.Source, comparer.Workload, comparer.PlannerVersion, comparer.PullNb)
+			comparerUUID, err := exec.GetFinishedExecution(client, comparer.GitRef, comparer.Source, comparer.Workload, comparer.PlannerVersion, comparer.PullNb)
 			if err != nil {
 				slog.Error(err)
 				return
@@ -161,6 +194,13 @@ func (s *Server) compareElement(element *executionQueueElement) {
 			}
 		}
 	}
+
+	if err := s.commentPullRequest(element); err != nil {
+		slog.Errorf("could not comment the results of %+v: %v", element.identifier, err)
+	}
+	if err := s.handleRegressions(element); err != nil {
+		slog.Errorf("could not handle the regressions of %+v: %v", element.identifier, err)
+	}
 }
 
 func (s *Server) getNumberOfBenchmarksInDB(identifier executionIdentifier) (int, error) {
@@ -182,97 +222,125 @@ func (s *Server) getNumberOfBenchmarksInDB(identifier executionIdentifier) (int,
 	return nb, nil
 }
 
-// cronExecutionQueueWatcher runs an infinite loop that watches the execution queue
-// it will send an item to the Executor based on different priority rules ordered that way:
-//  0. No executions that are in progress will get executed
-//  1. Admin executions always get executed first no matter what
-//  2. Execution of the same type (workload/commit) will be executed sequentially
-//  3. If none of this priority match, a random element is picked
-func (s *Server) cronExecutionQueueWatcher() {
-	var lastExecutedId executionIdentifier
-	queueWatch := func() {
-		mtx.Lock()
-		defer mtx.Unlock()
-		if currentCountExec >= maxConcurJob || len(queue) == 0 {
+// cronExecutionQueueWatcher watches the execution queue until the given context is done.
+// It sleeps until something changes: an element is added to the queue, an execution finishes
+// and frees its host, or the retry delay of a failed element expires. Every time it wakes up,
+// it dispatches as many elements as there are free benchmark hosts.
+func (s *Server) cronExecutionQueueWatcher(ctx context.Context) {
+	for {
+		var retryTimer <-chan time.Time
+		var timer *time.Timer
+		if nextRetryAt := s.dispatchQueue(); !nextRetryAt.IsZero() {
+			timer = time.NewTimer(time.Until(nextRetryAt))
+			retryTimer = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-queueSignal:
+		case <-retryTimer:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
 			return
 		}
+	}
+}
 
-		// Look for what's in the queue, specifically here is what we look for:
-		// 	- An execution that matches the previous execution
-		// 	- The first admin execution
-		var lastBenchmarkIsTheSame bool
-		var nextExecuteElement *executionQueueElement
-		var firstAdminExecuteElement *executionQueueElement
-		for _, element := range queue {
-			if element.Executing {
-				continue
-			}
+// dispatchQueue sends elements of the queue to the Executor of every free benchmark host.
+// It returns the earliest time at which an element waiting for its retry becomes due,
+// or a zero time if there is none.
+func (s *Server) dispatchQueue() time.Time {
+	mtx.Lock()
+	defer mtx.Unlock()
 
-			// Look if there is a matching execution in the queue
-			if nextExecuteElement == nil && element.identifier.equalWithoutUUID(lastExecutedId) {
-				nextExecuteElement = element
-				lastBenchmarkIsTheSame = true
-			}
+	for {
+		host := s.hosts.free()
+		if host == nil {
+			return time.Time{}
+		}
+		element, lastIsSame, nextIsSame, nextRetryAt := s.nextQueueElement(host, time.Now())
+		if element == nil {
+			return nextRetryAt
+		}
 
-			// Look if there is any admin execution in the queue
-			if firstAdminExecuteElement == nil && element.identifier.Source == "admin" {
-				firstAdminExecuteElement = element
-			}
+		// Execute the element on the host we found
+		host.lease(element.identifier, nextIsSame)
 
-			// If both the first admin and next execution are not nil, we found what we want, we can stop
-			if nextExecuteElement != nil && firstAdminExecuteElement != nil {
-				break
-			}
-		}
+		// setting this element to `Executing = true`, so we do not execute it twice in the future
+		element.Executing = true
+		persistQueueElement(queueUpdate, element)
+		executionsStarted.WithLabelValues(element.identifier.Workload).Inc()
+		queueEvents.publish(newQueueEvent(queueEventStarted, element.identifier))
 
-		// If we found an admin execution and the next execution we found is either nil or not an admin
-		// we force execute the first admin execution we found.
-		if firstAdminExecuteElement != nil && (nextExecuteElement == nil || nextExecuteElement.identifier.Source != "admin") {
-			nextExecuteElement = firstAdminExecuteElement
-			lastBenchmarkIsTheSame = false
-		}
+		// The execution can be stopped through the API or by the reaper by canceling its context.
+		ctx, cancel := context.WithCancelCause(context.Background())
+		element.cancel = cancel
+		go s.executeElement(ctx, cancel, element, host, nextIsSame, lastIsSame)
+	}
+}
 
-		// If we did not find any matching element just go to the first one which is not executing
-		if firstAdminExecuteElement == nil && nextExecuteElement == nil {
-			for _, element := range queue {
-				if !element.Executing {
-					nextExecuteElement = element
-					break
-				}
+// nextQueueElement picks the element of the queue that should be executed next on the given host.
+// The element is chosen based on different priority rules ordered that way:
+//  0. No executions that are in progress or waiting for their retry delay will get executed
+//  1. Executions are ordered by the server's queuePriorityPolicy, the first one is picked
+//  2. Execution of the same type (workload/commit) will be executed sequentially on the same host,
+//     as long as they are not less important than the first execution of the queue
+//
+// If no element can be executed, nil is returned along with the earliest time at which
+// an element waiting for its retry becomes due. The caller must hold the queue's mutex.
+func (s *Server) nextQueueElement(host *benchmarkHost, now time.Time) (element *executionQueueElement, lastIsSame, nextIsSame bool, nextRetryAt time.Time) {
+	// Executions that another host is expecting to run next are skipped.
+	var candidates []*executionQueueElement
+	for _, e := range pendingQueueElements(s.priorityPolicy, now) {
+		if e.retryAt.After(now) {
+			if nextRetryAt.IsZero() || e.retryAt.Before(nextRetryAt) {
+				nextRetryAt = e.retryAt
 			}
+			continue
 		}
+		if s.hosts.reservedByAnotherHost(host, e.identifier) {
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+	if len(candidates) == 0 {
+		return nil, false, false, nextRetryAt
+	}
 
-		if nextExecuteElement != nil {
-			// Find out if there is another element in queue that match the one we want to execute
-			var nextBenchmarkIsTheSame bool
-			for _, element := range queue {
-				if element.Executing {
-					continue
-				}
-				if nextExecuteElement.identifier.UUID != element.identifier.UUID && element.identifier.equalWithoutUUID(nextExecuteElement.identifier) {
-					nextBenchmarkIsTheSame = true
-					break
-				}
-			}
-
-			// Execute the element if found
-			currentCountExec++
-			lastExecutedId = nextExecuteElement.identifier
-
-			// setting this element to `Executing = true`, so we do not execute it twice in the future
-			nextExecuteElement.Executing = true
-			go s.executeElement(nextExecuteElement, nextBenchmarkIsTheSame, lastBenchmarkIsTheSame)
-			return
+	// Look for an execution that matches the previous execution of this host, we only
+	// pick it over the first element of the queue if it is as important.
+	element = candidates[0]
+	for _, e := range candidates {
+		if !e.identifier.equalWithoutUUID(host.lastExecuted) {
+			continue
+		}
+		if s.priorityPolicy.sourcePriority(e) >= s.priorityPolicy.sourcePriority(element) {
+			element = e
+			lastIsSame = host.lastSucceeded
 		}
+		break
 	}
 
-	for {
-		queueWatch()
+	// Find out if there is another element in queue that match the one we want to execute
+	for _, e := range queue {
+		if e.Executing {
+			continue
+		}
+		if element.identifier.UUID != e.identifier.UUID && e.identifier.equalWithoutUUID(element.identifier) {
+			nextIsSame = true
+			break
+		}
 	}
+	return element, lastIsSame, nextIsSame, time.Time{}
 }
 
-func decrementNumberOfOnGoingExecution() {
+// releaseHost frees the given host once an execution is done with it.
+func releaseHost(host *benchmarkHost, identifier executionIdentifier, succeeded bool) {
 	mtx.Lock()
-	currentCountExec--
+	host.release(identifier, succeeded)
 	mtx.Unlock()
+	signalQueue()
 }
@@ -0,0 +1,142 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestServer_nextQueueElement(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := executionIdentifier{GitRef: "a", Source: "cron", Workload: "oltp", UUID: "0"}
+
+	newElement := func(gitRef, source, uuid string) *executionQueueElement {
+		return &executionQueueElement{
+			identifier: executionIdentifier{GitRef: gitRef, Source: source, Workload: "oltp", UUID: uuid},
+			addedAt:    now,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		elements       []*executionQueueElement
+		wantUUID       string
+		wantLastIsSame bool
+		wantNextIsSame bool
+		wantRetryAt    time.Time
+	}{
+		{
+			name:     "Empty queue",
+			elements: nil,
+		},
+		{
+			name: "Highest priority first",
+			elements: []*executionQueueElement{
+				newElement("b", "cron", "1"),
+				newElement("c", "admin", "2"),
+			},
+			wantUUID: "2",
+		},
+		{
+			name: "Same configuration as the last execution",
+			elements: []*executionQueueElement{
+				newElement("b", "cron", "1"),
+				newElement("a", "cron", "2"),
+				newElement("a", "cron", "3"),
+			},
+			wantUUID:       "2",
+			wantLastIsSame: true,
+			wantNextIsSame: true,
+		},
+		{
+			name: "Same configuration as the last execution but less important",
+			elements: []*executionQueueElement{
+				newElement("a", "cron", "1"),
+				newElement("b", "admin", "2"),
+			},
+			wantUUID: "2",
+		},
+		{
+			name: "Waiting for retry",
+			elements: []*executionQueueElement{
+				{identifier: executionIdentifier{GitRef: "b", UUID: "1"}, retryAt: now.Add(time.Minute)},
+				{identifier: executionIdentifier{GitRef: "c", UUID: "2"}, retryAt: now.Add(time.Hour)},
+			},
+			wantRetryAt: now.Add(time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			queue = make(executionQueue)
+			for _, element := range tt.elements {
+				queue[element.identifier] = element
+			}
+			s := &Server{
+				hosts:          newHostPool([]string{"10.0.0.1"}),
+				priorityPolicy: weightedPriorityPolicy{sourceWeights: map[string]int{"admin": 10, "cron": 1}},
+			}
+			s.hosts[0].release(last, true)
+
+			element, lastIsSame, nextIsSame, retryAt := s.nextQueueElement(s.hosts[0], now)
+			if tt.wantUUID == "" {
+				c.Assert(element, qt.IsNil)
+			} else {
+				c.Assert(element, qt.IsNotNil)
+				c.Assert(element.identifier.UUID, qt.Equals, tt.wantUUID)
+			}
+			c.Assert(lastIsSame, qt.Equals, tt.wantLastIsSame)
+			c.Assert(nextIsSame, qt.Equals, tt.wantNextIsSame)
+			c.Assert(retryAt, qt.Equals, tt.wantRetryAt)
+		})
+	}
+}
+
+func TestServer_cronExecutionQueueWatcherShutdown(t *testing.T) {
+	c := qt.New(t)
+
+	queue = make(executionQueue)
+	s := &Server{
+		hosts:          newHostPool([]string{"10.0.0.1"}),
+		priorityPolicy: weightedPriorityPolicy{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.cronExecutionQueueWatcher(ctx)
+		close(done)
+	}()
+
+	// Waking up the watcher with an empty queue must not dispatch anything.
+	signalQueue()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		c.Fatal("the queue watcher did not stop after its context was canceled")
+	}
+	c.Assert(s.hosts[0].busy, qt.IsFalse)
+}
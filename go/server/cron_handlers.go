@@ -19,44 +19,36 @@
 package server
 
 import (
+	"fmt"
+	"path"
 	"strings"
 
 	"github.com/vitessio/arewefastyet/go/exec"
 	"github.com/vitessio/arewefastyet/go/tools/git"
-	"github.com/vitessio/arewefastyet/go/tools/macrobench"
 )
 
-func (s *Server) branchCronHandler() {
+// branchCronHandler updates the local clone of Vitess and queues the elements
+// created by the given function for the branches of the schedule.
+func (s *Server) branchCronHandler(sc schedule, createElements func(sc schedule) ([]*executionQueueElement, error)) error {
 	// update the local clone of vitess from remote
 	s.vitessPathMu.Lock()
 	defer s.vitessPathMu.Unlock()
 	err := s.pullLocalVitess()
 	if err != nil {
-		slog.Error(err.Error())
-		return
+		return err
 	}
 
-	mainBranchElements, err := s.mainBranchCronHandler()
+	execElements, err := createElements(sc)
 	if err != nil {
-		slog.Error(err.Error())
-		return
+		return err
 	}
-
-	releaseBranchElements, err := s.releaseBranchesCronHandler()
-	if err != nil {
-		slog.Error(err.Error())
-		return
-	}
-
-	execElements := append(mainBranchElements, releaseBranchElements...)
 	for _, elem := range execElements {
 		s.addToQueue(elem)
 	}
+	return nil
 }
 
-func (s *Server) mainBranchCronHandler() ([]*executionQueueElement, error) {
-	var elements []*executionQueueElement
-	configs := s.getConfigFiles()
+func (s *Server) mainBranchCronHandler(sc schedule) ([]*executionQueueElement, error) {
 	vitessPath := s.getVitessPath()
 
 	// getting the latest commit hash from local fork of Vitess
@@ -73,40 +65,20 @@ func (s *Server) mainBranchCronHandler() ([]*executionQueueElement, error) {
 	currVersion := git.Version{Major: lastRelease.Version.Major + 1}
 
 	// We compare main with the previous hash of main and with the latest release
-	for workload, config := range configs {
-		if config.skip {
-			continue
-		}
-		if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > currVersion.Major {
-			continue
-		}
-		if workload == "micro" {
-			_, previousGitRef, err := exec.GetPreviousFromSourceMicrobenchmark(s.dbClient, exec.SourceCron, ref)
-			if err != nil {
-				slog.Warn(err.Error())
-				continue
-			}
-			elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, "", exec.SourceCron, lastRelease, currVersion)...)
-		} else {
-			for _, version := range git.GetPlannerVersions() {
-				_, previousGitRef, err := exec.GetPreviousFromSourceMacrobenchmark(s.dbClient, exec.SourceCron, workload, string(version), ref)
-				if err != nil {
-					slog.Warn(err.Error())
-					continue
-				}
-				elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, string(version), exec.SourceCron, lastRelease, currVersion)...)
-			}
-		}
-	}
-	return elements, nil
+	return s.createBranchElements(sc, ref, exec.SourceCron, lastRelease, currVersion), nil
 }
 
-func (s *Server) releaseBranchesCronHandler() ([]*executionQueueElement, error) {
+func (s *Server) releaseBranchesCronHandler(sc schedule) ([]*executionQueueElement, error) {
 	var elements []*executionQueueElement
-	configs := s.getConfigFiles()
 	vitesLocalPath := s.getVitessPath()
 
-	releases, err := git.GetLatestVitessReleaseBranchCommitHash(vitesLocalPath)
+	var releases []*git.Release
+	var err error
+	if sc.Branch == "" {
+		releases, err = git.GetLatestVitessReleaseBranchCommitHash(vitesLocalPath)
+	} else {
+		releases, err = git.GetAllVitessReleaseBranchCommitHash(vitesLocalPath)
+	}
 	if err != nil {
 		slog.Warn(err.Error())
 		return nil, err
@@ -114,6 +86,11 @@ func (s *Server) releaseBranchesCronHandler() ([]*executionQueueElement, error)
 
 	// We compare release-branches with the previous hash of that branch and with the latest patch release of that version
 	for _, release := range releases {
+		if sc.Branch != "" {
+			if matched, _ := path.Match(sc.Branch, strings.TrimSuffix(release.Name, "-branch")); !matched {
+				continue
+			}
+		}
 		ref := release.CommitHash
 		source := exec.SourceReleaseBranch + release.Name
 		lastPatchRelease, err := git.GetLastestPatchReleaseOfGivenMajorVersion(vitesLocalPath, release.Version)
@@ -126,39 +103,81 @@ func (s *Server) releaseBranchesCronHandler() ([]*executionQueueElement, error)
 			currVersion = lastPatchRelease.Version
 			currVersion.Patch += 1
 		}
+		elements = append(elements, s.createBranchElements(sc, ref, source, lastPatchRelease, currVersion)...)
+	}
+	return elements, nil
+}
 
-		for workload, config := range configs {
-			if config.skip {
-				continue
-			}
-			if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > currVersion.Major {
-				continue
-			}
+// customBranchCronHandler benchmarks the head of the branch of the schedule. Its benchmarks
+// use the same kind of source as the release branches: "cron_{branch}-branch".
+func (s *Server) customBranchCronHandler(sc schedule) ([]*executionQueueElement, error) {
+	vitessPath := s.getVitessPath()
 
-			if workload == "micro" {
-				_, previousGitRef, err := exec.GetPreviousFromSourceMicrobenchmark(s.dbClient, source, ref)
+	ref, err := git.GetCommitHashOfBranch(vitessPath, sc.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	lastRelease, err := git.GetLastestRelease(vitessPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// A branch that is neither based on main nor on a release branch is considered
+	// to be on the next version, like main.
+	currVersion, err := git.GetVersionForCommitSHA(vitessPath, ref)
+	if err != nil {
+		currVersion = git.Version{Major: lastRelease.Version.Major + 1}
+	}
+	if currVersion.Major <= lastRelease.Version.Major {
+		lastRelease, err = git.GetLastestPatchReleaseOfGivenMajorVersion(vitessPath, git.Version{Major: currVersion.Major})
+		if err != nil {
+			slog.Warn(err.Error())
+			lastRelease = nil
+		}
+	}
+	return s.createBranchElements(sc, ref, exec.SourceReleaseBranch+sc.Branch+"-branch", lastRelease, currVersion), nil
+}
+
+// createBranchElements creates the elements benchmarking ref on every workload and planner
+// version of the schedule, along with the elements they are compared with.
+func (s *Server) createBranchElements(sc schedule, ref, source string, lastRelease *git.Release, version git.Version) []*executionQueueElement {
+	var elements []*executionQueueElement
+	if !sc.comparesWith(compareWithRelease) {
+		lastRelease = nil
+	}
+
+	for workload, config := range sc.workloads(s.getConfigFiles()) {
+		if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > version.Major {
+			continue
+		}
+		if workload == "micro" {
+			var previousGitRef string
+			if sc.comparesWith(compareWithPrevious) {
+				var err error
+				_, previousGitRef, err = exec.GetPreviousFromSourceMicrobenchmark(s.dbClient, source, ref)
 				if err != nil {
 					slog.Warn(err.Error())
 					continue
 				}
-
-				elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, "", source, lastPatchRelease, currVersion)...)
-			} else {
-				versions := git.GetPlannerVersions()
-
-				for _, version := range versions {
-					_, previousGitRef, err := exec.GetPreviousFromSourceMacrobenchmark(s.dbClient, source, workload, string(version), ref)
-					if err != nil {
-						slog.Warn(err.Error())
-						continue
-					}
-
-					elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, string(version), source, lastPatchRelease, currVersion)...)
+			}
+			elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, "", source, lastRelease, version)...)
+			continue
+		}
+		for _, plannerVersion := range sc.PlannerVersions {
+			var previousGitRef string
+			if sc.comparesWith(compareWithPrevious) {
+				var err error
+				_, previousGitRef, err = exec.GetPreviousFromSourceMacrobenchmark(s.dbClient, source, workload, plannerVersion, ref)
+				if err != nil {
+					slog.Warn(err.Error())
+					continue
 				}
 			}
+			elements = append(elements, s.createBranchElementWithComparisonOnPreviousAndRelease(config, ref, workload, previousGitRef, plannerVersion, source, lastRelease, version)...)
 		}
 	}
-	return elements, nil
+	return elements
 }
 
 func (s *Server) createBranchElementWithComparisonOnPreviousAndRelease(config benchmarkConfig, ref, workload, previousGitRef, plannerVersion, source string, lastRelease *git.Release, version git.Version) []*executionQueueElement {
@@ -173,6 +192,7 @@ func (s *Server) createBranchElementWithComparisonOnPreviousAndRelease(config be
 		// creating an execution queue element for the latest benchmark with SourceCron as source
 		// this will not be executed since the benchmark already exist, we still create the element in order to compare
 		previousElement := s.createSimpleExecutionQueueElement(config, source, previousGitRef, workload, plannerVersion, false, 0, version, nil)
+		previousElement.baseline = true
 		previousElement.compareWith = append(previousElement.compareWith, newExecutionElement.identifier)
 		newExecutionElement.compareWith = append(newExecutionElement.compareWith, previousElement.identifier)
 		elements = append(elements, previousElement)
@@ -182,6 +202,7 @@ func (s *Server) createBranchElementWithComparisonOnPreviousAndRelease(config be
 		// creating an execution queue element for the latest release (comparing branch with the latest release)
 		// this will probably not be executed the benchmark should already exist, we still create it to compare main once its benchmark is over
 		lastReleaseElement := s.createSimpleExecutionQueueElement(config, exec.SourceTag+lastRelease.Name, lastRelease.CommitHash, workload, plannerVersion, false, 0, lastRelease.Version, nil)
+		lastReleaseElement.baseline = true
 		lastReleaseElement.compareWith = append(lastReleaseElement.compareWith, newExecutionElement.identifier)
 		newExecutionElement.compareWith = append(newExecutionElement.compareWith, lastReleaseElement.identifier)
 		elements = append(elements, lastReleaseElement)
@@ -189,86 +210,77 @@ func (s *Server) createBranchElementWithComparisonOnPreviousAndRelease(config be
 	return elements
 }
 
-func (s *Server) pullRequestsCronHandler() {
-	vitesLocalPath := s.getVitessPath()
-	configs := s.getConfigFiles()
-	prLabelsInfo := []struct {
-		label   string
-		useGen4 bool
-	}{
-		{label: s.prLabelTrigger, useGen4: true},
-		{label: s.prLabelTriggerV3, useGen4: false},
+func (s *Server) pullRequestsCronHandler(sc schedule) error {
+	// The GitHub search API needs quotes around labels that contain spaces.
+	prInfos, err := git.GetPullRequestsFromGitHub([]string{fmt.Sprintf("%q", sc.Label)}, vitessRepository)
+	if err != nil {
+		githubAPIErrors.WithLabelValues("search_pull_requests").Inc()
+		return err
 	}
 
 	var elements []*executionQueueElement
-
-	for _, labelInfo := range prLabelsInfo {
-		prInfos, err := git.GetPullRequestsFromGitHub([]string{labelInfo.label}, "vitessio/vitess")
+	for _, prInfo := range prInfos {
+		if prInfo.SHA == "" || prInfo.Number == 0 {
+			continue
+		}
+		prElements, err := s.createPullRequestElements(sc, prInfo.SHA, prInfo.Base, prInfo.Number)
 		if err != nil {
 			slog.Warn(err)
 			continue
 		}
+		elements = append(elements, prElements...)
+	}
+	s.addPullRequestElementsToQueue(elements)
+	return nil
+}
 
-		for _, prInfo := range prInfos {
-			ref := prInfo.SHA
-			previousGitRef := prInfo.Base
-			pullNb := prInfo.Number
-			if ref == "" || pullNb == 0 {
-				continue
-			}
-			currVersion, err := git.GetVersionForCommitSHA(vitesLocalPath, previousGitRef)
-			if err != nil {
-				slog.Warn(err)
-				continue
-			}
-			for workload, config := range configs {
-				if config.skip {
-					continue
-				}
-				if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > currVersion.Major {
-					continue
-				}
+// createPullRequestElements creates the queue elements used to benchmark the given head
+// of a pull request on every workload of the schedule. The base is used to know which
+// version of Vitess the pull request targets.
+func (s *Server) createPullRequestElements(sc schedule, ref, base string, pullNb int) ([]*executionQueueElement, error) {
+	currVersion, err := git.GetVersionForCommitSHA(s.getVitessPath(), base)
+	if err != nil {
+		return nil, err
+	}
 
-				if workload == "micro" {
-					elements = append(elements, s.createPullRequestElement(config, ref, workload, "", pullNb, currVersion))
-				} else {
-					versions := []macrobench.PlannerVersion{macrobench.V3Planner}
-					if labelInfo.useGen4 {
-						versions = []macrobench.PlannerVersion{macrobench.Gen4Planner}
-					}
-					for _, version := range versions {
-						elements = append(elements, s.createPullRequestElement(config, ref, workload, version, pullNb, currVersion))
-					}
-				}
+	var elements []*executionQueueElement
+	for workload, config := range sc.workloads(s.getConfigFiles()) {
+		if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > currVersion.Major {
+			continue
+		}
+
+		if workload == "micro" {
+			elements = append(elements, s.createPullRequestElement(config, ref, workload, "", pullNb, currVersion))
+		} else {
+			for _, plannerVersion := range sc.PlannerVersions {
+				elements = append(elements, s.createPullRequestElement(config, ref, workload, plannerVersion, pullNb, currVersion))
 			}
 		}
 	}
+	return elements, nil
+}
+
+// addPullRequestElementsToQueue adds the given elements to the queue, after removing the
+// pending elements of the same pull requests that benchmark an outdated head.
+func (s *Server) addPullRequestElementsToQueue(elements []*executionQueueElement) {
 	for _, element := range elements {
 		s.removePRFromQueue(element)
 		s.addToQueue(element)
 	}
 }
 
-func (s *Server) createPullRequestElement(config benchmarkConfig, ref, workload string, plannerVersion macrobench.PlannerVersion, pullNb int, gitVersion git.Version) *executionQueueElement {
-	return s.createSimpleExecutionQueueElement(config, exec.SourcePullRequest, ref, workload, string(plannerVersion), true, pullNb, gitVersion, nil)
-}
-
-func (s *Server) tagsCronHandler() {
+func (s *Server) tagsCronHandler(sc schedule) error {
 	// update the local clone of vitess from remote
 	s.vitessPathMu.Lock()
 	defer s.vitessPathMu.Unlock()
 	err := s.pullLocalVitess()
 	if err != nil {
-		slog.Error(err.Error())
-		return
+		return err
 	}
 
-	configs := s.getConfigFiles()
-
 	releases, err := git.GetSupportedVitessReleases(s.getVitessPath())
 	if err != nil {
-		slog.Error(err)
-		return
+		return err
 	}
 
 	var elements []*executionQueueElement
@@ -276,19 +288,15 @@ func (s *Server) tagsCronHandler() {
 	// We add single executions for the tags, we do not compare them against anything
 	for _, release := range releases {
 		source := exec.SourceTag + release.Name
-		for workload, config := range configs {
-			if config.skip {
-				continue
-			}
+		for workload, config := range sc.workloads(s.getConfigFiles()) {
 			if minVersion := config.v.GetInt(keyMinimumVitessVersion); minVersion > release.Version.Major {
 				continue
 			}
 			if workload == "micro" {
 				elements = append(elements, s.createSimpleExecutionQueueElement(config, source, release.CommitHash, workload, "", true, 0, release.Version, nil))
 			} else {
-				versions := git.GetPlannerVersions()
-				for _, version := range versions {
-					elements = append(elements, s.createSimpleExecutionQueueElement(config, source, release.CommitHash, workload, string(version), true, 0, release.Version, nil))
+				for _, plannerVersion := range sc.PlannerVersions {
+					elements = append(elements, s.createSimpleExecutionQueueElement(config, source, release.CommitHash, workload, plannerVersion, true, 0, release.Version, nil))
 				}
 			}
 		}
@@ -296,12 +304,16 @@ func (s *Server) tagsCronHandler() {
 	for _, element := range elements {
 		s.addToQueue(element)
 	}
+	return nil
+}
+
+func (s *Server) createPullRequestElement(config benchmarkConfig, ref, workload, plannerVersion string, pullNb int, gitVersion git.Version) *executionQueueElement {
+	return s.createSimpleExecutionQueueElement(config, exec.SourcePullRequest, ref, workload, plannerVersion, true, pullNb, gitVersion, nil)
 }
 
 func (s *Server) createSimpleExecutionQueueElement(config benchmarkConfig, source, ref, workload, plannerVersion string, notify bool, pullNb int, version git.Version, profileInformation *exec.ProfileInformation) *executionQueueElement {
 	return &executionQueueElement{
 		config:       config,
-		retry:        s.cronNbRetry,
 		notifyAlways: notify,
 		identifier: executionIdentifier{
 			GitRef:         ref,
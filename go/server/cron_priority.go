@@ -0,0 +1,235 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultExecutionDuration is the duration used to estimate when an element of the
+	// queue will start if we have no history for its workload.
+	defaultExecutionDuration = 1 * time.Hour
+)
+
+var (
+	defaultQueueSourceWeights = []string{"admin=1000", "cron_pr=300", "bisect=250", "custom_run=200", "cron=100", "cron_*=50", "cron_tags_*=10"}
+)
+
+type (
+	// queuePriorityPolicy decides in which order the elements of the execution queue
+	// are executed. The element with the highest priority is executed first.
+	queuePriorityPolicy interface {
+		// sourcePriority returns the priority given to the element solely based
+		// on what it is benchmarking, without taking its age into account.
+		sourcePriority(element *executionQueueElement) float64
+
+		// priority returns the full priority of the element at the given time.
+		priority(element *executionQueueElement, now time.Time) float64
+	}
+
+	// weightedPriorityPolicy is a queuePriorityPolicy that computes the priority of an
+	// element by adding a configurable weight for its source, a bonus if the element
+	// benchmarks a pull request, and a weight per hour spent in the queue.
+	weightedPriorityPolicy struct {
+		// sourceWeights maps a source to its weight. A key ending with '*' matches
+		// every source starting with the given prefix, the longest prefix wins.
+		sourceWeights map[string]int
+
+		// pullRequestWeight is added to elements benchmarking a pull request.
+		pullRequestWeight int
+
+		// ageWeight is added to the priority for each hour the element spent in the queue.
+		ageWeight float64
+	}
+
+	// queuedElementEstimate is an element of the queue with its position
+	// in the queue and the time at which we expect it to start.
+	queuedElementEstimate struct {
+		element          *executionQueueElement
+		position         int
+		priority         float64
+		estimatedStartAt time.Time
+	}
+)
+
+// parseSourceWeights parses a list of "source=weight" pairs into a map.
+func parseSourceWeights(pairs []string) (map[string]int, error) {
+	weights := make(map[string]int, len(pairs))
+	for _, pair := range pairs {
+		source, value, found := strings.Cut(pair, "=")
+		if !found || source == "" {
+			return nil, fmt.Errorf("invalid source weight %q, expected the format source=weight", pair)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for source %q: %v", source, err)
+		}
+		weights[source] = weight
+	}
+	return weights, nil
+}
+
+func (p weightedPriorityPolicy) sourceWeight(source string) int {
+	if weight, ok := p.sourceWeights[source]; ok {
+		return weight
+	}
+	var bestPrefix string
+	var bestWeight int
+	for key, weight := range p.sourceWeights {
+		prefix, isPattern := strings.CutSuffix(key, "*")
+		if !isPattern || !strings.HasPrefix(source, prefix) || len(prefix) < len(bestPrefix) {
+			continue
+		}
+		if len(prefix) == len(bestPrefix) && weight <= bestWeight {
+			continue
+		}
+		bestPrefix = prefix
+		bestWeight = weight
+	}
+	return bestWeight
+}
+
+func (p weightedPriorityPolicy) sourcePriority(element *executionQueueElement) float64 {
+	priority := float64(p.sourceWeight(element.identifier.Source))
+	if element.identifier.PullNb != 0 {
+		priority += float64(p.pullRequestWeight)
+	}
+	return priority
+}
+
+func (p weightedPriorityPolicy) priority(element *executionQueueElement, now time.Time) float64 {
+	priority := p.sourcePriority(element)
+	if !element.addedAt.IsZero() && now.After(element.addedAt) {
+		priority += p.ageWeight * now.Sub(element.addedAt).Hours()
+	}
+	return priority
+}
+
+// orderQueueElements sorts the given elements from the one that should be executed first
+// to the one that should be executed last. The order is deterministic: elements with the
+// same priority are ordered by age, and then by their identifier.
+func orderQueueElements(policy queuePriorityPolicy, elements []*executionQueueElement, now time.Time) {
+	priorities := make(map[*executionQueueElement]float64, len(elements))
+	for _, element := range elements {
+		priorities[element] = policy.priority(element, now)
+	}
+	sort.SliceStable(elements, func(i, j int) bool {
+		a, b := elements[i], elements[j]
+		if priorities[a] != priorities[b] {
+			return priorities[a] > priorities[b]
+		}
+		if !a.addedAt.Equal(b.addedAt) {
+			return a.addedAt.Before(b.addedAt)
+		}
+		return a.identifier.less(b.identifier)
+	})
+}
+
+// less compares two identifiers field by field, it is used to break ties when ordering the queue.
+func (ei executionIdentifier) less(id executionIdentifier) bool {
+	fields := [][2]string{
+		{ei.Source, id.Source},
+		{ei.GitRef, id.GitRef},
+		{ei.Workload, id.Workload},
+		{ei.PlannerVersion, id.PlannerVersion},
+		{ei.UUID, id.UUID},
+	}
+	for _, f := range fields {
+		if f[0] != f[1] {
+			return f[0] < f[1]
+		}
+	}
+	return ei.PullNb < id.PullNb
+}
+
+// pendingQueueElements returns all the elements of the queue that are not executing
+// ordered by the given policy. The caller must hold the queue's mutex.
+func pendingQueueElements(policy queuePriorityPolicy, now time.Time) []*executionQueueElement {
+	elements := make([]*executionQueueElement, 0, len(queue))
+	for _, element := range queue {
+		if element.Executing {
+			continue
+		}
+		elements = append(elements, element)
+	}
+	orderQueueElements(policy, elements, now)
+	return elements
+}
+
+// hostAvailability is a min-heap of the times at which each host will be available.
+type hostAvailability []time.Time
+
+func (h hostAvailability) Len() int           { return len(h) }
+func (h hostAvailability) Less(i, j int) bool { return h[i].Before(h[j]) }
+func (h hostAvailability) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *hostAvailability) Push(x any)        { *h = append(*h, x.(time.Time)) }
+func (h *hostAvailability) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// estimateQueue computes the position and the estimated start time of every pending element.
+// The estimation assumes the elements will be executed in the given order on the first host
+// that becomes available, using the given average duration of each workload.
+func estimateQueue(policy queuePriorityPolicy, elements []*executionQueueElement, hosts hostPool, durations map[string]time.Duration, now time.Time) []queuedElementEstimate {
+	durationOf := func(workload string) time.Duration {
+		if d, ok := durations[strings.ToLower(workload)]; ok && d > 0 {
+			return d
+		}
+		return defaultExecutionDuration
+	}
+
+	availability := make(hostAvailability, 0, len(hosts))
+	for _, host := range hosts {
+		availableAt := now
+		if host.busy {
+			availableAt = host.startedAt.Add(durationOf(host.lastExecuted.Workload))
+			if availableAt.Before(now) {
+				availableAt = now
+			}
+		}
+		availability = append(availability, availableAt)
+	}
+	if len(availability) == 0 {
+		availability = append(availability, now)
+	}
+	heap.Init(&availability)
+
+	estimates := make([]queuedElementEstimate, 0, len(elements))
+	for i, element := range elements {
+		startAt := heap.Pop(&availability).(time.Time)
+		estimates = append(estimates, queuedElementEstimate{
+			element:          element,
+			position:         i + 1,
+			priority:         policy.priority(element, now),
+			estimatedStartAt: startAt,
+		})
+		heap.Push(&availability, startAt.Add(durationOf(element.identifier.Workload)))
+	}
+	return estimates
+}
@@ -0,0 +1,169 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestWeightedPriorityPolicy_sourceWeight(t *testing.T) {
+	c := qt.New(t)
+	weights, err := parseSourceWeights(defaultQueueSourceWeights)
+	c.Assert(err, qt.IsNil)
+	policy := weightedPriorityPolicy{sourceWeights: weights}
+
+	tests := []struct {
+		source string
+		want   int
+	}{
+		{source: "admin", want: 1000},
+		{source: "cron_pr", want: 300},
+		{source: "cron", want: 100},
+		{source: "cron_release-18.0", want: 50},
+		{source: "cron_tags_v18.0.0", want: 10},
+		{source: "unknown", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(policy.sourceWeight(tt.source), qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestParseSourceWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]int
+		wantErr bool
+	}{
+		{name: "Valid", pairs: []string{"admin=10", "cron_*=-2"}, want: map[string]int{"admin": 10, "cron_*": -2}},
+		{name: "Missing weight", pairs: []string{"admin"}, wantErr: true},
+		{name: "Invalid weight", pairs: []string{"admin=high"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			got, err := parseSourceWeights(tt.pairs)
+			if tt.wantErr {
+				c.Assert(err, qt.IsNotNil)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestOrderQueueElements(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := weightedPriorityPolicy{
+		sourceWeights:     map[string]int{"admin": 1000, "cron_pr": 300, "cron": 100},
+		pullRequestWeight: 50,
+		ageWeight:         10,
+	}
+	newElement := func(source, gitRef string, pullNb int, age time.Duration) *executionQueueElement {
+		return &executionQueueElement{
+			identifier: executionIdentifier{Source: source, GitRef: gitRef, PullNb: pullNb, Workload: "oltp"},
+			addedAt:    now.Add(-age),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		elements []*executionQueueElement
+		want     []string
+	}{
+		{
+			name: "Source weights",
+			elements: []*executionQueueElement{
+				newElement("cron", "a", 0, 0),
+				newElement("cron_pr", "b", 1, 0),
+				newElement("admin", "c", 0, 0),
+			},
+			want: []string{"c", "b", "a"},
+		},
+		{
+			name: "Old elements catch up",
+			elements: []*executionQueueElement{
+				newElement("cron_pr", "a", 1, 0),
+				newElement("cron", "b", 0, 30*time.Hour),
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "Pull request bonus",
+			elements: []*executionQueueElement{
+				newElement("cron", "a", 0, 0),
+				newElement("cron", "b", 1, 0),
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "Ties are broken by identifier",
+			elements: []*executionQueueElement{
+				newElement("cron", "c", 0, time.Hour),
+				newElement("cron", "a", 0, time.Hour),
+				newElement("cron", "b", 0, time.Hour),
+			},
+			want: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			orderQueueElements(policy, tt.elements, now)
+			got := make([]string, 0, len(tt.elements))
+			for _, element := range tt.elements {
+				got = append(got, element.identifier.GitRef)
+			}
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestEstimateQueue(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := weightedPriorityPolicy{}
+	hosts := newHostPool([]string{"10.0.0.1", "10.0.0.2"})
+	hosts[0].lease(executionIdentifier{Workload: "tpcc"}, false)
+	hosts[0].startedAt = now.Add(-10 * time.Minute)
+
+	elements := []*executionQueueElement{
+		{identifier: executionIdentifier{GitRef: "a", Workload: "OLTP"}},
+		{identifier: executionIdentifier{GitRef: "b", Workload: "oltp"}},
+		{identifier: executionIdentifier{GitRef: "c", Workload: "micro"}},
+	}
+	durations := map[string]time.Duration{"oltp": 20 * time.Minute, "tpcc": 30 * time.Minute}
+
+	estimates := estimateQueue(policy, elements, hosts, durations, now)
+	c.Assert(estimates, qt.HasLen, 3)
+	c.Assert(estimates[0].position, qt.Equals, 1)
+	c.Assert(estimates[0].estimatedStartAt, qt.Equals, now)
+	c.Assert(estimates[1].estimatedStartAt, qt.Equals, now.Add(20*time.Minute))
+	c.Assert(estimates[2].estimatedStartAt, qt.Equals, now.Add(20*time.Minute))
+}
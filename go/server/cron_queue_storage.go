@@ -0,0 +1,296 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+// queueOperation is a change of an element of the queue that is persisted in the execution_queue table.
+type queueOperation int
+
+const (
+	queueInsert queueOperation = iota
+	queueUpdate
+	queueDelete
+)
+
+// queueWrite is a change of an element of the queue waiting to be persisted, along with
+// the persisted fields of the element at the time of the change.
+type queueWrite struct {
+	operation queueOperation
+	element   *executionQueueElement
+	row       queueRow
+}
+
+// queueRow holds the persisted fields of an element of the queue, the comparisons and the
+// failures of the element are encoded in JSON.
+type queueRow struct {
+	identifier                        executionIdentifier
+	compareWith, failures             string
+	retryAt, addedAt                  time.Time
+	notifyAlways, baseline, executing bool
+}
+
+var (
+	// queueWrites are the changes of the queue waiting to be persisted, in the order in which
+	// they were made. They are recorded while holding the queue's mutex but are guarded by their
+	// own mutex, the database is written by the queue writer once the queue's mutex is released.
+	queueWritesMtx    sync.Mutex
+	queueWrites       []queueWrite
+	queueWritesSignal = make(chan struct{}, 1)
+
+	// queueFlushMtx serializes the flushes of the writes of the queue, which keeps them in order.
+	// The queueID of the elements is only read and written while holding it.
+	queueFlushMtx sync.Mutex
+)
+
+// persistQueueElement records the given change of the element, it is persisted later by the
+// queue writer. The caller holds the queue's mutex, the fields of the element are read right away.
+func persistQueueElement(operation queueOperation, element *executionQueueElement) {
+	write := queueWrite{operation: operation, element: element}
+	if operation != queueDelete {
+		row, err := newQueueRow(element)
+		if err != nil {
+			slog.Error(err)
+			return
+		}
+		write.row = row
+	}
+
+	queueWritesMtx.Lock()
+	queueWrites = append(queueWrites, write)
+	queueWritesMtx.Unlock()
+
+	select {
+	case queueWritesSignal <- struct{}{}:
+	default:
+	}
+}
+
+func newQueueRow(element *executionQueueElement) (queueRow, error) {
+	compareWith, err := json.Marshal(element.compareWith)
+	if err != nil {
+		return queueRow{}, err
+	}
+	failures, err := json.Marshal(element.failures)
+	if err != nil {
+		return queueRow{}, err
+	}
+	return queueRow{
+		identifier:   element.identifier,
+		compareWith:  string(compareWith),
+		failures:     string(failures),
+		retryAt:      element.retryAt,
+		addedAt:      element.addedAt,
+		notifyAlways: element.notifyAlways,
+		baseline:     element.baseline,
+		executing:    element.Executing,
+	}, nil
+}
+
+// flushQueueWrites persists the pending changes of the queue in the execution_queue table.
+func (s *Server) flushQueueWrites() {
+	queueFlushMtx.Lock()
+	defer queueFlushMtx.Unlock()
+
+	queueWritesMtx.Lock()
+	writes := queueWrites
+	queueWrites = nil
+	queueWritesMtx.Unlock()
+
+	for _, write := range writes {
+		switch write.operation {
+		case queueInsert:
+			s.insertQueueElementToDB(write.element, write.row)
+		case queueUpdate:
+			s.updateQueueElementInDB(write.element, write.row)
+		case queueDelete:
+			s.deleteQueueElementFromDB(write.element)
+		}
+	}
+}
+
+// queueWriter persists the changes of the queue as they are made, until the given
+// context is done. The last changes are persisted before returning.
+func (s *Server) queueWriter(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.flushQueueWrites()
+			return
+		case <-queueWritesSignal:
+			s.flushQueueWrites()
+		}
+	}
+}
+
+// loadQueueFromDB reads the execution_queue table and adds every element it finds to
+// the in-memory queue. Elements that were executing when the server stopped are marked
+// as failed with an infrastructure failure, they are then either retried with a new UUID
+// or removed from the queue if the retry policy does not allow another retry.
+func (s *Server) loadQueueFromDB() error {
+	rows, err := s.dbClient.Read("SELECT id, IFNULL(uuid, ''), git_ref, source, workload, IFNULL(planner_version, ''), pull_nb, IFNULL(pull_base_ref, ''), " +
+		"version_major, version_minor, version_patch, profile_binary, profile_mode, IFNULL(compare_with, ''), IFNULL(failures, ''), retry_at, notify_always, baseline, executing, created_at FROM execution_queue ORDER BY id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var elements []*executionQueueElement
+	for rows.Next() {
+		var (
+			element                    executionQueueElement
+			profileBinary, profileMode sql.NullString
+			compareWith, failures      string
+			retryAt, createdAt         sql.NullTime
+		)
+		id := &element.identifier
+		err = rows.Scan(&element.queueID, &id.UUID, &id.GitRef, &id.Source, &id.Workload, &id.PlannerVersion, &id.PullNb, &id.PullBaseRef,
+			&id.Version.Major, &id.Version.Minor, &id.Version.Patch, &profileBinary, &profileMode, &compareWith, &failures, &retryAt, &element.notifyAlways, &element.baseline, &element.Executing, &createdAt)
+		if err != nil {
+			return err
+		}
+		if createdAt.Valid {
+			element.addedAt = createdAt.Time
+		}
+		if retryAt.Valid {
+			element.retryAt = retryAt.Time
+		}
+		if profileBinary.Valid {
+			id.Profile = &exec.ProfileInformation{Binary: profileBinary.String, Mode: profileMode.String}
+		}
+		if compareWith != "" {
+			err = json.Unmarshal([]byte(compareWith), &element.compareWith)
+			if err != nil {
+				return err
+			}
+		}
+		if failures != "" {
+			err = json.Unmarshal([]byte(failures), &element.failures)
+			if err != nil {
+				return err
+			}
+		}
+		elements = append(elements, &element)
+	}
+	rows.Close()
+
+	// The changes made while reloading the queue are persisted once it is reloaded.
+	defer s.flushQueueWrites()
+	mtx.Lock()
+	defer mtx.Unlock()
+	for _, element := range elements {
+		config, ok := s.benchmarkConfig[strings.ToLower(element.identifier.Workload)]
+		if !ok {
+			slog.Warnf("unknown workload for %+v, removing it from the queue", element.identifier)
+			persistQueueElement(queueDelete, element)
+			continue
+		}
+		element.config = config
+
+		// The element was being executed when the server stopped, the execution
+		// was interrupted, we mark it as failed and retry it if we can.
+		if element.Executing {
+			if element.identifier.UUID != "" {
+				if err := exec.MarkAsFailed(s.dbClient, element.identifier.UUID, exec.FailureInfrastructure, "execution interrupted by a restart of the server"); err != nil {
+					slog.Error(err)
+				}
+			}
+			if !s.scheduleRetry(element, exec.FailureInfrastructure, time.Now()) {
+				slog.Infof("%+v was interrupted and has no retry left, removing it from the queue", element.identifier)
+				persistQueueElement(queueDelete, element)
+				continue
+			}
+			element.Executing = false
+			element.identifier.UUID = uuid.NewString()
+			persistQueueElement(queueUpdate, element)
+			slog.Infof("%+v was interrupted, it will be retried", element.identifier)
+		}
+
+		if _, found := queue[element.identifier]; found {
+			persistQueueElement(queueDelete, element)
+			continue
+		}
+		queue[element.identifier] = element
+	}
+	slog.Infof("%d elements were reloaded in the queue", len(queue))
+	return nil
+}
+
+// insertQueueElementToDB persists the given element in the execution_queue table with the given row.
+// Errors are logged but not returned, the element will still live in the in-memory queue.
+func (s *Server) insertQueueElementToDB(element *executionQueueElement, row queueRow) {
+	var profileBinary, profileMode sql.NullString
+	if row.identifier.Profile != nil {
+		profileBinary = sql.NullString{String: row.identifier.Profile.Binary, Valid: true}
+		profileMode = sql.NullString{String: row.identifier.Profile.Mode, Valid: true}
+	}
+	id := row.identifier
+	var err error
+	query := "INSERT INTO execution_queue(uuid, git_ref, source, workload, planner_version, pull_nb, pull_base_ref, version_major, version_minor, version_patch, " +
+		"profile_binary, profile_mode, compare_with, failures, retry_at, notify_always, baseline, executing, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	element.queueID, err = s.dbClient.Write(query, id.UUID, id.GitRef, id.Source, id.Workload, id.PlannerVersion, id.PullNb, id.PullBaseRef,
+		id.Version.Major, id.Version.Minor, id.Version.Patch, profileBinary, profileMode, row.compareWith, row.failures, nullTime(row.retryAt), row.notifyAlways, row.baseline, row.executing, row.addedAt.UTC())
+	if err != nil {
+		slog.Error(err)
+	}
+}
+
+// updateQueueElementInDB updates the mutable fields (UUID, comparisons, failures, retry time
+// and executing) of the given element in the execution_queue table with the given row.
+func (s *Server) updateQueueElementInDB(element *executionQueueElement, row queueRow) {
+	if element.queueID == 0 {
+		return
+	}
+	_, err := s.dbClient.Write("UPDATE execution_queue SET uuid = ?, compare_with = ?, failures = ?, retry_at = ?, executing = ? WHERE id = ?",
+		row.identifier.UUID, row.compareWith, row.failures, nullTime(row.retryAt), row.executing, element.queueID)
+	if err != nil {
+		slog.Error(err)
+	}
+}
+
+// nullTime converts the given time to a UTC sql.NullTime, zero times are stored as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// deleteQueueElementFromDB removes the given element from the execution_queue table.
+func (s *Server) deleteQueueElementFromDB(element *executionQueueElement) {
+	if element.queueID == 0 {
+		return
+	}
+	_, err := s.dbClient.Write("DELETE FROM execution_queue WHERE id = ?", element.queueID)
+	if err != nil {
+		slog.Error(err)
+		return
+	}
+	element.queueID = 0
+}
@@ -0,0 +1,209 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+	"go.uber.org/zap"
+)
+
+// newTestServer returns a Server using a SQLite database, knowing the OLTP workload,
+// and retrying the infrastructure failures once. It empties the queue.
+func newTestServer(c *qt.C) *Server {
+	SetSLogger(zap.NewNop().Sugar())
+	cfg := sqlite.Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}
+	client, err := cfg.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+
+	queue = make(executionQueue)
+	queueWrites = nil
+	c.Cleanup(func() {
+		queue = make(executionQueue)
+		queueWrites = nil
+	})
+	return &Server{
+		dbClient:        client,
+		benchmarkConfig: map[string]benchmarkConfig{"oltp": {}},
+		retryPolicies:   retryPolicies{exec.FailureInfrastructure: {retries: 1, backoff: time.Minute}},
+	}
+}
+
+func TestServer_queueStorage(t *testing.T) {
+	c := qt.New(t)
+	s := newTestServer(c)
+
+	addedAt := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	previous := executionIdentifier{GitRef: "a", Source: exec.SourceCron, Workload: "OLTP", PlannerVersion: "gen4", UUID: "previous"}
+	compared := &executionQueueElement{
+		identifier:   executionIdentifier{GitRef: "b", Source: exec.SourceCron, Workload: "OLTP", PlannerVersion: "gen4", UUID: "compared", Version: git.Version{Major: 20}},
+		notifyAlways: true,
+		addedAt:      addedAt,
+	}
+	profiled := &executionQueueElement{
+		identifier: executionIdentifier{GitRef: "c", Source: exec.SourcePullRequest, Workload: "OLTP", PullNb: 42, PullBaseRef: "main", UUID: "profiled",
+			Profile: &exec.ProfileInformation{Binary: "vtgate", Mode: "cpu"}},
+		baseline: true,
+		addedAt:  addedAt,
+	}
+	interrupted := &executionQueueElement{
+		identifier: executionIdentifier{GitRef: "d", Source: exec.SourceCron, Workload: "OLTP", UUID: "interrupted"},
+		addedAt:    addedAt,
+	}
+	exhausted := &executionQueueElement{
+		identifier: executionIdentifier{GitRef: "e", Source: exec.SourceCron, Workload: "OLTP", UUID: "exhausted"},
+		failures:   map[exec.FailureCategory]int{exec.FailureInfrastructure: 1},
+		addedAt:    addedAt,
+	}
+	removed := &executionQueueElement{identifier: executionIdentifier{GitRef: "f", Source: exec.SourceCron, Workload: "OLTP", UUID: "removed"}, addedAt: addedAt}
+	unknown := &executionQueueElement{identifier: executionIdentifier{GitRef: "g", Source: exec.SourceCron, Workload: "TPCC", UUID: "unknown"}, addedAt: addedAt}
+
+	for _, element := range []*executionQueueElement{compared, profiled, interrupted, exhausted, removed, unknown} {
+		persistQueueElement(queueInsert, element)
+	}
+	s.flushQueueWrites()
+	for _, element := range []*executionQueueElement{compared, profiled, interrupted, exhausted, removed, unknown} {
+		c.Assert(element.queueID, qt.Not(qt.Equals), int64(0))
+	}
+
+	// The mutable fields are updated, and the interrupted elements were executing when the server stopped.
+	retryAt := time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC)
+	compared.compareWith = []executionIdentifier{previous}
+	compared.failures = map[exec.FailureCategory]int{exec.FailureTimeout: 1}
+	compared.retryAt = retryAt
+	persistQueueElement(queueUpdate, compared)
+	for _, element := range []*executionQueueElement{interrupted, exhausted} {
+		element.Executing = true
+		persistQueueElement(queueUpdate, element)
+	}
+	persistQueueElement(queueDelete, removed)
+	s.flushQueueWrites()
+	c.Assert(removed.queueID, qt.Equals, int64(0))
+
+	c.Assert(s.loadQueueFromDB(), qt.IsNil)
+	c.Assert(queue, qt.HasLen, 3)
+
+	got := queueElementByGitRef("b")
+	c.Assert(got, qt.IsNotNil)
+	c.Assert(got.queueID, qt.Equals, compared.queueID)
+	c.Assert(got.compareWith, qt.DeepEquals, []executionIdentifier{previous})
+	c.Assert(got.failures, qt.DeepEquals, map[exec.FailureCategory]int{exec.FailureTimeout: 1})
+	c.Assert(got.retryAt.Equal(retryAt), qt.IsTrue, qt.Commentf("retry at %s", got.retryAt))
+	c.Assert(got.addedAt.Equal(addedAt), qt.IsTrue, qt.Commentf("added at %s", got.addedAt))
+	c.Assert(got.notifyAlways, qt.IsTrue)
+	c.Assert(got.baseline, qt.IsFalse)
+	c.Assert(got.Executing, qt.IsFalse)
+
+	got = queueElementByGitRef("c")
+	c.Assert(got, qt.IsNotNil)
+	c.Assert(got.identifier.Profile, qt.DeepEquals, &exec.ProfileInformation{Binary: "vtgate", Mode: "cpu"})
+	c.Assert(got.compareWith, qt.HasLen, 0)
+	c.Assert(got.baseline, qt.IsTrue)
+
+	// The interrupted element is retried with a new UUID, the one without retry left is removed.
+	retried := queueElementByGitRef("d")
+	c.Assert(retried, qt.IsNotNil)
+	c.Assert(retried.identifier.UUID, qt.Not(qt.Equals), "interrupted")
+	c.Assert(retried.Executing, qt.IsFalse)
+	c.Assert(retried.failures, qt.DeepEquals, map[exec.FailureCategory]int{exec.FailureInfrastructure: 1})
+	c.Assert(retried.retryAt.IsZero(), qt.IsFalse)
+
+	// Reloading the queue again restores the same elements, with the retried UUID persisted.
+	queue = make(executionQueue)
+	c.Assert(s.loadQueueFromDB(), qt.IsNil)
+	c.Assert(queue, qt.HasLen, 3)
+	reloaded := queueElementByGitRef("d")
+	c.Assert(reloaded, qt.IsNotNil)
+	c.Assert(reloaded.identifier.UUID, qt.Equals, retried.identifier.UUID)
+	c.Assert(reloaded.Executing, qt.IsFalse)
+}
+
+func TestServer_queueWriter(t *testing.T) {
+	c := qt.New(t)
+	s := newTestServer(c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.queueWriter(ctx)
+		close(stopped)
+	}()
+
+	// The changes are persisted while the queue's mutex is held, with the
+	// fields the element had when they were made.
+	element := &executionQueueElement{identifier: executionIdentifier{GitRef: "a", Source: exec.SourceCron, Workload: "OLTP", UUID: "inserted"}, addedAt: time.Now()}
+	mtx.Lock()
+	persistQueueElement(queueInsert, element)
+	element.identifier.UUID = "updated"
+	persistQueueElement(queueUpdate, element)
+	element.identifier.UUID = "not persisted"
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		uuids := queueUUIDsInDB(c, s)
+		if len(uuids) == 1 && uuids[0] == "updated" {
+			break
+		}
+		if time.Now().After(deadline) {
+			mtx.Unlock()
+			c.Fatalf("the changes of the queue were not persisted, got %v", uuids)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mtx.Unlock()
+
+	// The last changes are persisted once the writer stops.
+	persistQueueElement(queueDelete, element)
+	cancel()
+	<-stopped
+	c.Assert(queueUUIDsInDB(c, s), qt.HasLen, 0)
+}
+
+// queueUUIDsInDB returns the UUIDs of the elements persisted in the execution_queue table.
+func queueUUIDsInDB(c *qt.C, s *Server) []string {
+	rows, err := s.dbClient.Read("SELECT uuid FROM execution_queue")
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		c.Assert(rows.Scan(&uuid), qt.IsNil)
+		uuids = append(uuids, uuid)
+	}
+	c.Assert(rows.Err(), qt.IsNil)
+	return uuids
+}
+
+// queueElementByGitRef returns the element of the queue benchmarking the given git ref. The
+// elements are not looked up by identifier, its profile is a pointer that is not restored as is.
+func queueElementByGitRef(gitRef string) *executionQueueElement {
+	for id, element := range queue {
+		if id.GitRef == gitRef {
+			return element
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"go.uber.org/zap"
+)
+
+func TestServer_cancelQueueElement(t *testing.T) {
+	c := qt.New(t)
+	SetSLogger(zap.NewNop().Sugar())
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	executing := &executionQueueElement{identifier: executionIdentifier{GitRef: "a", UUID: "1"}, Executing: true, cancel: cancel}
+	pending := &executionQueueElement{identifier: executionIdentifier{GitRef: "b", UUID: "2"}}
+	queue = executionQueue{
+		executing.identifier: executing,
+		pending.identifier:   pending,
+	}
+	s := &Server{}
+
+	// Canceling an executing element stops its execution but keeps it in the queue
+	// until the executor is done with it.
+	c.Assert(s.cancelQueueElement("1"), qt.IsTrue)
+	c.Assert(ctx.Err(), qt.Equals, context.Canceled)
+	c.Assert(context.Cause(ctx), qt.Equals, context.Canceled)
+	c.Assert(queue, qt.HasLen, 2)
+
+	// Canceling a pending element removes it from the queue.
+	c.Assert(s.cancelQueueElement("2"), qt.IsTrue)
+	c.Assert(queue, qt.HasLen, 1)
+
+	c.Assert(s.cancelQueueElement("3"), qt.IsFalse)
+}
@@ -0,0 +1,137 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	queueEventAdded    = "added"
+	queueEventStarted  = "started"
+	queueEventFinished = "finished"
+	queueEventFailed   = "failed"
+	queueEventCanceled = "canceled"
+
+	// queueEventKeepAlive is the interval at which a comment is sent to the
+	// subscribers of the events stream to keep their connection open.
+	queueEventKeepAlive = 30 * time.Second
+
+	// queueEventBufferSize is the number of events a subscriber can lag behind,
+	// the events that do not fit in its buffer are dropped.
+	queueEventBufferSize = 64
+)
+
+// queueEvent describes a change of the execution queue or of the status of an execution.
+type queueEvent struct {
+	Type           string    `json:"type"`
+	UUID           string    `json:"uuid"`
+	GitRef         string    `json:"git_ref"`
+	Source         string    `json:"source"`
+	Workload       string    `json:"workload"`
+	PlannerVersion string    `json:"planner_version,omitempty"`
+	PullNb         int       `json:"pull_nb,omitempty"`
+	Time           time.Time `json:"time"`
+
+	// Error is set for failed executions.
+	Error string `json:"error,omitempty"`
+}
+
+// queueEventBroker fans out the queue events to every subscriber.
+type queueEventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan queueEvent]struct{}
+}
+
+var queueEvents = &queueEventBroker{subscribers: map[chan queueEvent]struct{}{}}
+
+func newQueueEvent(eventType string, id executionIdentifier) queueEvent {
+	return queueEvent{
+		Type:           eventType,
+		UUID:           id.UUID,
+		GitRef:         id.GitRef,
+		Source:         id.Source,
+		Workload:       id.Workload,
+		PlannerVersion: id.PlannerVersion,
+		PullNb:         id.PullNb,
+		Time:           time.Now(),
+	}
+}
+
+// subscribe returns a channel receiving the future events, it must be released with unsubscribe.
+func (b *queueEventBroker) subscribe() chan queueEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan queueEvent, queueEventBufferSize)
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (b *queueEventBroker) unsubscribe(ch chan queueEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, ch)
+}
+
+// publish sends the event to every subscriber. It never blocks: a subscriber
+// that is too slow to read its events misses them.
+func (b *queueEventBroker) publish(event queueEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// streamQueueEvents streams the events of the queue using server-sent events, every
+// event is named after its type and contains a JSON encoded queueEvent.
+func (s *Server) streamQueueEvents(c *gin.Context) {
+	events := queueEvents.subscribe()
+	defer queueEvents.unsubscribe(events)
+
+	keepAlive := time.NewTicker(queueEventKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-s.done:
+			return false
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+		case <-keepAlive.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
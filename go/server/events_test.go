@@ -0,0 +1,114 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gin-gonic/gin"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+func TestQueueEventBroker(t *testing.T) {
+	c := qt.New(t)
+	broker := &queueEventBroker{subscribers: map[chan queueEvent]struct{}{}}
+
+	first := broker.subscribe()
+	second := broker.subscribe()
+	broker.publish(queueEvent{Type: queueEventAdded, UUID: "a"})
+	c.Assert((<-first).UUID, qt.Equals, "a")
+	c.Assert((<-second).UUID, qt.Equals, "a")
+
+	// A subscriber that does not read its events does not block the others.
+	for i := 0; i < queueEventBufferSize+10; i++ {
+		broker.publish(queueEvent{Type: queueEventStarted})
+		<-second
+	}
+	c.Assert(first, qt.HasLen, queueEventBufferSize)
+
+	broker.unsubscribe(first)
+	broker.publish(queueEvent{Type: queueEventFinished})
+	c.Assert(first, qt.HasLen, queueEventBufferSize)
+	c.Assert((<-second).Type, qt.Equals, queueEventFinished)
+}
+
+func TestServer_streamQueueEvents(t *testing.T) {
+	c := qt.New(t)
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{}
+	router := gin.New()
+	router.GET("/api/events", s.streamQueueEvents)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/events")
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, qt.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), qt.Equals, "text/event-stream")
+
+	queueEvents.publish(newQueueEvent(queueEventStarted, executionIdentifier{UUID: "1234", GitRef: "abcd", Source: exec.SourceCron, Workload: "oltp"}))
+
+	reader := bufio.NewReader(resp.Body)
+	name, err := reader.ReadString('\n')
+	c.Assert(err, qt.IsNil)
+	c.Assert(name, qt.Equals, "event:started\n")
+	data, err := reader.ReadString('\n')
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.HasPrefix(data, `data:{"type":"started","uuid":"1234","git_ref":"abcd","source":"cron","workload":"oltp","time":`), qt.IsTrue, qt.Commentf(data))
+}
+
+func TestServer_streamQueueEventsShutdown(t *testing.T) {
+	c := qt.New(t)
+	gin.SetMode(gin.TestMode)
+
+	done := make(chan struct{})
+	s := &Server{done: done}
+	router := gin.New()
+	router.GET("/api/events", s.streamQueueEvents)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/events")
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, qt.Equals, http.StatusOK)
+
+	// The stream ends once the server shuts down.
+	close(done)
+	finished := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(resp.Body)
+		finished <- err
+	}()
+	select {
+	case err := <-finished:
+		c.Assert(err, qt.IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("the stream of events did not end after the server shut down")
+	}
+}
@@ -0,0 +1,257 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// healthCheckTimeout bounds the time spent checking one dependency.
+	healthCheckTimeout = 5 * time.Second
+
+	healthStatusOK       = "ok"
+	healthStatusFailing  = "failing"
+	healthStatusDisabled = "disabled"
+)
+
+// HealthCheck is the status of one of the dependencies of the server.
+type HealthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// LastSuccess and LastFailure are only set for the checks of the schedules.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+}
+
+// HealthReport is the status of the server, it is failing if one of its checks is failing.
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// cronRun records the outcome of the runs of the CRON of a schedule.
+type cronRun struct {
+	startedAt, lastSuccess, lastFailure time.Time
+	lastError                           error
+}
+
+// cronRuns holds the last run of the CRON of every schedule, keyed by schedule name.
+type cronRuns struct {
+	mu   sync.Mutex
+	runs map[string]*cronRun
+}
+
+// start records that the CRON of the given schedule started at the given time.
+func (cr *cronRuns) start(name string, now time.Time) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.runs == nil {
+		cr.runs = map[string]*cronRun{}
+	}
+	cr.runs[name] = &cronRun{startedAt: now}
+}
+
+// record records the outcome of a run of the CRON of the given schedule.
+func (cr *cronRuns) record(name string, err error, now time.Time) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.runs == nil {
+		cr.runs = map[string]*cronRun{}
+	}
+	run, ok := cr.runs[name]
+	if !ok {
+		run = &cronRun{startedAt: now}
+		cr.runs[name] = run
+	}
+	if err != nil {
+		run.lastFailure = now
+		run.lastError = err
+		return
+	}
+	run.lastSuccess = now
+	run.lastError = nil
+}
+
+// check reports the health of the CRON of the given schedule. The CRON is failing when
+// its last run failed and it did not succeed during its two last expected runs.
+func (cr *cronRuns) check(sc schedule, now time.Time) HealthCheck {
+	hc := HealthCheck{Name: "schedule:" + sc.Name, Status: healthStatusOK}
+	cr.mu.Lock()
+	run, ok := cr.runs[sc.Name]
+	var r cronRun
+	if ok {
+		r = *run
+	}
+	cr.mu.Unlock()
+	if !ok {
+		hc.Status = healthStatusDisabled
+		return hc
+	}
+	if !r.lastSuccess.IsZero() {
+		hc.LastSuccess = &r.lastSuccess
+	}
+	if !r.lastFailure.IsZero() {
+		hc.LastFailure = &r.lastFailure
+	}
+	if r.lastError == nil {
+		return hc
+	}
+
+	since := r.startedAt
+	if r.lastSuccess.After(since) {
+		since = r.lastSuccess
+	}
+	cronSchedule, err := cron.ParseStandard(sc.Cron)
+	if err != nil || now.After(cronSchedule.Next(cronSchedule.Next(since))) {
+		hc.Status = healthStatusFailing
+		hc.Error = r.lastError.Error()
+	}
+	return hc
+}
+
+// healthz reports whether the server itself is healthy: its local clone of Vitess
+// is usable and the CRONs of its schedules keep succeeding.
+func (s *Server) healthz(c *gin.Context) {
+	checks := []func(context.Context) HealthCheck{s.checkVitessClone}
+	now := time.Now()
+	for _, sc := range s.schedules {
+		if sc.Cron == "" {
+			continue
+		}
+		checks = append(checks, func(context.Context) HealthCheck { return s.cronRuns.check(sc, now) })
+	}
+	s.serveHealthReport(c, checks)
+}
+
+// readyz reports whether the server is ready to serve requests and run benchmarks:
+// the databases and GitHub can be reached, and the local clone of Vitess is usable.
+func (s *Server) readyz(c *gin.Context) {
+	s.serveHealthReport(c, []func(context.Context) HealthCheck{
+		s.checkDatabase,
+		s.checkMetricsSource,
+		s.checkGitHub,
+		s.checkVitessClone,
+	})
+}
+
+// serveHealthReport runs the given checks concurrently and serves their report. The status
+// code is 503 if one of the checks is failing.
+func (s *Server) serveHealthReport(c *gin.Context, checks []func(context.Context) HealthCheck) {
+	report := runHealthChecks(c.Request.Context(), checks)
+	status := http.StatusOK
+	if report.Status != healthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+func runHealthChecks(ctx context.Context, checks []func(context.Context) HealthCheck) HealthReport {
+	report := HealthReport{Status: healthStatusOK, Checks: make([]HealthCheck, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			report.Checks[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+	for _, check := range report.Checks {
+		if check.Status == healthStatusFailing {
+			report.Status = healthStatusFailing
+		}
+	}
+	return report
+}
+
+// newHealthCheck returns a check with the given name, failing if err is not nil.
+func newHealthCheck(name string, err error) HealthCheck {
+	if err != nil {
+		return HealthCheck{Name: name, Status: healthStatusFailing, Error: err.Error()}
+	}
+	return HealthCheck{Name: name, Status: healthStatusOK}
+}
+
+func (s *Server) checkDatabase(ctx context.Context) HealthCheck {
+	if s.dbClient == nil {
+		return newHealthCheck("mysql", errors.New("the database client is not initialized"))
+	}
+	return newHealthCheck(string(s.dbClient.Dialect()), s.dbClient.Ping(ctx))
+}
+
+// checkMetricsSource checks the backend, InfluxDB or Prometheus, from which the macrobenchmarks
+// read their metrics. Its configuration is read from the secrets given to the server.
+func (s *Server) checkMetricsSource(ctx context.Context) HealthCheck {
+	name := s.metricsConfig.Name()
+	if !s.metricsConfig.IsValid() {
+		return HealthCheck{Name: name, Status: healthStatusDisabled}
+	}
+	source, err := s.metricsConfig.NewSource()
+	if err != nil {
+		return newHealthCheck(name, err)
+	}
+	defer source.Close()
+	return newHealthCheck(name, source.Ping(ctx))
+}
+
+func (s *Server) checkGitHub(ctx context.Context) HealthCheck {
+	if s.ghApp == nil {
+		return newHealthCheck("github", errors.New("the GitHub App is not configured"))
+	}
+	return newHealthCheck("github", s.ghApp.CheckAuthentication(ctx))
+}
+
+// checkVitessClone checks that the local clone of Vitess exists and that it was fetched
+// from the remote recently.
+func (s *Server) checkVitessClone(context.Context) HealthCheck {
+	return newHealthCheck("vitess", vitessCloneFreshness(s.getVitessPath(), s.vitessCloneMaxAge, time.Now()))
+}
+
+func vitessCloneFreshness(vitessPath string, maxAge time.Duration, now time.Time) error {
+	if _, err := os.Stat(filepath.Join(vitessPath, ".git")); err != nil {
+		return fmt.Errorf("no clone of Vitess at %s: %w", vitessPath, err)
+	}
+	if maxAge <= 0 {
+		return nil
+	}
+	// FETCH_HEAD is written every time the clone is fetched.
+	info, err := os.Stat(filepath.Join(vitessPath, ".git", "FETCH_HEAD"))
+	if err != nil {
+		return fmt.Errorf("the clone of Vitess was never fetched: %w", err)
+	}
+	if age := now.Sub(info.ModTime()); age > maxAge {
+		return fmt.Errorf("the clone of Vitess was last fetched %s ago", age.Round(time.Second))
+	}
+	return nil
+}
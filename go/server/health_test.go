@@ -0,0 +1,148 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCronRuns_check(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	sc := schedule{Name: "pull-requests", Cron: "*/5 * * * *"}
+	errGitHub := errors.New("GitHub is down")
+
+	tests := []struct {
+		name       string
+		runs       func(cr *cronRuns)
+		now        time.Time
+		wantStatus string
+	}{
+		{name: "Not started", runs: func(cr *cronRuns) {}, now: start, wantStatus: healthStatusDisabled},
+		{name: "Never ran", runs: func(cr *cronRuns) { cr.start(sc.Name, start) }, now: start.Add(time.Hour), wantStatus: healthStatusOK},
+		{name: "Last run succeeded", runs: func(cr *cronRuns) {
+			cr.start(sc.Name, start)
+			cr.record(sc.Name, errGitHub, start.Add(5*time.Minute))
+			cr.record(sc.Name, nil, start.Add(10*time.Minute))
+		}, now: start.Add(time.Hour), wantStatus: healthStatusOK},
+		{name: "One failed run", runs: func(cr *cronRuns) {
+			cr.start(sc.Name, start)
+			cr.record(sc.Name, nil, start.Add(5*time.Minute))
+			cr.record(sc.Name, errGitHub, start.Add(10*time.Minute))
+		}, now: start.Add(11 * time.Minute), wantStatus: healthStatusOK},
+		{name: "Two failed runs", runs: func(cr *cronRuns) {
+			cr.start(sc.Name, start)
+			cr.record(sc.Name, nil, start.Add(5*time.Minute))
+			cr.record(sc.Name, errGitHub, start.Add(10*time.Minute))
+			cr.record(sc.Name, errGitHub, start.Add(15*time.Minute))
+		}, now: start.Add(16 * time.Minute), wantStatus: healthStatusFailing},
+		{name: "Never succeeded", runs: func(cr *cronRuns) {
+			cr.start(sc.Name, start)
+			cr.record(sc.Name, errGitHub, start.Add(5*time.Minute))
+			cr.record(sc.Name, errGitHub, start.Add(10*time.Minute))
+		}, now: start.Add(11 * time.Minute), wantStatus: healthStatusFailing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			var cr cronRuns
+			tt.runs(&cr)
+			got := cr.check(sc, tt.now)
+			c.Assert(got.Name, qt.Equals, "schedule:pull-requests")
+			c.Assert(got.Status, qt.Equals, tt.wantStatus)
+			if tt.wantStatus == healthStatusFailing {
+				c.Assert(got.Error, qt.Equals, errGitHub.Error())
+				c.Assert(got.LastFailure, qt.IsNotNil)
+			}
+		})
+	}
+}
+
+func TestVitessCloneFreshness(t *testing.T) {
+	c := qt.New(t)
+	vitessPath := t.TempDir()
+	now := time.Now()
+
+	c.Assert(vitessCloneFreshness(vitessPath, time.Hour, now), qt.ErrorMatches, "no clone of Vitess at .*")
+
+	c.Assert(os.Mkdir(filepath.Join(vitessPath, ".git"), 0755), qt.IsNil)
+	c.Assert(vitessCloneFreshness(vitessPath, time.Hour, now), qt.ErrorMatches, "the clone of Vitess was never fetched: .*")
+	c.Assert(vitessCloneFreshness(vitessPath, 0, now), qt.IsNil)
+
+	fetchHead := filepath.Join(vitessPath, ".git", "FETCH_HEAD")
+	c.Assert(os.WriteFile(fetchHead, nil, 0644), qt.IsNil)
+	c.Assert(os.Chtimes(fetchHead, now.Add(-2*time.Hour), now.Add(-2*time.Hour)), qt.IsNil)
+	c.Assert(vitessCloneFreshness(vitessPath, 3*time.Hour, now), qt.IsNil)
+	c.Assert(vitessCloneFreshness(vitessPath, time.Hour, now), qt.ErrorMatches, "the clone of Vitess was last fetched 2h0m0s ago")
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	c := qt.New(t)
+	ok := func(context.Context) HealthCheck { return newHealthCheck("ok", nil) }
+	disabled := func(context.Context) HealthCheck { return HealthCheck{Name: "disabled", Status: healthStatusDisabled} }
+	failing := func(context.Context) HealthCheck { return newHealthCheck("failing", errors.New("unreachable")) }
+
+	report := runHealthChecks(context.Background(), []func(context.Context) HealthCheck{ok, disabled})
+	c.Assert(report.Status, qt.Equals, healthStatusOK)
+
+	report = runHealthChecks(context.Background(), []func(context.Context) HealthCheck{ok, failing, disabled})
+	c.Assert(report.Status, qt.Equals, healthStatusFailing)
+	c.Assert(report.Checks, qt.DeepEquals, []HealthCheck{
+		{Name: "ok", Status: healthStatusOK},
+		{Name: "failing", Status: healthStatusFailing, Error: "unreachable"},
+		{Name: "disabled", Status: healthStatusDisabled},
+	})
+}
+
+func TestServer_healthz(t *testing.T) {
+	c := qt.New(t)
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{localVitessPath: t.TempDir(), schedules: []schedule{{Name: "main", Cron: "@midnight"}}}
+	s.cronRuns.start("main", time.Now())
+	router := gin.New()
+	router.GET("/healthz", s.healthz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	c.Assert(w.Code, qt.Equals, http.StatusServiceUnavailable)
+	var report HealthReport
+	c.Assert(json.Unmarshal(w.Body.Bytes(), &report), qt.IsNil)
+	c.Assert(report.Status, qt.Equals, healthStatusFailing)
+	c.Assert(report.Checks, qt.HasLen, 2)
+	c.Assert(report.Checks[0].Name, qt.Equals, "vitess")
+	c.Assert(report.Checks[0].Status, qt.Equals, healthStatusFailing)
+	c.Assert(report.Checks[1], qt.DeepEquals, HealthCheck{Name: "schedule:main", Status: healthStatusOK})
+
+	s.vitessCloneMaxAge = 0
+	c.Assert(os.MkdirAll(filepath.Join(s.getVitessPath(), ".git"), 0755), qt.IsNil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	c.Assert(w.Code, qt.Equals, http.StatusOK)
+}
@@ -0,0 +1,100 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import "time"
+
+type (
+	// benchmarkHost is a machine on which benchmarks are executed. A host is leased
+	// to a single execution at a time. All the fields of a benchmarkHost are protected
+	// by the queue's mutex.
+	benchmarkHost struct {
+		address string
+
+		// busy is set to true while an execution is running on this host,
+		// and startedAt is the time at which that execution started.
+		busy      bool
+		startedAt time.Time
+
+		// lastExecuted is the identifier of the last execution that ran on this host,
+		// and lastSucceeded tells whether that execution finished successfully.
+		// They are used to know if the next execution can skip the preparatory cleanup.
+		lastExecuted  executionIdentifier
+		lastSucceeded bool
+
+		// nextIsSame is set to true if the current execution was told that the next
+		// execution on this host will have the same configuration. Other hosts will not
+		// pick up executions matching lastExecuted while this is true.
+		nextIsSame bool
+	}
+
+	hostPool []*benchmarkHost
+)
+
+func newHostPool(addresses []string) hostPool {
+	pool := make(hostPool, 0, len(addresses))
+	for _, address := range addresses {
+		pool = append(pool, &benchmarkHost{address: address})
+	}
+	return pool
+}
+
+// free returns the first host of the pool that is not busy, or nil
+// if all the hosts are busy.
+func (hp hostPool) free() *benchmarkHost {
+	for _, host := range hp {
+		if !host.busy {
+			return host
+		}
+	}
+	return nil
+}
+
+// reservedByAnotherHost returns true if the given identifier matches the configuration
+// of an execution currently running on another host that expects the next execution
+// to be the same.
+func (hp hostPool) reservedByAnotherHost(host *benchmarkHost, identifier executionIdentifier) bool {
+	for _, other := range hp {
+		if other == host || !other.busy || !other.nextIsSame {
+			continue
+		}
+		if other.lastExecuted.equalWithoutUUID(identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// lease marks the host as busy with the given identifier.
+func (host *benchmarkHost) lease(identifier executionIdentifier, nextIsSame bool) {
+	host.busy = true
+	host.startedAt = time.Now()
+	host.lastExecuted = identifier
+	host.lastSucceeded = false
+	host.nextIsSame = nextIsSame
+}
+
+// release marks the host as free. The outcome of the last execution is saved
+// so that the next execution knows whether it can skip the cleanup phase.
+func (host *benchmarkHost) release(identifier executionIdentifier, succeeded bool) {
+	host.busy = false
+	host.nextIsSame = false
+	host.lastExecuted = identifier
+	host.lastSucceeded = succeeded
+}
@@ -0,0 +1,71 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestHostPool_free(t *testing.T) {
+	c := qt.New(t)
+
+	pool := newHostPool([]string{"10.0.0.1", "10.0.0.2"})
+	c.Assert(pool.free(), qt.Equals, pool[0])
+
+	pool[0].lease(executionIdentifier{GitRef: "abc"}, false)
+	c.Assert(pool.free(), qt.Equals, pool[1])
+
+	pool[1].lease(executionIdentifier{GitRef: "def"}, false)
+	c.Assert(pool.free(), qt.IsNil)
+
+	pool[0].release(executionIdentifier{GitRef: "abc"}, true)
+	c.Assert(pool.free(), qt.Equals, pool[0])
+	c.Assert(pool[0].lastSucceeded, qt.IsTrue)
+}
+
+func TestHostPool_reservedByAnotherHost(t *testing.T) {
+	id := executionIdentifier{GitRef: "abc", Source: "cron", Workload: "oltp", PlannerVersion: "Gen4", UUID: "1"}
+	sameConfig := id
+	sameConfig.UUID = "2"
+	otherConfig := executionIdentifier{GitRef: "def", Source: "cron", Workload: "oltp", PlannerVersion: "Gen4", UUID: "3"}
+
+	tests := []struct {
+		name       string
+		nextIsSame bool
+		identifier executionIdentifier
+		want       bool
+	}{
+		{name: "Same config expected next", nextIsSame: true, identifier: sameConfig, want: true},
+		{name: "Same config not expected next", nextIsSame: false, identifier: sameConfig, want: false},
+		{name: "Different config", nextIsSame: true, identifier: otherConfig, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			pool := newHostPool([]string{"10.0.0.1", "10.0.0.2"})
+			pool[0].lease(id, tt.nextIsSame)
+
+			c.Assert(pool.reservedByAnotherHost(pool[1], tt.identifier), qt.Equals, tt.want)
+			c.Assert(pool.reservedByAnotherHost(pool[0], tt.identifier), qt.IsFalse)
+		})
+	}
+}
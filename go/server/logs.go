@@ -0,0 +1,125 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+const (
+	// logsPollInterval is the interval at which the logs of a running execution are read.
+	logsPollInterval = 500 * time.Millisecond
+
+	logsChunkSize = 32 * 1024
+)
+
+// streamExecutionLogs streams the Ansible output of the execution with the given UUID.
+// The "output" query parameter selects the standard output (default) or the standard
+// error with "stderr". The logs of a running execution are followed until the execution
+// ends, unless the "follow" query parameter is false.
+func (s *Server) streamExecutionLogs(c *gin.Context) {
+	execUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "invalid execution UUID"})
+		return
+	}
+
+	stdout, stderr := exec.OutputFiles(viper.GetString(keyExecRootDir), execUUID)
+	file := stdout
+	switch c.DefaultQuery("output", "stdout") {
+	case "stdout":
+	case "stderr":
+		file = stderr
+	default:
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "output must be stdout or stderr"})
+		return
+	}
+	follow := c.DefaultQuery("follow", "true") != "false"
+
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		c.JSON(http.StatusNotFound, &ErrorAPI{Error: "no logs for execution " + execUUID.String()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+		slog.Error(err)
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	buf := make([]byte, logsChunkSize)
+	ended := false
+	c.Stream(func(w io.Writer) bool {
+		n, err := f.Read(buf)
+		if n > 0 {
+			_, _ = w.Write(buf[:n])
+			return true
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			slog.Error(err)
+			return false
+		}
+
+		// We reached the end of the file, we wait for more logs if the execution is still running.
+		// Once it ended, the file is read one last time to get the logs written before its end.
+		if !follow || ended {
+			return false
+		}
+		if !isExecuting(execUUID.String()) {
+			ended = true
+			return true
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-s.done:
+			return false
+		case <-time.After(logsPollInterval):
+			return true
+		}
+	})
+}
+
+// isExecuting returns true if the element of the queue with the given UUID is executing.
+func isExecuting(UUID string) bool {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	for id, e := range queue {
+		if id.UUID == UUID {
+			return e.Executing
+		}
+	}
+	return false
+}
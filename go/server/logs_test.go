@@ -0,0 +1,118 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"go.uber.org/zap"
+)
+
+func TestServer_streamExecutionLogs(t *testing.T) {
+	c := qt.New(t)
+	SetSLogger(zap.NewNop().Sugar())
+	gin.SetMode(gin.TestMode)
+
+	root := t.TempDir()
+	viper.Set(keyExecRootDir, root)
+	defer viper.Set(keyExecRootDir, nil)
+
+	execUUID := uuid.New()
+	stdout, stderr := exec.OutputFiles(root, execUUID)
+	c.Assert(os.MkdirAll(filepath.Dir(stdout), 0755), qt.IsNil)
+	c.Assert(os.WriteFile(stdout, []byte("PLAY [all]\n"), 0644), qt.IsNil)
+	c.Assert(os.WriteFile(stderr, []byte("warning\n"), 0644), qt.IsNil)
+
+	s := &Server{}
+	router := gin.New()
+	router.GET("/api/executions/:uuid/logs", s.streamExecutionLogs)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	get := func(url string) (int, string) {
+		resp, err := http.Get(srv.URL + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		wantCode int
+		wantBody string
+	}{
+		{name: "Standard output", url: "/api/executions/" + execUUID.String() + "/logs", wantCode: http.StatusOK, wantBody: "PLAY [all]\n"},
+		{name: "Standard error", url: "/api/executions/" + execUUID.String() + "/logs?output=stderr", wantCode: http.StatusOK, wantBody: "warning\n"},
+		{name: "Unknown output", url: "/api/executions/" + execUUID.String() + "/logs?output=both", wantCode: http.StatusBadRequest},
+		{name: "Invalid UUID", url: "/api/executions/not-a-uuid/logs", wantCode: http.StatusBadRequest},
+		{name: "Unknown execution", url: "/api/executions/" + uuid.NewString() + "/logs", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			code, body := get(tt.url)
+			c.Assert(code, qt.Equals, tt.wantCode)
+			if tt.wantBody != "" {
+				c.Assert(body, qt.Equals, tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("Follow a running execution", func(t *testing.T) {
+		c := qt.New(t)
+		mtx.Lock()
+		queue = executionQueue{}
+		element := &executionQueueElement{Executing: true, identifier: executionIdentifier{UUID: execUUID.String()}}
+		queue[element.identifier] = element
+		mtx.Unlock()
+
+		go func() {
+			time.Sleep(2 * logsPollInterval)
+			f, err := os.OpenFile(stdout, os.O_APPEND|os.O_WRONLY, 0644)
+			if err == nil {
+				_, _ = io.WriteString(f, "TASK [macrobench : Run]\n")
+				_ = f.Close()
+			}
+			mtx.Lock()
+			delete(queue, element.identifier)
+			mtx.Unlock()
+		}()
+
+		code, body := get("/api/executions/" + execUUID.String() + "/logs")
+		c.Assert(code, qt.Equals, http.StatusOK)
+		c.Assert(body, qt.Equals, "PLAY [all]\nTASK [macrobench : Run]\n")
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vitessio/arewefastyet/go/storage/psdb"
+)
+
+const metricsNamespace = "arewefastyet"
+
+var (
+	executionsStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "executions_started_total",
+		Help:      "Number of executions sent to a benchmark host.",
+	}, []string{"workload"})
+
+	executionsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "executions_finished_total",
+		Help:      "Number of executions that finished successfully.",
+	}, []string{"workload"})
+
+	executionsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "executions_failed_total",
+		Help:      "Number of executions that failed, by category of failure.",
+	}, []string{"workload", "category"})
+
+	executionRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "execution_retries_total",
+		Help:      "Number of failed executions that were put back in the queue to be retried.",
+	}, []string{"workload", "category"})
+
+	// The buckets go from one minute to about eight hours, the longest timeout of a workload.
+	executionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "execution_duration_seconds",
+		Help:      "Duration of the executions, whether they succeeded or not.",
+		Buckets:   prometheus.ExponentialBuckets(60, 2, 10),
+	}, []string{"workload"})
+
+	cronHandlerErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "cron_handler_errors_total",
+		Help:      "Number of errors that stopped the handler of a schedule.",
+	}, []string{"schedule"})
+
+	githubAPIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "github_api_errors_total",
+		Help:      "Number of failed calls to the GitHub API.",
+	}, []string{"call"})
+
+	queueLengthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "queue_length"),
+		"Number of elements in the execution queue, by source.",
+		[]string{"source"}, nil,
+	)
+)
+
+// queueCollector reports the length of the execution queue every time the metrics are scraped.
+type queueCollector struct{}
+
+func (queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueLengthDesc
+}
+
+func (queueCollector) Collect(ch chan<- prometheus.Metric) {
+	mtx.RLock()
+	lengths := map[string]int{}
+	for id := range queue {
+		lengths[id.Source]++
+	}
+	mtx.RUnlock()
+
+	for source, length := range lengths {
+		ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(length), source)
+	}
+}
+
+// newMetricsRegistry returns a registry with the metrics of the server, of the database
+// client, and of the Go runtime.
+func newMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		queueCollector{},
+		executionsStarted,
+		executionsFinished,
+		executionsFailed,
+		executionRetries,
+		executionDuration,
+		cronHandlerErrors,
+		githubAPIErrors,
+		psdb.QueryDuration,
+	)
+	return registry
+}
+
+// metricsHandler serves the metrics of the given registry in the Prometheus format.
+func metricsHandler(registry *prometheus.Registry) gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+}
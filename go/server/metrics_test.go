@@ -0,0 +1,69 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+func TestQueueCollector(t *testing.T) {
+	c := qt.New(t)
+	queue = executionQueue{
+		{UUID: "1", Source: exec.SourceCron}:        {},
+		{UUID: "2", Source: exec.SourceCron}:        {Executing: true},
+		{UUID: "3", Source: exec.SourcePullRequest}: {},
+	}
+	defer func() { queue = make(executionQueue) }()
+
+	expected := `
+# HELP arewefastyet_queue_length Number of elements in the execution queue, by source.
+# TYPE arewefastyet_queue_length gauge
+arewefastyet_queue_length{source="cron"} 2
+arewefastyet_queue_length{source="cron_pr"} 1
+`
+	c.Assert(testutil.CollectAndCompare(queueCollector{}, strings.NewReader(expected)), qt.IsNil)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	c := qt.New(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/metrics", metricsHandler(newMetricsRegistry()))
+	executionsStarted.WithLabelValues("oltp").Inc()
+	cronHandlerErrors.WithLabelValues("main").Inc()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	c.Assert(w.Code, qt.Equals, http.StatusOK)
+	body, err := io.ReadAll(w.Body)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(body), qt.Contains, `arewefastyet_executions_started_total{workload="oltp"}`)
+	c.Assert(string(body), qt.Contains, `arewefastyet_cron_handler_errors_total{schedule="main"}`)
+	c.Assert(string(body), qt.Contains, "go_goroutines")
+}
@@ -0,0 +1,170 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+	"github.com/vitessio/arewefastyet/go/tools/microbench"
+)
+
+// pullRequestCommentMtx prevents two executions of the same pull request from
+// creating two different comments when they finish at the same time.
+var pullRequestCommentMtx sync.Mutex
+
+// pullRequestSummary is the comparison of the head of a pull request with its base
+// that is posted as a comment on the pull request.
+type pullRequestSummary struct {
+	pullNb         int
+	base, head     string
+	plannerVersion macrobench.PlannerVersion
+	websiteURL     string
+
+	macros map[string]macrobench.StatisticalCompareResults
+	micros microbench.ComparisonArray
+
+	// regressionThreshold is the percentage above which a decrease of a microbenchmark is a regression.
+	regressionThreshold float64
+}
+
+// commentPullRequest compares the head of the pull request benchmarked by the given element
+// with its base, and posts the summary on the pull request. The comment is edited instead
+// every time another benchmark of the pull request finishes.
+func (s *Server) commentPullRequest(element *executionQueueElement) error {
+	if !s.prComment || element.identifier.Source != exec.SourcePullRequest || element.identifier.PullNb == 0 {
+		return nil
+	}
+	pullNb := element.identifier.PullNb
+
+	prInfo, err := s.ghApp.GetPullRequestInfo(pullNb)
+	if err != nil {
+		githubAPIErrors.WithLabelValues("get_pull_request").Inc()
+		return err
+	}
+	refs, err := exec.GetPullRequestInfo(s.dbClient, pullNb, prInfo)
+	if err != nil {
+		return err
+	}
+	if refs.Base == "" {
+		slog.Infof("no benchmark of the base of pull request %d, not commenting", pullNb)
+		return nil
+	}
+
+	summary := pullRequestSummary{
+		pullNb:         pullNb,
+		base:           refs.Base,
+		head:           element.identifier.GitRef,
+		plannerVersion: macrobench.PlannerVersion(element.identifier.PlannerVersion),
+		websiteURL:     s.websiteURL,
+
+		regressionThreshold: s.regressionThresholdMicro,
+	}
+	if summary.plannerVersion == "" {
+		summary.plannerVersion = macrobench.Gen4Planner
+	}
+	summary.macros, err = macrobench.Compare(s.dbClient, summary.base, summary.head, s.workloads, summary.plannerVersion)
+	if err != nil {
+		return err
+	}
+	summary.micros, err = microbench.Compare(s.dbClient, summary.head, summary.base)
+	if err != nil {
+		return err
+	}
+
+	pullRequestCommentMtx.Lock()
+	defer pullRequestCommentMtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err = s.ghApp.UpsertPullRequestComment(ctx, pullNb, summary.marker(), summary.markdown())
+	if err != nil {
+		githubAPIErrors.WithLabelValues("upsert_comment").Inc()
+	}
+	return err
+}
+
+// marker identifies the comment of the summary, there is one comment per planner version.
+func (prs pullRequestSummary) marker() string {
+	return fmt.Sprintf("<!-- arewefastyet-summary-%s -->", prs.plannerVersion)
+}
+
+func (prs pullRequestSummary) markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "### Benchmark results\n\n")
+	fmt.Fprintf(&b, "Comparing the head of this pull request (`%s`) with its base (`%s`) using the %s planner.\n\n", git.ShortenSHA(prs.head), git.ShortenSHA(prs.base), prs.plannerVersion)
+
+	workloads := make([]string, 0, len(prs.macros))
+	for workload := range prs.macros {
+		workloads = append(workloads, workload)
+	}
+	sort.Strings(workloads)
+
+	if len(workloads) > 0 {
+		b.WriteString("| Workload | QPS | TPS | Latency | CPU time | Memory |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, workload := range workloads {
+			res := prs.macros[workload]
+			if res.MissingResults {
+				fmt.Fprintf(&b, "| %s | pending | pending | pending | pending | pending |\n", workload)
+				continue
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", workload,
+				formatStatisticalResult(res.TotalQPS),
+				formatStatisticalResult(res.TPS),
+				formatStatisticalResult(res.Latency),
+				formatStatisticalResult(res.TotalComponentsCPUTime),
+				formatStatisticalResult(res.TotalComponentsMemStatsAllocBytes),
+			)
+		}
+		b.WriteString("\nDeltas are computed from the base to the head, `~` means the difference is not statistically significant.\n")
+	}
+
+	if len(prs.micros) > 0 {
+		fmt.Fprintf(&b, "\n#### Microbenchmarks\n\n%d microbenchmarks compared.", len(prs.micros))
+		if regression := prs.micros.RegressionWithThreshold(prs.regressionThreshold); regression != "" {
+			fmt.Fprintf(&b, " The following ones regressed by more than %g%%:\n\n%s", prs.regressionThreshold, regression)
+		} else {
+			fmt.Fprintf(&b, " None of them regressed by more than %g%%.\n", prs.regressionThreshold)
+		}
+	}
+
+	if prs.websiteURL != "" {
+		base := strings.TrimSuffix(prs.websiteURL, "/")
+		compareURL := fmt.Sprintf("%s/compare?old=%s&new=%s", base, url.QueryEscape(prs.base), url.QueryEscape(prs.head))
+		fmt.Fprintf(&b, "\n[Full comparison](%s) · [All the benchmarks of this pull request](%s/pr/%d)\n", compareURL, base, prs.pullNb)
+	}
+	return b.String()
+}
+
+func formatStatisticalResult(res macrobench.StatisticalResult) string {
+	if res.Insignificant {
+		return fmt.Sprintf("~ (p=%.3f)", res.P)
+	}
+	return fmt.Sprintf("%+.2f%% (p=%.3f)", res.Delta, res.P)
+}
@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+	"github.com/vitessio/arewefastyet/go/tools/microbench"
+)
+
+func TestPullRequestSummary_markdown(t *testing.T) {
+	c := qt.New(t)
+
+	summary := pullRequestSummary{
+		pullNb:         42,
+		base:           "0123456789abcdef",
+		head:           "fedcba9876543210",
+		plannerVersion: macrobench.Gen4Planner,
+		websiteURL:     "https://benchmark.vitess.io/",
+		macros: map[string]macrobench.StatisticalCompareResults{
+			"TPCC": {MissingResults: true},
+			"OLTP": {
+				TotalQPS:                          macrobench.StatisticalResult{Delta: 3.5, P: 0.002},
+				TPS:                               macrobench.StatisticalResult{Delta: 3.1, P: 0.004},
+				Latency:                           macrobench.StatisticalResult{Delta: -0.4, P: 0.42, Insignificant: true},
+				TotalComponentsCPUTime:            macrobench.StatisticalResult{Delta: -2, P: 0.01},
+				TotalComponentsMemStatsAllocBytes: macrobench.StatisticalResult{Delta: 0.1, P: 0.9, Insignificant: true},
+			},
+		},
+		micros: microbench.ComparisonArray{
+			{BenchmarkId: microbench.BenchmarkId{PkgName: "vtgate", SubBenchmarkName: "BenchmarkPlan"}, Diff: microbench.Result{Ops: -20}},
+			{BenchmarkId: microbench.BenchmarkId{PkgName: "vtgate", SubBenchmarkName: "BenchmarkParse"}, Diff: microbench.Result{Ops: -12}},
+		},
+		regressionThreshold: 15,
+	}
+
+	c.Assert(summary.marker(), qt.Equals, "<!-- arewefastyet-summary-Gen4 -->")
+	// Backquotes cannot be used in raw strings, they are replaced by quotes in the expected summary.
+	c.Assert(strings.ReplaceAll(summary.markdown(), "`", "'"), qt.Equals, `### Benchmark results
+
+Comparing the head of this pull request ('fedcba9') with its base ('0123456') using the Gen4 planner.
+
+| Workload | QPS | TPS | Latency | CPU time | Memory |
+|---|---|---|---|---|---|
+| OLTP | +3.50% (p=0.002) | +3.10% (p=0.004) | ~ (p=0.420) | -2.00% (p=0.010) | ~ (p=0.900) |
+| TPCC | pending | pending | pending | pending | pending |
+
+Deltas are computed from the base to the head, '~' means the difference is not statistically significant.
+
+#### Microbenchmarks
+
+2 microbenchmarks compared. The following ones regressed by more than 15%:
+
+- vtgate/BenchmarkPlan: metric: total operation, decreased by 20.00%
+
+[Full comparison](https://benchmark.vitess.io/compare?old=0123456789abcdef&new=fedcba9876543210) · [All the benchmarks of this pull request](https://benchmark.vitess.io/pr/42)
+`)
+}
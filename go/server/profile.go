@@ -0,0 +1,137 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/pprof/profile"
+	"github.com/google/uuid"
+	"github.com/vitessio/arewefastyet/go/storage/artifact"
+	"github.com/vitessio/arewefastyet/go/tools/pprof"
+)
+
+// defaultProfileCompareTop is the number of functions returned by the profile comparison by default.
+const defaultProfileCompareTop = 20
+
+var errNoProfile = errors.New("no profile found")
+
+// ProfileComparison is the function level difference between the profiles of two executions.
+type ProfileComparison struct {
+	*pprof.Diff
+	Folded []string `json:"folded,omitempty"`
+}
+
+// compareProfiles compares the profiles collected by the "old" and "new" executions.
+// The profiles of an execution are merged together, the "name" query parameter only
+// keeps the profiles whose artifact name contains it, for instance "vtgate" or "cpu".
+// The "sample" query parameter selects the sample type, "top" the number of functions
+// to return, and "folded" adds the folded stacks of a differential flame graph.
+func (s *Server) compareProfiles(c *gin.Context) {
+	if s.artifacts == nil {
+		c.JSON(http.StatusNotFound, &ErrorAPI{Error: "the artifact store is not enabled"})
+		return
+	}
+	oldUUID, err := uuid.Parse(c.Query("old"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "invalid old execution UUID"})
+		return
+	}
+	newUUID, err := uuid.Parse(c.Query("new"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "invalid new execution UUID"})
+		return
+	}
+	top, err := strconv.Atoi(c.DefaultQuery("top", strconv.Itoa(defaultProfileCompareTop)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: "top must be an integer"})
+		return
+	}
+	name := c.Query("name")
+	sampleType := c.Query("sample")
+
+	profiles := make([]*profile.Profile, 0, 2)
+	for _, execUUID := range []uuid.UUID{oldUUID, newUUID} {
+		artifacts, err := artifact.GetForExecution(s.db(c), execUUID.String())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &ErrorAPI{Error: err.Error()})
+			slog.Error(err)
+			return
+		}
+		p, err := s.loadExecutionProfile(artifacts, name)
+		if errors.Is(err, errNoProfile) {
+			c.JSON(http.StatusNotFound, &ErrorAPI{Error: fmt.Sprintf("no profile matching %q for execution %s", name, execUUID.String())})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &ErrorAPI{Error: fmt.Sprintf("execution %s: %v", execUUID.String(), err)})
+			return
+		}
+		profiles = append(profiles, p)
+	}
+
+	diff, err := pprof.Compare(profiles[0], profiles[1], sampleType, top)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &ErrorAPI{Error: err.Error()})
+		return
+	}
+	comparison := ProfileComparison{Diff: diff}
+	if c.DefaultQuery("folded", "false") == "true" {
+		comparison.Folded, err = pprof.FoldedStacks(profiles[0], profiles[1], sampleType)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &ErrorAPI{Error: err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// loadExecutionProfile parses the profile artifacts whose name contains the given name and
+// merges them into a single profile. It returns errNoProfile if no artifact matches.
+func (s *Server) loadExecutionProfile(artifacts []artifact.Artifact, name string) (*profile.Profile, error) {
+	var profiles []*profile.Profile
+	for _, a := range artifacts {
+		if a.Kind != artifact.KindProfile || !strings.Contains(a.Name, name) {
+			continue
+		}
+		f, err := s.artifacts.Open(a.Digest)
+		if err != nil {
+			return nil, err
+		}
+		p, err := profile.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the profile %s: %w", a.Name, err)
+		}
+		profiles = append(profiles, p)
+	}
+	if len(profiles) == 0 {
+		return nil, errNoProfile
+	}
+	p, err := profile.Merge(profiles)
+	if err != nil {
+		return nil, fmt.Errorf("could not merge the profiles, use the name parameter to select compatible profiles: %w", err)
+	}
+	return p, nil
+}
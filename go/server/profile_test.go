@@ -0,0 +1,83 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/pprof/profile"
+	"github.com/vitessio/arewefastyet/go/storage/artifact"
+)
+
+func putProfile(c *qt.C, store *artifact.Store, name, sampleType string, value int64) artifact.Artifact {
+	fn := &profile.Function{ID: 1, Name: "main"}
+	loc := &profile.Location{ID: 1, Line: []profile.Line{{Function: fn}}}
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: sampleType, Unit: "count"}},
+		Sample:     []*profile.Sample{{Location: []*profile.Location{loc}, Value: []int64{value}}},
+		Location:   []*profile.Location{loc},
+		Function:   []*profile.Function{fn},
+	}
+	var buf bytes.Buffer
+	c.Assert(p.Write(&buf), qt.IsNil)
+	digest, size, err := store.Put(&buf)
+	c.Assert(err, qt.IsNil)
+	return artifact.Artifact{Name: name, Kind: artifact.KindProfile, Digest: digest, Size: size}
+}
+
+func TestServer_loadExecutionProfile(t *testing.T) {
+	c := qt.New(t)
+	s := &Server{artifacts: artifact.NewStore(t.TempDir())}
+	artifacts := []artifact.Artifact{
+		putProfile(c, s.artifacts, "profiles/host1/vtgate-1/cpu.pprof", "samples", 10),
+		putProfile(c, s.artifacts, "profiles/host1/vtgate-2/cpu.pprof", "samples", 5),
+		putProfile(c, s.artifacts, "profiles/host1/vttablet-1/mem.pprof", "objects", 7),
+		{Name: "exec-stdout.log", Kind: artifact.KindLog},
+	}
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    int64
+		wantErr string
+	}{
+		{name: "Profiles are merged", filter: "vtgate", want: 15},
+		{name: "Single profile", filter: "vttablet", want: 7},
+		{name: "No matching profile", filter: "vtorc", wantErr: errNoProfile.Error()},
+		{name: "Incompatible profiles", filter: "", wantErr: "could not merge the profiles, .*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			p, err := s.loadExecutionProfile(artifacts, tt.filter)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			var total int64
+			for _, sample := range p.Sample {
+				total += sample.Value[0]
+			}
+			c.Assert(total, qt.Equals, tt.want)
+		})
+	}
+}
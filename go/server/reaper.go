@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+// recoverOrphanedExecutions marks as failed the executions that were left in the started
+// status by a server that stopped during their run. It is called when the server starts,
+// before any execution is run, so none of the started executions can still be running.
+func (s *Server) recoverOrphanedExecutions() error {
+	uuids, err := exec.GetStartedExecutions(s.dbClient)
+	if err != nil {
+		return err
+	}
+	for _, uuid := range uuids {
+		err := exec.MarkAsFailed(s.dbClient, uuid, exec.FailureInfrastructure, "execution orphaned: the server stopped while it was running")
+		if err != nil {
+			return err
+		}
+		slog.Infof("execution %s was orphaned, it is marked as failed", uuid)
+	}
+	return nil
+}
+
+// reapStuckExecutions marks as failed the started executions that did not send a heartbeat
+// for longer than the heartbeat timeout, the process running them is gone or stuck. If the
+// execution belongs to an element of the queue, it is stopped with an infrastructure failure:
+// executeElement then releases its host and retries it if its retry policy allows it.
+func (s *Server) reapStuckExecutions() {
+	uuids, err := exec.GetExecutionsWithoutHeartbeat(s.dbClient, s.heartbeatTimeout)
+	if err != nil {
+		slog.Error(err)
+		return
+	}
+	for _, uuid := range uuids {
+		reason := fmt.Sprintf("execution stuck: no heartbeat received for more than %s", s.heartbeatTimeout)
+		if err := exec.MarkAsFailed(s.dbClient, uuid, exec.FailureInfrastructure, reason); err != nil {
+			slog.Error(err)
+			continue
+		}
+		slog.Warnf("execution %s is stuck, it is marked as failed", uuid)
+		stopExecution(uuid, &exec.Failure{Category: exec.FailureInfrastructure, Reason: reason, Err: errors.New(reason)})
+	}
+}
+
+// stopExecution cancels the execution of the element of the queue with the given UUID,
+// with the given failure as cause. It does nothing if no element is executing with that UUID.
+func stopExecution(UUID string, failure *exec.Failure) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	for id, e := range queue {
+		if id.UUID == UUID && e.Executing && e.cancel != nil {
+			e.cancel(failure)
+		}
+	}
+}
+
+// executionReaper reaps the stuck executions periodically until the given context is done.
+func (s *Server) executionReaper(ctx context.Context) {
+	ticker := time.NewTicker(exec.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reapStuckExecutions()
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+// insertExecution inserts an execution with the given status and heartbeat in the database of the server.
+func insertExecution(c *qt.C, s *Server, uuid, status string, heartbeatAt time.Time) {
+	_, err := s.dbClient.Write("INSERT INTO execution(uuid, status, source, git_ref, workload, heartbeat_at) VALUES(?, ?, ?, ?, ?, ?)",
+		uuid, status, exec.SourceCron, "abcd", "OLTP", heartbeatAt.UTC())
+	c.Assert(err, qt.IsNil)
+}
+
+// executionStatus returns the status and the failure category of the given execution.
+func executionStatus(c *qt.C, s *Server, uuid string) (string, string) {
+	rows, err := s.dbClient.Read("SELECT status, IFNULL(failure_category, '') FROM execution WHERE uuid = ?", uuid)
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	c.Assert(rows.Next(), qt.IsTrue)
+	var status, category string
+	c.Assert(rows.Scan(&status, &category), qt.IsNil)
+	return status, category
+}
+
+func TestServer_recoverOrphanedExecutions(t *testing.T) {
+	c := qt.New(t)
+	s := newTestServer(c)
+
+	now := time.Now()
+	insertExecution(c, s, "orphaned", exec.StatusStarted, now.Add(-time.Hour))
+	insertExecution(c, s, "recent", exec.StatusStarted, now)
+	insertExecution(c, s, "finished", exec.StatusFinished, now.Add(-time.Hour))
+
+	c.Assert(s.recoverOrphanedExecutions(), qt.IsNil)
+
+	// No execution is running when the server starts, even the recent heartbeats are orphaned.
+	for _, uuid := range []string{"orphaned", "recent"} {
+		status, category := executionStatus(c, s, uuid)
+		c.Assert(status, qt.Equals, exec.StatusFailed)
+		c.Assert(category, qt.Equals, string(exec.FailureInfrastructure))
+	}
+	status, _ := executionStatus(c, s, "finished")
+	status, _ = executionStatus(c, s, "finished")
+	c.Assert(status, qt.Equals, exec.StatusFinished)
+}
+
+func TestServer_reapStuckExecutions(t *testing.T) {
+	c := qt.New(t)
+	s := newTestServer(c)
+	s.heartbeatTimeout = 5 * time.Minute
+
+	now := time.Now()
+	insertExecution(c, s, "stuck", exec.StatusStarted, now.Add(-time.Hour))
+	insertExecution(c, s, "running", exec.StatusStarted, now)
+
+	stuckCtx, stuckCancel := context.WithCancelCause(context.Background())
+	defer stuckCancel(nil)
+	runningCtx, runningCancel := context.WithCancelCause(context.Background())
+	defer runningCancel(nil)
+	stuck := &executionQueueElement{identifier: executionIdentifier{GitRef: "a", UUID: "stuck"}, Executing: true, cancel: stuckCancel}
+	running := &executionQueueElement{identifier: executionIdentifier{GitRef: "b", UUID: "running"}, Executing: true, cancel: runningCancel}
+	queue = executionQueue{
+		stuck.identifier:   stuck,
+		running.identifier: running,
+	}
+
+	s.reapStuckExecutions()
+
+	status, category := executionStatus(c, s, "stuck")
+	c.Assert(status, qt.Equals, exec.StatusFailed)
+	c.Assert(category, qt.Equals, string(exec.FailureInfrastructure))
+	status, _ = executionStatus(c, s, "running")
+	c.Assert(status, qt.Equals, exec.StatusStarted)
+
+	// The stuck execution is stopped with an infrastructure failure so that it is retried,
+	// executeElement then releases its host and retries it.
+	c.Assert(stuckCtx.Err(), qt.Equals, context.Canceled)
+	var failure *exec.Failure
+	c.Assert(errors.As(context.Cause(stuckCtx), &failure), qt.IsTrue)
+	c.Assert(failure.Category, qt.Equals, exec.FailureInfrastructure)
+	c.Assert(runningCtx.Err(), qt.IsNil)
+	c.Assert(queue, qt.HasLen, 2)
+}
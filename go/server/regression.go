@@ -0,0 +1,142 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/slack"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+	"github.com/vitessio/arewefastyet/go/tools/microbench"
+)
+
+// isRegressionAlertSource returns true if regressions of the given source must be
+// announced on Slack: only the nightly benchmarks of main and of the release branches are.
+func isRegressionAlertSource(source string) bool {
+	return source == exec.SourceCron || strings.HasPrefix(source, exec.SourceReleaseBranch) && strings.HasSuffix(source, "-branch")
+}
+
+// handleRegressions compares the given element with every element it must be compared with.
+// Each significant regression is announced on Slack and, if it is a regression between two
+// nightly benchmarks of main, it is bisected. Since an element is executed several times,
+// this is only done once the last execution of the element is finished. Baseline elements
+// are skipped, they are older than the elements they are compared with.
+func (s *Server) handleRegressions(element *executionQueueElement) error {
+	if !s.slackConfig.IsValid() && !s.bisectRegressions {
+		return nil
+	}
+	if element.baseline || !isRegressionAlertSource(element.identifier.Source) || hasPendingDuplicate(element) {
+		return nil
+	}
+
+	for _, comparer := range element.compareWith {
+		reason, err := s.regressionReason(comparer, element.identifier)
+		if err != nil {
+			return err
+		}
+		if reason == "" {
+			continue
+		}
+
+		isNew, err := s.recordRegressionAlert(comparer, element.identifier)
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			slog.Infof("regression of %+v against %s was already announced", element.identifier, comparer.GitRef)
+			continue
+		}
+
+		s.startBisectionOfRegression(comparer, element.identifier)
+		if !s.slackConfig.IsValid() {
+			continue
+		}
+		msg := slack.TextMessage{Content: regressionAlertMessage(comparer, element.identifier, reason, s.websiteURL)}
+		if err := msg.Send(s.slackConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasPendingDuplicate returns true if another execution with the same configuration
+// as the given element is still in the queue.
+func hasPendingDuplicate(element *executionQueueElement) bool {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	for id := range queue {
+		if id != element.identifier && id.equalWithoutUUID(element.identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// regressionReason returns the reason of the regression of new compared to old,
+// or an empty string if new did not regress.
+func (s *Server) regressionReason(old, new executionIdentifier) (string, error) {
+	if new.Workload == "micro" {
+		micros, err := microbench.Compare(s.dbClient, new.GitRef, old.GitRef)
+		if err != nil {
+			return "", err
+		}
+		return micros.RegressionWithThreshold(s.regressionThresholdMicro), nil
+	}
+
+	macros, err := macrobench.Compare(s.dbClient, old.GitRef, new.GitRef, []string{new.Workload}, macrobench.PlannerVersion(new.PlannerVersion))
+	if err != nil {
+		return "", err
+	}
+	return macros[new.Workload].Regression(s.regressionThreshold), nil
+}
+
+// recordRegressionAlert saves the regression alert in the database. It returns false if
+// the same regression was already recorded, in which case it must not be announced again.
+func (s *Server) recordRegressionAlert(old, new executionIdentifier) (bool, error) {
+	id, err := s.dbClient.Write(storage.DialectOf(s.dbClient).InsertIgnore()+" INTO regression_alert(source, workload, planner_version, git_ref, compared_git_ref) VALUES(?, ?, ?, ?, ?)",
+		new.Source, new.Workload, new.PlannerVersion, new.GitRef, old.GitRef)
+	if err != nil {
+		return false, err
+	}
+	return id != 0, nil
+}
+
+func regressionAlertMessage(old, new executionIdentifier, reason, websiteURL string) string {
+	comparison := "the previous benchmark of `" + new.Source + "`"
+	if old.Source != new.Source {
+		comparison = "`" + old.Source + "`"
+	}
+	workload := new.Workload
+	if new.PlannerVersion != "" {
+		workload += " (" + new.PlannerVersion + " planner)"
+	}
+
+	msg := fmt.Sprintf(":rotating_light: *Regression detected on `%s`* for %s\nComparing `%s` with %s (`%s`):\n%s",
+		new.Source, workload, git.ShortenSHA(new.GitRef), comparison, git.ShortenSHA(old.GitRef), reason)
+	if websiteURL != "" {
+		msg += fmt.Sprintf("<%s/compare?old=%s&new=%s|See the full comparison>", strings.TrimSuffix(websiteURL, "/"), url.QueryEscape(old.GitRef), url.QueryEscape(new.GitRef))
+	}
+	return msg
+}
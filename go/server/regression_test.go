@@ -0,0 +1,124 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+)
+
+func TestIsRegressionAlertSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{source: exec.SourceCron, want: true},
+		{source: exec.SourceReleaseBranch + "release-18.0-branch", want: true},
+		{source: exec.SourceTag + "v18.0.0"},
+		{source: exec.SourcePullRequest},
+		{source: "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(isRegressionAlertSource(tt.source), qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestHasPendingDuplicate(t *testing.T) {
+	c := qt.New(t)
+
+	element := &executionQueueElement{identifier: executionIdentifier{GitRef: "a", Source: exec.SourceCron, Workload: "oltp", UUID: "1"}}
+	duplicate := &executionQueueElement{identifier: executionIdentifier{GitRef: "a", Source: exec.SourceCron, Workload: "oltp", UUID: "2"}}
+	other := &executionQueueElement{identifier: executionIdentifier{GitRef: "b", Source: exec.SourceCron, Workload: "oltp", UUID: "3"}}
+
+	queue = executionQueue{other.identifier: other}
+	c.Assert(hasPendingDuplicate(element), qt.IsFalse)
+
+	queue[duplicate.identifier] = duplicate
+	c.Assert(hasPendingDuplicate(element), qt.IsTrue)
+}
+
+func TestRegressionAlertMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		old  executionIdentifier
+		want string
+	}{
+		{
+			name: "Previous benchmark",
+			old:  executionIdentifier{GitRef: "0123456789", Source: exec.SourceCron},
+			want: ":rotating_light: *Regression detected on `cron`* for oltp (Gen4 planner)\nComparing `fedcba9` with the previous benchmark of `cron` (`0123456`):\n- total QPS decreased by 7.50% (p=0.001)\n<https://benchmark.vitess.io/compare?old=0123456789&new=fedcba9876|See the full comparison>",
+		},
+		{
+			name: "Release",
+			old:  executionIdentifier{GitRef: "0123456789", Source: exec.SourceTag + "v18.0.0"},
+			want: ":rotating_light: *Regression detected on `cron`* for oltp (Gen4 planner)\nComparing `fedcba9` with `cron_tags_v18.0.0` (`0123456`):\n- total QPS decreased by 7.50% (p=0.001)\n<https://benchmark.vitess.io/compare?old=0123456789&new=fedcba9876|See the full comparison>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			newID := executionIdentifier{GitRef: "fedcba9876", Source: exec.SourceCron, Workload: "oltp", PlannerVersion: "Gen4"}
+			got := regressionAlertMessage(tt.old, newID, "- total QPS decreased by 7.50% (p=0.001)\n", "https://benchmark.vitess.io")
+			c.Assert(got, qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestServer_createBranchElementWithComparisonOnPreviousAndRelease(t *testing.T) {
+	c := qt.New(t)
+
+	s := &Server{}
+	config := benchmarkConfig{v: viper.New()}
+	lastRelease := &git.Release{Name: "v20.0.0", CommitHash: "release", Version: git.Version{Major: 20}}
+	elements := s.createBranchElementWithComparisonOnPreviousAndRelease(config, "new", "OLTP", "previous", "Gen4", exec.SourceCron, lastRelease, git.Version{Major: 21})
+	c.Assert(elements, qt.HasLen, 3)
+
+	// Only the new element is compared with older refs, the others are its baselines.
+	newElement, previousElement, releaseElement := elements[0], elements[1], elements[2]
+	c.Assert(newElement.baseline, qt.IsFalse)
+	c.Assert(newElement.compareWith, qt.DeepEquals, []executionIdentifier{previousElement.identifier, releaseElement.identifier})
+	c.Assert(previousElement.baseline, qt.IsTrue)
+	c.Assert(previousElement.identifier.GitRef, qt.Equals, "previous")
+	c.Assert(previousElement.compareWith, qt.DeepEquals, []executionIdentifier{newElement.identifier})
+	c.Assert(releaseElement.baseline, qt.IsTrue)
+	c.Assert(releaseElement.identifier.GitRef, qt.Equals, "release")
+}
+
+func TestServer_handleRegressionsOfBaseline(t *testing.T) {
+	c := qt.New(t)
+
+	queue = make(executionQueue)
+	newID := executionIdentifier{GitRef: "new", Source: exec.SourceCron, Workload: "OLTP", UUID: "1"}
+	previous := &executionQueueElement{
+		identifier:  executionIdentifier{GitRef: "previous", Source: exec.SourceCron, Workload: "OLTP", UUID: "2"},
+		compareWith: []executionIdentifier{newID},
+		baseline:    true,
+	}
+
+	// The server has no database, comparing the baseline with the new element would fail.
+	s := &Server{bisectRegressions: true}
+	c.Assert(s.handleRegressions(previous), qt.IsNil)
+}
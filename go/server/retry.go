@@ -0,0 +1,116 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/exec"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// maxRetryBackoff caps the exponential backoff between two retries.
+	maxRetryBackoff = 6 * time.Hour
+)
+
+var (
+	defaultRetryPolicies = []string{"infrastructure=3:1m", "vitess_build=0:0s", "benchmark_tool=1:5m", "timeout=1:5m"}
+)
+
+type (
+	// retryPolicy defines how many times an element of the queue can be retried after
+	// failing, and how long to wait before the first retry. The waiting time doubles
+	// after each new failure.
+	retryPolicy struct {
+		retries int
+		backoff time.Duration
+	}
+
+	// retryPolicies maps a category of failure to its retry policy.
+	retryPolicies map[exec.FailureCategory]retryPolicy
+)
+
+// parseRetryPolicies parses a list of "category=retries:backoff" entries, for instance
+// "infrastructure=3:1m". Categories that are not configured use the given fallback.
+func parseRetryPolicies(entries []string, fallback retryPolicy) (retryPolicies, error) {
+	policies := make(retryPolicies, len(exec.FailureCategories))
+	for _, category := range exec.FailureCategories {
+		policies[category] = fallback
+	}
+	for _, entry := range entries {
+		category, value, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid retry policy %q, expected the format category=retries:backoff", entry)
+		}
+		if !slices.Contains(exec.FailureCategories, exec.FailureCategory(category)) {
+			return nil, fmt.Errorf("invalid retry policy %q, unknown failure category %q", entry, category)
+		}
+		rawRetries, rawBackoff, found := strings.Cut(value, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid retry policy %q, expected the format category=retries:backoff", entry)
+		}
+		retries, err := strconv.Atoi(rawRetries)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("invalid number of retries in retry policy %q", entry)
+		}
+		backoff, err := time.ParseDuration(rawBackoff)
+		if err != nil || backoff < 0 {
+			return nil, fmt.Errorf("invalid backoff in retry policy %q", entry)
+		}
+		policies[exec.FailureCategory(category)] = retryPolicy{retries: retries, backoff: backoff}
+	}
+	return policies, nil
+}
+
+// next returns how long to wait before retrying an element that failed the given number
+// of times with the given category. It returns false if the element must not be retried.
+func (rp retryPolicies) next(category exec.FailureCategory, failures int) (time.Duration, bool) {
+	policy, ok := rp[category]
+	if !ok {
+		policy = rp[exec.FailureUnknown]
+	}
+	if failures > policy.retries {
+		return 0, false
+	}
+	delay := policy.backoff
+	for i := 1; i < failures && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff), true
+}
+
+// scheduleRetry records a new failure of the given category for the element and computes
+// when it should be retried. It returns false if the element has no retry left for that
+// category. The caller must hold the queue's mutex.
+func (s *Server) scheduleRetry(element *executionQueueElement, category exec.FailureCategory, now time.Time) bool {
+	if element.failures == nil {
+		element.failures = map[exec.FailureCategory]int{}
+	}
+	element.failures[category]++
+	delay, ok := s.retryPolicies.next(category, element.failures[category])
+	if !ok {
+		return false
+	}
+	element.retryAt = now.Add(delay)
+	return true
+}
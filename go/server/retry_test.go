@@ -0,0 +1,118 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/exec"
+)
+
+func TestParseRetryPolicies(t *testing.T) {
+	fallback := retryPolicy{retries: 1, backoff: 30 * time.Second}
+
+	tests := []struct {
+		name    string
+		entries []string
+		want    retryPolicies
+		wantErr bool
+	}{
+		{
+			name:    "Default policies",
+			entries: defaultRetryPolicies,
+			want: retryPolicies{
+				exec.FailureInfrastructure: {retries: 3, backoff: time.Minute},
+				exec.FailureVitessBuild:    {retries: 0, backoff: 0},
+				exec.FailureBenchmarkTool:  {retries: 1, backoff: 5 * time.Minute},
+				exec.FailureTimeout:        {retries: 1, backoff: 5 * time.Minute},
+				exec.FailureUnknown:        fallback,
+			},
+		},
+		{name: "Unknown category", entries: []string{"network=1:1m"}, wantErr: true},
+		{name: "Missing backoff", entries: []string{"timeout=1"}, wantErr: true},
+		{name: "Invalid retries", entries: []string{"timeout=-1:1m"}, wantErr: true},
+		{name: "Invalid backoff", entries: []string{"timeout=1:soon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			got, err := parseRetryPolicies(tt.entries, fallback)
+			if tt.wantErr {
+				c.Assert(err, qt.IsNotNil)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.HasLen, len(tt.want))
+			for category, want := range tt.want {
+				c.Assert(got[category].retries, qt.Equals, want.retries, qt.Commentf(string(category)))
+				c.Assert(got[category].backoff, qt.Equals, want.backoff, qt.Commentf(string(category)))
+			}
+		})
+	}
+}
+
+func TestRetryPolicies_next(t *testing.T) {
+	policies := retryPolicies{
+		exec.FailureInfrastructure: {retries: 3, backoff: time.Minute},
+		exec.FailureVitessBuild:    {retries: 0},
+		exec.FailureTimeout:        {retries: 10, backoff: time.Hour},
+		exec.FailureUnknown:        {retries: 1, backoff: time.Second},
+	}
+
+	tests := []struct {
+		name      string
+		category  exec.FailureCategory
+		failures  int
+		wantDelay time.Duration
+		wantRetry bool
+	}{
+		{name: "First infrastructure failure", category: exec.FailureInfrastructure, failures: 1, wantDelay: time.Minute, wantRetry: true},
+		{name: "Third infrastructure failure", category: exec.FailureInfrastructure, failures: 3, wantDelay: 4 * time.Minute, wantRetry: true},
+		{name: "Too many infrastructure failures", category: exec.FailureInfrastructure, failures: 4},
+		{name: "Vitess build failures are not retried", category: exec.FailureVitessBuild, failures: 1},
+		{name: "Backoff is capped", category: exec.FailureTimeout, failures: 5, wantDelay: maxRetryBackoff, wantRetry: true},
+		{name: "Missing category uses unknown", category: exec.FailureBenchmarkTool, failures: 1, wantDelay: time.Second, wantRetry: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			delay, retry := policies.next(tt.category, tt.failures)
+			c.Assert(retry, qt.Equals, tt.wantRetry)
+			c.Assert(delay, qt.Equals, tt.wantDelay)
+		})
+	}
+}
+
+func TestServer_scheduleRetry(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := &Server{retryPolicies: retryPolicies{exec.FailureInfrastructure: {retries: 1, backoff: time.Minute}}}
+	element := &executionQueueElement{}
+
+	c.Assert(s.scheduleRetry(element, exec.FailureInfrastructure, now), qt.IsTrue)
+	c.Assert(element.retryAt, qt.Equals, now.Add(time.Minute))
+	c.Assert(element.failures, qt.DeepEquals, map[exec.FailureCategory]int{exec.FailureInfrastructure: 1})
+
+	c.Assert(s.scheduleRetry(element, exec.FailureInfrastructure, now), qt.IsFalse)
+}
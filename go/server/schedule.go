@@ -0,0 +1,266 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/tools/git"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// scheduleTargetMain benchmarks the head of the main branch.
+	scheduleTargetMain = "main"
+
+	// scheduleTargetReleaseBranches benchmarks the head of the release branches. By default
+	// the release branches of the two latest major releases are benchmarked, the branch
+	// field of the schedule can be used to select the branches with a pattern instead.
+	scheduleTargetReleaseBranches = "release-branches"
+
+	// scheduleTargetBranch benchmarks the head of the branch set in the branch field of the schedule.
+	scheduleTargetBranch = "branch"
+
+	// scheduleTargetTags benchmarks the supported releases of Vitess.
+	scheduleTargetTags = "tags"
+
+	// scheduleTargetPullRequests benchmarks the pull requests that have the label of the schedule.
+	scheduleTargetPullRequests = "pull-requests"
+
+	// compareWithPrevious compares a benchmark with the previous benchmark of the same source.
+	compareWithPrevious = "previous"
+
+	// compareWithRelease compares a benchmark with the latest release of its major version.
+	compareWithRelease = "release"
+)
+
+var (
+	scheduleTargets = []string{scheduleTargetMain, scheduleTargetReleaseBranches, scheduleTargetBranch, scheduleTargetTags, scheduleTargetPullRequests}
+	plannerVersions = []string{string(macrobench.Gen4Planner), string(macrobench.V3Planner), string(macrobench.Gen4FallbackPlanner)}
+)
+
+// schedule is the declaration of a recurring benchmark, schedules are read from a YAML file:
+//
+//	schedules:
+//	  - name: nightly
+//	    target: main
+//	    cron: "@midnight"
+//	    workloads: [oltp, tpcc]
+//	    planner_versions: [Gen4]
+//	    compare_with: [previous, release]
+type schedule struct {
+	Name   string `mapstructure:"name"`
+	Target string `mapstructure:"target"`
+
+	// Branch is the branch benchmarked by the "branch" target, or the pattern of the
+	// release branches benchmarked by the "release-branches" target, i.e "release-19.*".
+	Branch string `mapstructure:"branch"`
+
+	// Label is the GitHub label used by the "pull-requests" target.
+	Label string `mapstructure:"label"`
+
+	// Cron is the CRON expression of the schedule, an empty string or "none" disables the
+	// CRON. A "pull-requests" schedule without CRON is still used by the GitHub webhooks.
+	Cron string `mapstructure:"cron"`
+
+	// Workloads to benchmark, all the workloads that are not skipped by default if empty.
+	Workloads []string `mapstructure:"workloads"`
+
+	// PlannerVersions used to run the macrobenchmarks, Gen4 if empty.
+	PlannerVersions []string `mapstructure:"planner_versions"`
+
+	// CompareWith lists the benchmarks each new benchmark is compared with:
+	// "previous" and/or "release". Only branches can be compared.
+	CompareWith []string `mapstructure:"compare_with"`
+}
+
+// loadSchedules reads the schedules declared in the given YAML file.
+func loadSchedules(file string) ([]schedule, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	var schedules []schedule
+	if err := v.UnmarshalKey("schedules", &schedules); err != nil {
+		return nil, fmt.Errorf("invalid schedules in %s: %w", file, err)
+	}
+	return schedules, nil
+}
+
+// defaultSchedules returns the schedules used when no schedule file is configured,
+// they are built from the CRON and pull request label flags.
+func (s *Server) defaultSchedules() []schedule {
+	branchComparisons := []string{compareWithPrevious, compareWithRelease}
+	schedules := []schedule{
+		{Name: "main", Target: scheduleTargetMain, Cron: s.cronSchedule, CompareWith: branchComparisons},
+		{Name: "release-branches", Target: scheduleTargetReleaseBranches, Cron: s.cronSchedule, CompareWith: branchComparisons},
+		{Name: "tags", Target: scheduleTargetTags, Cron: s.cronScheduleTags},
+	}
+	if s.prLabelTrigger != "" {
+		schedules = append(schedules, schedule{Name: "pull-requests", Target: scheduleTargetPullRequests, Cron: s.cronSchedulePullRequests, Label: s.prLabelTrigger, PlannerVersions: []string{string(macrobench.Gen4Planner)}})
+	}
+	if s.prLabelTriggerV3 != "" {
+		schedules = append(schedules, schedule{Name: "pull-requests-v3", Target: scheduleTargetPullRequests, Cron: s.cronSchedulePullRequests, Label: s.prLabelTriggerV3, PlannerVersions: []string{string(macrobench.V3Planner)}})
+	}
+	return schedules
+}
+
+// initSchedules loads and validates the schedules, the benchmark configurations must already be loaded.
+func (s *Server) initSchedules() error {
+	schedules := s.defaultSchedules()
+	if s.schedulesFile != "" {
+		var err error
+		schedules, err = loadSchedules(s.schedulesFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := map[string]bool{}
+	for i := range schedules {
+		sc := &schedules[i]
+		if err := sc.normalize(s.benchmarkConfig); err != nil {
+			return fmt.Errorf("invalid schedule %q: %w", sc.Name, err)
+		}
+		if names[sc.Name] {
+			return fmt.Errorf("duplicated schedule name %q", sc.Name)
+		}
+		names[sc.Name] = true
+	}
+	s.schedules = schedules
+	return nil
+}
+
+// normalize validates the schedule and fills its optional fields with their default value.
+func (sc *schedule) normalize(configs map[string]benchmarkConfig) error {
+	if sc.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if !slices.Contains(scheduleTargets, sc.Target) {
+		return fmt.Errorf("unknown target %q, expected one of %s", sc.Target, strings.Join(scheduleTargets, ", "))
+	}
+	if sc.Cron == "none" {
+		sc.Cron = ""
+	}
+	if sc.Cron != "" {
+		if _, err := cron.ParseStandard(sc.Cron); err != nil {
+			return fmt.Errorf("invalid CRON expression %q: %w", sc.Cron, err)
+		}
+	}
+
+	switch sc.Target {
+	case scheduleTargetBranch:
+		if sc.Branch == "" {
+			return fmt.Errorf("the %s target needs a branch", sc.Target)
+		}
+	case scheduleTargetPullRequests:
+		// The labels used to be configured with quotes for the GitHub search API.
+		sc.Label = strings.Trim(sc.Label, "\"")
+		if sc.Label == "" {
+			return fmt.Errorf("the %s target needs a label", sc.Target)
+		}
+	}
+
+	for _, workload := range sc.Workloads {
+		if _, ok := configs[workload]; !ok {
+			return fmt.Errorf("unknown workload %q", workload)
+		}
+	}
+
+	if len(sc.PlannerVersions) == 0 {
+		for _, version := range git.GetPlannerVersions() {
+			sc.PlannerVersions = append(sc.PlannerVersions, string(version))
+		}
+	}
+	for _, version := range sc.PlannerVersions {
+		if !slices.Contains(plannerVersions, version) {
+			return fmt.Errorf("unknown planner version %q, expected one of %s", version, strings.Join(plannerVersions, ", "))
+		}
+	}
+
+	for _, comparison := range sc.CompareWith {
+		if comparison != compareWithPrevious && comparison != compareWithRelease {
+			return fmt.Errorf("unknown comparison %q, expected %s or %s", comparison, compareWithPrevious, compareWithRelease)
+		}
+	}
+	if len(sc.CompareWith) > 0 && (sc.Target == scheduleTargetTags || sc.Target == scheduleTargetPullRequests) {
+		return fmt.Errorf("the %s target cannot be compared", sc.Target)
+	}
+	return nil
+}
+
+// workloads returns the configuration of the workloads benchmarked by the schedule.
+func (sc schedule) workloads(configs map[string]benchmarkConfig) map[string]benchmarkConfig {
+	res := make(map[string]benchmarkConfig)
+	for workload, config := range configs {
+		if len(sc.Workloads) == 0 && !config.skip || slices.Contains(sc.Workloads, workload) {
+			res[workload] = config
+		}
+	}
+	return res
+}
+
+func (sc schedule) comparesWith(comparison string) bool {
+	return slices.Contains(sc.CompareWith, comparison)
+}
+
+// scheduleHandler returns the function executed every time the CRON of the schedule fires.
+// The outcome of every run is recorded so the health of the schedule can be reported.
+func (s *Server) scheduleHandler(sc schedule) func() {
+	var handler func() error
+	switch sc.Target {
+	case scheduleTargetMain:
+		handler = func() error { return s.branchCronHandler(sc, s.mainBranchCronHandler) }
+	case scheduleTargetReleaseBranches:
+		handler = func() error { return s.branchCronHandler(sc, s.releaseBranchesCronHandler) }
+	case scheduleTargetBranch:
+		handler = func() error { return s.branchCronHandler(sc, s.customBranchCronHandler) }
+	case scheduleTargetTags:
+		handler = func() error { return s.tagsCronHandler(sc) }
+	case scheduleTargetPullRequests:
+		handler = func() error { return s.pullRequestsCronHandler(sc) }
+	default:
+		return nil
+	}
+	return func() {
+		err := handler()
+		s.cronRuns.record(sc.Name, err, time.Now())
+		if err != nil {
+			cronHandlerErrors.WithLabelValues(sc.Name).Inc()
+			slog.Errorf("the handler of the schedule %s failed: %v", sc.Name, err)
+		}
+	}
+}
+
+// pullRequestSchedules returns the schedules benchmarking pull requests.
+func (s *Server) pullRequestSchedules() []schedule {
+	var res []schedule
+	for _, sc := range s.schedules {
+		if sc.Target == scheduleTargetPullRequests {
+			res = append(res, sc)
+		}
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSchedule_normalize(t *testing.T) {
+	configs := map[string]benchmarkConfig{"oltp": {}, "tpcc": {}}
+	tests := []struct {
+		name    string
+		sc      schedule
+		want    schedule
+		wantErr string
+	}{
+		{
+			name: "Defaults",
+			sc:   schedule{Name: "nightly", Target: scheduleTargetMain, Cron: "@midnight"},
+			want: schedule{Name: "nightly", Target: scheduleTargetMain, Cron: "@midnight", PlannerVersions: []string{"Gen4"}},
+		},
+		{
+			name: "No CRON",
+			sc:   schedule{Name: "pr", Target: scheduleTargetPullRequests, Cron: "none", Label: "\"Benchmark me\"", PlannerVersions: []string{"V3"}},
+			want: schedule{Name: "pr", Target: scheduleTargetPullRequests, Label: "Benchmark me", PlannerVersions: []string{"V3"}},
+		},
+		{name: "Missing name", sc: schedule{Target: scheduleTargetMain}, wantErr: "missing name"},
+		{name: "Unknown target", sc: schedule{Name: "a", Target: "nightly"}, wantErr: "unknown target \"nightly\".*"},
+		{name: "Invalid CRON", sc: schedule{Name: "a", Target: scheduleTargetMain, Cron: "every day"}, wantErr: "invalid CRON expression.*"},
+		{name: "Branch without branch", sc: schedule{Name: "a", Target: scheduleTargetBranch}, wantErr: "the branch target needs a branch"},
+		{name: "Pull requests without label", sc: schedule{Name: "a", Target: scheduleTargetPullRequests}, wantErr: "the pull-requests target needs a label"},
+		{name: "Unknown workload", sc: schedule{Name: "a", Target: scheduleTargetMain, Workloads: []string{"oltp", "tpch"}}, wantErr: "unknown workload \"tpch\""},
+		{name: "Unknown planner", sc: schedule{Name: "a", Target: scheduleTargetMain, PlannerVersions: []string{"V4"}}, wantErr: "unknown planner version \"V4\".*"},
+		{name: "Unknown comparison", sc: schedule{Name: "a", Target: scheduleTargetMain, CompareWith: []string{"tag"}}, wantErr: "unknown comparison \"tag\".*"},
+		{name: "Tags compared", sc: schedule{Name: "a", Target: scheduleTargetTags, CompareWith: []string{compareWithPrevious}}, wantErr: "the tags target cannot be compared"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			err := tt.sc.normalize(configs)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(tt.sc, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestSchedule_workloads(t *testing.T) {
+	configs := map[string]benchmarkConfig{"oltp": {}, "tpcc": {}, "tpcc_fk": {skip: true}}
+	tests := []struct {
+		name      string
+		workloads []string
+		want      []string
+	}{
+		{name: "All the workloads that are not skipped", want: []string{"oltp", "tpcc"}},
+		{name: "Listed workloads", workloads: []string{"tpcc", "tpcc_fk"}, want: []string{"tpcc", "tpcc_fk"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			var got []string
+			for workload := range (schedule{Workloads: tt.workloads}).workloads(configs) {
+				got = append(got, workload)
+			}
+			c.Assert(got, qt.ContentEquals, tt.want)
+		})
+	}
+}
+
+func TestServer_initSchedules(t *testing.T) {
+	c := qt.New(t)
+	configs := map[string]benchmarkConfig{"oltp": {}, "tpcc": {}}
+
+	s := &Server{
+		benchmarkConfig:          configs,
+		cronSchedule:             "@midnight",
+		cronSchedulePullRequests: "none",
+		cronScheduleTags:         "*/1 * * * *",
+		prLabelTrigger:           "\"Benchmark me\"",
+	}
+	c.Assert(s.initSchedules(), qt.IsNil)
+	c.Assert(s.schedules, qt.HasLen, 4)
+	c.Assert(s.pullRequestSchedules(), qt.DeepEquals, []schedule{
+		{Name: "pull-requests", Target: scheduleTargetPullRequests, Label: "Benchmark me", PlannerVersions: []string{"Gen4"}},
+	})
+
+	file := filepath.Join(t.TempDir(), "schedules.yaml")
+	err := os.WriteFile(file, []byte(`schedules:
+  - name: nightly
+    target: main
+    cron: "@midnight"
+    workloads: [oltp]
+    planner_versions: [Gen4, V3]
+    compare_with: [previous]
+  - name: release-19
+    target: branch
+    branch: release-19.0
+    cron: "0 2 * * *"
+`), 0o644)
+	c.Assert(err, qt.IsNil)
+
+	s = &Server{benchmarkConfig: configs, schedulesFile: file}
+	c.Assert(s.initSchedules(), qt.IsNil)
+	c.Assert(s.schedules, qt.DeepEquals, []schedule{
+		{Name: "nightly", Target: scheduleTargetMain, Cron: "@midnight", Workloads: []string{"oltp"}, PlannerVersions: []string{"Gen4", "V3"}, CompareWith: []string{compareWithPrevious}},
+		{Name: "release-19", Target: scheduleTargetBranch, Branch: "release-19.0", Cron: "0 2 * * *", PlannerVersions: []string{"Gen4"}},
+	})
+	c.Assert(s.pullRequestSchedules(), qt.HasLen, 0)
+
+	err = os.WriteFile(file, []byte("schedules:\n  - {name: a, target: main}\n  - {name: a, target: tags}\n"), 0o644)
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.initSchedules(), qt.ErrorMatches, "duplicated schedule name \"a\"")
+}
@@ -19,16 +19,28 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
 	"path"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/exec/metrics"
 	"github.com/vitessio/arewefastyet/go/slack"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/artifact"
 	"github.com/vitessio/arewefastyet/go/storage/psdb"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
 	"github.com/vitessio/arewefastyet/go/tools/github"
+	"github.com/vitessio/arewefastyet/go/tools/microbench"
 	"github.com/vitessio/arewefastyet/go/tools/server"
 
 	"github.com/gin-gonic/gin"
@@ -43,14 +55,39 @@ const (
 	flagCronSchedule                         = "web-cron-schedule"
 	flagCronSchedulePullRequests             = "web-cron-schedule-pull-requests"
 	flagCronScheduleTags                     = "web-cron-schedule-tags"
+	flagSchedulesFile                        = "web-schedules-file"
 	flagPullRequestLabelTrigger              = "web-pr-label-trigger"
 	flagPullRequestLabelTriggerWithPlannerV3 = "web-pr-label-trigger-planner-v3"
 	flagCronNbRetry                          = "web-cron-nb-retry"
+	flagCronRetryDelay                       = "web-cron-retry-delay"
+	flagCronRetryPolicies                    = "web-cron-retry-policies"
 	flagBenchmarkConfigPath                  = "web-benchmark-config-path"
 	flagFilterBySource                       = "web-source-filter"
 	flagExcludeFilterBySource                = "web-source-exclude-filter"
 	flagRequestRunKey                        = "web-request-run-key"
 	flagGhAuth                               = "admin-auth"
+	flagBenchmarkHosts                       = "web-benchmark-hosts"
+	flagQueueSourceWeights                   = "web-queue-source-weights"
+	flagQueuePullRequestWeight               = "web-queue-pull-request-weight"
+	flagQueueAgeWeight                       = "web-queue-age-weight"
+	flagPullRequestComment                   = "web-pr-comment"
+	flagWebsiteURL                           = "web-website-url"
+	flagRegressionThreshold                  = "web-regression-threshold"
+	flagRegressionThresholdMicro             = "web-regression-threshold-micro"
+	flagBisectRegressions                    = "web-bisect-regressions"
+	flagHeartbeatTimeout                     = "web-heartbeat-timeout"
+	flagArtifactsDir                         = "web-artifacts-dir"
+	flagArtifactsRetention                   = "web-artifacts-retention"
+	flagVitessCloneMaxAge                    = "web-vitess-clone-max-age"
+
+	// keyExecServerAddress is the configuration key used by exec.Exec to know on which
+	// server the benchmark must be executed. It is used as the only benchmark host when
+	// no host pool is configured.
+	keyExecServerAddress = "exec-server-address"
+
+	// keyExecRootDir is the configuration key used by exec.Exec to know in which
+	// directory the executions write their files, including their logs.
+	keyExecRootDir = "exec-root-dir"
 
 	// keyMinimumVitessVersion is used to define on which minimum Vitess version a given
 	// benchmark should be run. Only the major version is counted. This key/value is located
@@ -62,6 +99,9 @@ type benchmarkConfig struct {
 	file string
 	v    *viper.Viper
 	skip bool
+
+	// timeouts are the time budgets of the executions of the workload.
+	timeouts exec.Timeouts
 }
 
 type Server struct {
@@ -71,22 +111,73 @@ type Server struct {
 	vitessPathMu    sync.Mutex
 	localVitessPath string
 
-	dbCfg    *psdb.Config
-	dbClient *psdb.Client
+	// dbCfg is the configuration of PlanetScaleDB, sqliteCfg the configuration of the
+	// SQLite database used instead of it when set, for local development.
+	dbCfg     *psdb.Config
+	sqliteCfg *sqlite.Config
+	dbClient  storage.Database
 
 	// Configuration used to send message to Slack.
 	slackConfig slack.Config
 
+	// Thresholds, in percent, above which a significant change of a metric is announced
+	// as a regression on Slack, for macrobenchmarks and microbenchmarks.
+	regressionThreshold      float64
+	regressionThresholdMicro float64
+
+	// bisectRegressions enables the automatic bisection of the regressions between two
+	// nightly macrobenchmarks of main.
+	bisectRegressions bool
+
+	// heartbeatTimeout is the time after which a started execution that stopped sending
+	// heartbeats is considered stuck and is marked as failed.
+	heartbeatTimeout time.Duration
+
+	// artifactsDir is the directory of the store in which the artifacts of the executions
+	// are collected, they are removed after artifactsRetention. An empty directory disables
+	// the collection of the artifacts, a retention of zero keeps them forever.
+	artifactsDir       string
+	artifactsRetention time.Duration
+	artifacts          *artifact.Store
+
+	// vitessCloneMaxAge is the time after which the local clone of Vitess is reported as
+	// unhealthy if it was not fetched, zero disables the check of its freshness.
+	vitessCloneMaxAge time.Duration
+
+	// metricsConfig is the configuration of the backend from which the macrobenchmarks
+	// read their metrics, it is only used to check that the backend is reachable.
+	metricsConfig metrics.Config
+
+	// cronRuns records the outcome of the runs of the CRON of every schedule.
+	cronRuns cronRuns
+
+	// done is closed once the server shuts down, the streams of logs and events stop then,
+	// otherwise they would keep the HTTP server from shutting down.
+	done <-chan struct{}
+
 	cronSchedule             string
 	cronSchedulePullRequests string
 	cronScheduleTags         string
 	cronNbRetry              int
+	cronRetryDelay           time.Duration
+	cronRetryPolicies        []string
+	retryPolicies            retryPolicies
+
+	// schedulesFile is the YAML file declaring the schedules, when empty the schedules
+	// are built from the CRON and pull request label flags.
+	schedulesFile string
+	schedules     []schedule
 
 	benchmarkConfigPath string
 
 	prLabelTrigger   string
 	prLabelTriggerV3 string
 
+	// prComment enables the comments summarizing the benchmarks of pull requests,
+	// they link to the website located at websiteURL.
+	prComment  bool
+	websiteURL string
+
 	// benchmarkConfig is a map with the workload name as the key and the configuration
 	// of that given workload as a value of the map. The value is a benchmarkConfig which
 	// contains the file (yaml) configuration of the benchmark.
@@ -101,6 +192,17 @@ type Server struct {
 
 	requestRunKey string
 
+	// benchmarkHosts is the list of addresses of the servers on which benchmarks are
+	// executed, and hosts is the pool created from it. Each host runs one execution at a time.
+	benchmarkHosts []string
+	hosts          hostPool
+
+	// Configuration of the policy used to decide in which order the queue is executed.
+	queueSourceWeights     []string
+	queuePullRequestWeight int
+	queueAgeWeight         float64
+	priorityPolicy         queuePriorityPolicy
+
 	// Mode used to run the server.
 	server.Mode
 }
@@ -112,16 +214,32 @@ func (s *Server) AddToCommand(cmd *cobra.Command) {
 
 	// execution configuration flags
 	cmd.Flags().StringVar(&s.benchmarkConfigPath, flagBenchmarkConfigPath, "", "Path to the configuration file folder for the benchmarks.")
-	cmd.Flags().StringVar(&s.cronSchedule, flagCronSchedule, "@midnight", "Execution CRON schedule defaults to every day at midnight. An empty string will result in no CRON.")
-	cmd.Flags().StringVar(&s.cronSchedulePullRequests, flagCronSchedulePullRequests, "*/5 * * * *", "Execution CRON schedule for pull requests benchmarks. An empty string will result in no CRON. Defaults to an execution every 5 minutes.")
-	cmd.Flags().StringVar(&s.cronScheduleTags, flagCronScheduleTags, "*/1 * * * *", "Execution CRON schedule for tags/releases benchmarks. An empty string will result in no CRON. Defaults to an execution every minute.")
-	cmd.Flags().IntVar(&s.cronNbRetry, flagCronNbRetry, 1, "Number of retries allowed for each cron job.")
-	cmd.Flags().StringVar(&s.prLabelTrigger, flagPullRequestLabelTrigger, "Benchmark me", "GitHub Pull Request label that will trigger the execution of new execution.")
-	cmd.Flags().StringVar(&s.prLabelTriggerV3, flagPullRequestLabelTriggerWithPlannerV3, "Benchmark me (V3)", "GitHub Pull Request label that will trigger the execution of new execution using the V3 planner.")
+	cmd.Flags().StringVar(&s.cronSchedule, flagCronSchedule, "@midnight", "Execution CRON schedule defaults to every day at midnight. An empty string will result in no CRON. Ignored if a schedules file is used.")
+	cmd.Flags().StringVar(&s.cronSchedulePullRequests, flagCronSchedulePullRequests, "*/5 * * * *", "Execution CRON schedule for pull requests benchmarks. An empty string will result in no CRON. Defaults to an execution every 5 minutes. Ignored if a schedules file is used.")
+	cmd.Flags().StringVar(&s.cronScheduleTags, flagCronScheduleTags, "*/1 * * * *", "Execution CRON schedule for tags/releases benchmarks. An empty string will result in no CRON. Defaults to an execution every minute. Ignored if a schedules file is used.")
+	cmd.Flags().StringVar(&s.schedulesFile, flagSchedulesFile, "", "Path to the YAML file declaring the benchmark schedules. If empty, the schedules are built from the CRON and pull request label flags.")
+	cmd.Flags().IntVar(&s.cronNbRetry, flagCronNbRetry, 1, "Number of retries allowed for each cron job when the category of the failure has no retry policy.")
+	cmd.Flags().DurationVar(&s.cronRetryDelay, flagCronRetryDelay, 30*time.Second, "Time to wait before retrying a failed execution when the category of the failure has no retry policy.")
+	cmd.Flags().StringSliceVar(&s.cronRetryPolicies, flagCronRetryPolicies, defaultRetryPolicies, "Retry policy of each category of failure (infrastructure, vitess_build, benchmark_tool, timeout, unknown), formatted as category=retries:backoff. The backoff doubles after each failure.")
+	cmd.Flags().StringVar(&s.prLabelTrigger, flagPullRequestLabelTrigger, "Benchmark me", "GitHub Pull Request label that will trigger the execution of new execution. Ignored if a schedules file is used.")
+	cmd.Flags().StringVar(&s.prLabelTriggerV3, flagPullRequestLabelTriggerWithPlannerV3, "Benchmark me (V3)", "GitHub Pull Request label that will trigger the execution of new execution using the V3 planner. Ignored if a schedules file is used.")
+	cmd.Flags().BoolVar(&s.prComment, flagPullRequestComment, true, "Comment on the benchmarked GitHub Pull Requests with a summary of their results.")
+	cmd.Flags().StringVar(&s.websiteURL, flagWebsiteURL, "https://benchmark.vitess.io", "URL of the arewefastyet website, used to link to the results.")
+	cmd.Flags().Float64Var(&s.regressionThreshold, flagRegressionThreshold, 5, "Percentage above which a statistically significant change of a macrobenchmark metric is announced as a regression on Slack.")
+	cmd.Flags().Float64Var(&s.regressionThresholdMicro, flagRegressionThresholdMicro, microbench.DefaultRegressionThreshold, "Percentage above which a decrease of a microbenchmark metric is announced as a regression on Slack.")
+	cmd.Flags().BoolVar(&s.bisectRegressions, flagBisectRegressions, true, "Automatically bisect the regressions between two nightly macrobenchmarks of main to find the commit that introduced them.")
+	cmd.Flags().DurationVar(&s.heartbeatTimeout, flagHeartbeatTimeout, 5*time.Minute, "Time after which a started execution that stopped sending heartbeats is considered stuck and marked as failed.")
+	cmd.Flags().StringVar(&s.artifactsDir, flagArtifactsDir, "", "Directory in which the logs and profiles of the executions are stored. An empty string disables the collection of artifacts.")
+	cmd.Flags().DurationVar(&s.artifactsRetention, flagArtifactsRetention, 30*24*time.Hour, "Time after which the artifacts of an execution are removed. Zero keeps them forever.")
+	cmd.Flags().DurationVar(&s.vitessCloneMaxAge, flagVitessCloneMaxAge, 24*time.Hour, "Time after which the local clone of Vitess is reported as unhealthy if it was not fetched. Zero disables the check.")
 	cmd.Flags().StringSliceVar(&s.sourceFilter, flagFilterBySource, nil, "List of execution source that should be run. By default, all sources are ran.")
 	cmd.Flags().StringSliceVar(&s.excludeSourceFilter, flagExcludeFilterBySource, nil, "List of execution source to not execute. By default, all sources are ran.")
 	cmd.Flags().StringVar(&s.requestRunKey, flagRequestRunKey, "", "Key to authenticate requests for custom benchmark runs.")
 	cmd.Flags().StringVar(&s.ghTokenSalt, flagGhAuth, "", "The salt string to salt the GitHub Token")
+	cmd.Flags().StringSliceVar(&s.benchmarkHosts, flagBenchmarkHosts, nil, "List of IP addresses of the servers on which benchmarks are executed. Defaults to the value of exec-server-address.")
+	cmd.Flags().StringSliceVar(&s.queueSourceWeights, flagQueueSourceWeights, defaultQueueSourceWeights, "Priority given to the elements of the queue based on their source, formatted as source=weight. A source ending with '*' matches all the sources with the given prefix.")
+	cmd.Flags().IntVar(&s.queuePullRequestWeight, flagQueuePullRequestWeight, 50, "Priority added to the elements of the queue that benchmark a pull request.")
+	cmd.Flags().Float64Var(&s.queueAgeWeight, flagQueueAgeWeight, 10, "Priority added to the elements of the queue for every hour they spend waiting in the queue.")
 
 	_ = viper.BindPFlag(flagPort, cmd.Flags().Lookup(flagPort))
 	_ = viper.BindPFlag(flagVitessPath, cmd.Flags().Lookup(flagVitessPath))
@@ -129,19 +247,39 @@ func (s *Server) AddToCommand(cmd *cobra.Command) {
 	_ = viper.BindPFlag(flagCronSchedule, cmd.Flags().Lookup(flagCronSchedule))
 	_ = viper.BindPFlag(flagCronSchedulePullRequests, cmd.Flags().Lookup(flagCronSchedulePullRequests))
 	_ = viper.BindPFlag(flagCronScheduleTags, cmd.Flags().Lookup(flagCronScheduleTags))
+	_ = viper.BindPFlag(flagSchedulesFile, cmd.Flags().Lookup(flagSchedulesFile))
 	_ = viper.BindPFlag(flagCronNbRetry, cmd.Flags().Lookup(flagCronNbRetry))
+	_ = viper.BindPFlag(flagCronRetryDelay, cmd.Flags().Lookup(flagCronRetryDelay))
+	_ = viper.BindPFlag(flagCronRetryPolicies, cmd.Flags().Lookup(flagCronRetryPolicies))
 	_ = viper.BindPFlag(flagPullRequestLabelTrigger, cmd.Flags().Lookup(flagPullRequestLabelTrigger))
 	_ = viper.BindPFlag(flagPullRequestLabelTriggerWithPlannerV3, cmd.Flags().Lookup(flagPullRequestLabelTriggerWithPlannerV3))
+	_ = viper.BindPFlag(flagPullRequestComment, cmd.Flags().Lookup(flagPullRequestComment))
+	_ = viper.BindPFlag(flagWebsiteURL, cmd.Flags().Lookup(flagWebsiteURL))
+	_ = viper.BindPFlag(flagRegressionThreshold, cmd.Flags().Lookup(flagRegressionThreshold))
+	_ = viper.BindPFlag(flagRegressionThresholdMicro, cmd.Flags().Lookup(flagRegressionThresholdMicro))
+	_ = viper.BindPFlag(flagBisectRegressions, cmd.Flags().Lookup(flagBisectRegressions))
+	_ = viper.BindPFlag(flagHeartbeatTimeout, cmd.Flags().Lookup(flagHeartbeatTimeout))
+	_ = viper.BindPFlag(flagArtifactsDir, cmd.Flags().Lookup(flagArtifactsDir))
+	_ = viper.BindPFlag(flagArtifactsRetention, cmd.Flags().Lookup(flagArtifactsRetention))
+	_ = viper.BindPFlag(flagVitessCloneMaxAge, cmd.Flags().Lookup(flagVitessCloneMaxAge))
 	_ = viper.BindPFlag(flagFilterBySource, cmd.Flags().Lookup(flagFilterBySource))
 	_ = viper.BindPFlag(flagExcludeFilterBySource, cmd.Flags().Lookup(flagExcludeFilterBySource))
 	_ = viper.BindPFlag(flagRequestRunKey, cmd.Flags().Lookup(flagRequestRunKey))
 	_ = viper.BindPFlag(flagGhAuth, cmd.Flags().Lookup(flagGhAuth))
+	_ = viper.BindPFlag(flagBenchmarkHosts, cmd.Flags().Lookup(flagBenchmarkHosts))
+	_ = viper.BindPFlag(flagQueueSourceWeights, cmd.Flags().Lookup(flagQueueSourceWeights))
+	_ = viper.BindPFlag(flagQueuePullRequestWeight, cmd.Flags().Lookup(flagQueuePullRequestWeight))
+	_ = viper.BindPFlag(flagQueueAgeWeight, cmd.Flags().Lookup(flagQueueAgeWeight))
 
 	s.slackConfig.AddToCommand(cmd)
 	if s.dbCfg == nil {
 		s.dbCfg = &psdb.Config{}
 	}
 	s.dbCfg.AddToCommand(cmd)
+	if s.sqliteCfg == nil {
+		s.sqliteCfg = &sqlite.Config{}
+	}
+	s.sqliteCfg.AddToCommand(cmd)
 	if s.ghApp == nil {
 		s.ghApp = &github.App{}
 	}
@@ -178,6 +316,36 @@ func (s *Server) Init() error {
 	if err := s.createStorages(); err != nil {
 		return err
 	}
+	if err := s.checkSchema(); err != nil {
+		return err
+	}
+
+	hosts := s.benchmarkHosts
+	if len(hosts) == 0 {
+		hosts = []string{viper.GetString(keyExecServerAddress)}
+	}
+	s.hosts = newHostPool(hosts)
+
+	sourceWeights, err := parseSourceWeights(s.queueSourceWeights)
+	if err != nil {
+		return err
+	}
+	s.retryPolicies, err = parseRetryPolicies(s.cronRetryPolicies, retryPolicy{retries: s.cronNbRetry, backoff: s.cronRetryDelay})
+	if err != nil {
+		return err
+	}
+	if s.artifactsDir != "" {
+		s.artifacts = artifact.NewStore(s.artifactsDir)
+	}
+	s.metricsConfig.AddToViper(viper.GetViper())
+	if s.heartbeatTimeout < 2*exec.HeartbeatInterval {
+		return fmt.Errorf("the heartbeat timeout must be at least %s", 2*exec.HeartbeatInterval)
+	}
+	s.priorityPolicy = weightedPriorityPolicy{
+		sourceWeights:     sourceWeights,
+		pullRequestWeight: s.queuePullRequestWeight,
+		ageWeight:         s.queueAgeWeight,
+	}
 
 	s.benchmarkConfig = map[string]benchmarkConfig{
 		// "micro":         {file: path.Join(s.benchmarkConfigPath, "micro.yaml"), v: viper.New(), skip: true},
@@ -200,13 +368,18 @@ func (s *Server) Init() error {
 		if err := config.v.ReadInConfig(); err != nil {
 			slog.Error(err)
 		}
+		config.timeouts, err = exec.TimeoutsFromConfig(config.v, workload)
+		if err != nil {
+			return fmt.Errorf("invalid timeouts for the %s workload: %w", workload, err)
+		}
+		s.benchmarkConfig[workload] = config
 		// skipping tpcc unsharded and fk workload for comparison
 		if workload == "micro" || strings.Contains(workload, "tpcc_") {
 			continue
 		}
 		s.workloads = append(s.workloads, strings.ToUpper(workload))
 	}
-	return nil
+	return s.initSchedules()
 }
 
 func (s *Server) Run() error {
@@ -214,7 +387,13 @@ func (s *Server) Run() error {
 		return errors.New(server.ErrorIncorrectConfiguration)
 	}
 
-	err := s.createCrons()
+	// The context is canceled when the server receives SIGINT or SIGTERM, which
+	// stops the CRONs, the queue watcher and the HTTP server.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	s.done = ctx.Done()
+
+	err := s.createCrons(ctx)
 	if err != nil {
 		return err
 	}
@@ -236,8 +415,14 @@ func (s *Server) Run() error {
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Metrics and health
+	s.router.GET("/metrics", metricsHandler(newMetricsRegistry()))
+	s.router.GET("/healthz", s.healthz)
+	s.router.GET("/readyz", s.readyz)
+
 	// API
 	s.router.GET("/api/workloads", s.getWorkloadList)
+	s.router.GET("/api/workloads/timeouts", s.getWorkloadTimeouts)
 	s.router.GET("/api/recent", s.getRecentExecutions)
 	s.router.GET("/api/queue", s.getExecutionsQueue)
 	s.router.GET("/api/vitess/refs", s.getLatestVitessGitRef)
@@ -257,8 +442,52 @@ func (s *Server) Run() error {
 	s.router.GET("/api/run/delete", s.deleteRun)
 	s.router.POST("/api/executions/add", s.addExecutions)
 	s.router.POST("/api/executions/clear", s.clearExecutionQueue)
+	s.router.POST("/api/executions/cancel", s.cancelExecution)
+	s.router.GET("/api/bisections", s.getBisections)
+	s.router.GET("/api/bisections/:id", s.getBisection)
+	s.router.GET("/api/executions/:uuid/logs", s.streamExecutionLogs)
+	s.router.GET("/api/execution/:uuid/artifacts", s.getExecutionArtifacts)
+	s.router.GET("/api/execution/:uuid/artifacts/*name", s.downloadExecutionArtifact)
+	// The artifacts are also served next to the logs of the execution.
+	s.router.GET("/api/executions/:uuid/artifacts", s.getExecutionArtifacts)
+	s.router.GET("/api/executions/:uuid/artifacts/*name", s.downloadExecutionArtifact)
+	s.router.GET("/api/profile/compare", s.compareProfiles)
+	s.router.GET("/api/events", s.streamQueueEvents)
+	s.router.POST("/api/bisections/start", s.startBisectionRequest)
+
+	// GitHub webhooks are received on their own port, only if we can verify their signature.
+	if s.ghApp.WebhookEnabled() {
+		webhookRouter := gin.Default()
+		webhookRouter.POST(webhookPath, s.handleGitHubWebhook)
+		webhookServer := &http.Server{Addr: ":" + s.ghApp.Port(), Handler: webhookRouter}
+		go shutdownOnDone(ctx, webhookServer)
+		go func() {
+			slog.Info("Listening for GitHub webhooks on port ", s.ghApp.Port())
+			if err := webhookServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error(err)
+			}
+		}()
+	}
+
+	httpServer := &http.Server{Addr: ":" + s.port, Handler: s.router}
+	go shutdownOnDone(ctx, httpServer)
 
-	return s.router.Run(":" + s.port)
+	slog.Info("Listening on port ", s.port)
+	err = httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// shutdownOnDone gracefully shuts down the given HTTP server once ctx is done.
+func shutdownOnDone(ctx context.Context, httpServer *http.Server) {
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error(err)
+	}
 }
 
 func Run(port, localVitessPath string) error {
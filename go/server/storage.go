@@ -18,10 +18,43 @@
 
 package server
 
-func (s *Server) createStorages() (err error) {
-	s.dbClient, err = s.dbCfg.NewClient()
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/migrate"
+)
+
+func (s *Server) createStorages() error {
+	// A SQLite database replaces PlanetScaleDB when it is configured, which is
+	// convenient to run the server locally.
+	if s.sqliteCfg != nil && s.sqliteCfg.IsValid() {
+		client, err := s.sqliteCfg.NewClient()
+		if err != nil {
+			return err
+		}
+		s.dbClient = client
+		return nil
+	}
+	client, err := s.dbCfg.NewClient()
+	if err != nil {
+		return err
+	}
+	s.dbClient = client
+	return nil
+}
+
+// checkSchema returns an error if the schema of the database is not at the version
+// of the latest migration embedded in the binary.
+func (s *Server) checkSchema() error {
+	m, err := migrate.New(s.dbClient)
 	if err != nil {
-		return
+		return err
 	}
-	return
+	return m.Check()
+}
+
+// db returns the database client bound to the context of the given request, the queries
+// of the handler are therefore canceled when the request is.
+func (s *Server) db(c *gin.Context) storage.SQLClient {
+	return storage.WithContext(c.Request.Context(), s.dbClient)
 }
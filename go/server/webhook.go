@@ -0,0 +1,138 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vitessio/arewefastyet/go/tools/github"
+)
+
+const (
+	webhookPath = "/webhooks/github"
+
+	// vitessRepository is the repository whose pull requests are benchmarked.
+	vitessRepository = "vitessio/vitess"
+)
+
+// handleGitHubWebhook receives the webhooks sent by GitHub. Their signature is
+// verified before anything else, and only pull_request events are handled.
+// The event is handled in the background, GitHub expects a quick answer.
+func (s *Server) handleGitHubWebhook(c *gin.Context) {
+	event, err := s.ghApp.ParsePullRequestWebhook(c.Request)
+	if err != nil {
+		slog.Warnf("rejected GitHub webhook: %v", err)
+		switch {
+		case errors.Is(err, github.ErrWebhookNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, &ErrorAPI{Error: err.Error()})
+		case errors.Is(err, github.ErrInvalidWebhookSignature):
+			c.JSON(http.StatusUnauthorized, &ErrorAPI{Error: "Unauthorized"})
+		default:
+			c.JSON(http.StatusBadRequest, &ErrorAPI{Error: err.Error()})
+		}
+		return
+	}
+
+	if event == nil || event.Repo != vitessRepository {
+		c.JSON(http.StatusOK, "")
+		return
+	}
+	go s.handlePullRequestEvent(*event)
+	c.JSON(http.StatusAccepted, "")
+}
+
+// handlePullRequestEvent updates the execution queue after a change on a pull request:
+//   - Adding a trigger label, or pushing to a pull request with a trigger label, queues
+//     the benchmarks of the head of the pull request and removes the outdated ones.
+//   - Removing a trigger label removes the pending benchmarks that were using it.
+//   - Closing a pull request removes all its pending benchmarks.
+func (s *Server) handlePullRequestEvent(event github.PullRequestEvent) {
+	switch event.Action {
+	case github.PullRequestActionLabeled:
+		if sc, ok := s.findPullRequestSchedule(event.Label); ok {
+			s.queuePullRequest(event, []schedule{sc})
+		}
+	case github.PullRequestActionSynchronize:
+		var schedules []schedule
+		for _, sc := range s.pullRequestSchedules() {
+			if event.HasLabel(sc.Label) {
+				schedules = append(schedules, sc)
+			}
+		}
+		if len(schedules) > 0 {
+			s.queuePullRequest(event, schedules)
+		}
+	case github.PullRequestActionUnlabeled:
+		sc, ok := s.findPullRequestSchedule(event.Label)
+		if !ok {
+			return
+		}
+		for _, other := range s.pullRequestSchedules() {
+			if other.Label != sc.Label && event.HasLabel(other.Label) {
+				// The pull request is still benchmarked with another label, we only
+				// remove the benchmarks that were using the planners of the removed label.
+				s.removePullRequestFromQueue(event.Number, sc.PlannerVersions...)
+				return
+			}
+		}
+		s.removePullRequestFromQueue(event.Number)
+	case github.PullRequestActionClosed:
+		s.removePullRequestFromQueue(event.Number)
+	}
+}
+
+func (s *Server) findPullRequestSchedule(label string) (schedule, bool) {
+	for _, sc := range s.pullRequestSchedules() {
+		if sc.Label == label {
+			return sc, true
+		}
+	}
+	return schedule{}, false
+}
+
+func (s *Server) queuePullRequest(event github.PullRequestEvent, schedules []schedule) {
+	if event.HeadSHA == "" || event.Number == 0 {
+		return
+	}
+
+	s.vitessPathMu.Lock()
+	defer s.vitessPathMu.Unlock()
+
+	var elements []*executionQueueElement
+	pulled := false
+	for _, sc := range schedules {
+		prElements, err := s.createPullRequestElements(sc, event.HeadSHA, event.BaseSHA, event.Number)
+		if err != nil && !pulled {
+			// The base of the pull request might be more recent than our local clone
+			// of Vitess, we update it once and try again.
+			pulled = true
+			if err = s.pullLocalVitess(); err == nil {
+				prElements, err = s.createPullRequestElements(sc, event.HeadSHA, event.BaseSHA, event.Number)
+			}
+		}
+		if err != nil {
+			slog.Warnf("could not queue pull request %d: %v", event.Number, err)
+			continue
+		}
+		elements = append(elements, prElements...)
+	}
+	s.addPullRequestElementsToQueue(elements)
+}
@@ -0,0 +1,107 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package server
+
+import (
+	"sort"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/exec"
+	"github.com/vitessio/arewefastyet/go/tools/github"
+	"github.com/vitessio/arewefastyet/go/tools/macrobench"
+	"go.uber.org/zap"
+)
+
+func TestServer_handlePullRequestEvent(t *testing.T) {
+	SetSLogger(zap.NewNop().Sugar())
+	newElement := func(uuid string, pullNb int, plannerVersion macrobench.PlannerVersion, executing bool) *executionQueueElement {
+		return &executionQueueElement{
+			Executing: executing,
+			identifier: executionIdentifier{
+				GitRef:         "head",
+				Source:         exec.SourcePullRequest,
+				Workload:       "oltp",
+				PlannerVersion: string(plannerVersion),
+				PullNb:         pullNb,
+				UUID:           uuid,
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		event github.PullRequestEvent
+		want  []string
+	}{
+		{
+			name:  "Closed",
+			event: github.PullRequestEvent{Action: github.PullRequestActionClosed, Number: 1},
+			want:  []string{"executing", "other-pr"},
+		},
+		{
+			name:  "Last trigger label removed",
+			event: github.PullRequestEvent{Action: github.PullRequestActionUnlabeled, Number: 1, Label: "Benchmark me"},
+			want:  []string{"executing", "other-pr"},
+		},
+		{
+			name:  "Trigger label removed while another one remains",
+			event: github.PullRequestEvent{Action: github.PullRequestActionUnlabeled, Number: 1, Label: "Benchmark me", Labels: []string{"Benchmark me (V3)"}},
+			want:  []string{"executing", "other-pr", "v3"},
+		},
+		{
+			name:  "Unrelated label removed",
+			event: github.PullRequestEvent{Action: github.PullRequestActionUnlabeled, Number: 1, Label: "Type: Bug"},
+			want:  []string{"executing", "gen4", "other-pr", "v3"},
+		},
+		{
+			name:  "Unrelated label added",
+			event: github.PullRequestEvent{Action: github.PullRequestActionLabeled, Number: 1, Label: "Type: Bug", HeadSHA: "new-head"},
+			want:  []string{"executing", "gen4", "other-pr", "v3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			queue = make(executionQueue)
+			for _, element := range []*executionQueueElement{
+				newElement("gen4", 1, macrobench.Gen4Planner, false),
+				newElement("v3", 1, macrobench.V3Planner, false),
+				newElement("executing", 1, macrobench.Gen4Planner, true),
+				newElement("other-pr", 2, macrobench.Gen4Planner, false),
+			} {
+				queue[element.identifier] = element
+			}
+			s := &Server{schedules: []schedule{
+				{Name: "pr", Target: scheduleTargetPullRequests, Label: "Benchmark me", PlannerVersions: []string{string(macrobench.Gen4Planner)}},
+				{Name: "pr-v3", Target: scheduleTargetPullRequests, Label: "Benchmark me (V3)", PlannerVersions: []string{string(macrobench.V3Planner)}},
+			}}
+
+			s.handlePullRequestEvent(tt.event)
+
+			var got []string
+			for id := range queue {
+				got = append(got, id.UUID)
+			}
+			sort.Strings(got)
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
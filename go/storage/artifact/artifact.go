@@ -0,0 +1,194 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package artifact
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+const (
+	// DirName is the name of the sub-directory of an execution's directory in which
+	// the files collected during the execution are saved.
+	DirName = "artifacts"
+
+	KindLog     = "log"
+	KindProfile = "profile"
+	KindFile    = "file"
+)
+
+// Artifact is a file produced by an execution.
+type Artifact struct {
+	ExecUUID string `json:"exec_uuid"`
+
+	// Name is the path of the file relative to the directory of the execution.
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Digest    string    `json:"digest"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// kindOf returns the kind of the artifact with the given name.
+func kindOf(name string) string {
+	base := filepath.Base(name)
+	switch {
+	case strings.HasSuffix(base, ".log"):
+		return KindLog
+	case strings.HasPrefix(name, "profiles/"), strings.HasSuffix(base, ".pprof"), strings.Contains(base, "prof_"):
+		return KindProfile
+	}
+	return KindFile
+}
+
+// Collect saves the logs of the execution and the files of its DirName sub-directory in the
+// store, and records them in the database. The name of the collected files is their path
+// relative to the artifacts directory, or to the execution directory for the logs.
+func Collect(client storage.SQLClient, store *Store, execUUID, dir string, logs []string) ([]Artifact, error) {
+	store.gc.RLock()
+	defer store.gc.RUnlock()
+
+	type file struct{ path, name string }
+	var files []file
+	for _, log := range logs {
+		files = append(files, file{path: filepath.Join(dir, log), name: log})
+	}
+	artifactsDir := filepath.Join(dir, DirName)
+	err := filepath.WalkDir(artifactsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == artifactsDir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(artifactsDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, file{path: path, name: filepath.ToSlash(name)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for _, f := range files {
+		digest, size, err := store.PutFile(f.path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return artifacts, err
+		}
+		a := Artifact{ExecUUID: execUUID, Name: f.name, Kind: kindOf(f.name), Digest: digest, Size: size, CreatedAt: time.Now()}
+		if err := insert(client, a); err != nil {
+			return artifacts, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
+
+func insert(client storage.SQLClient, a Artifact) error {
+	_, err := client.Write("INSERT INTO artifact(exec_uuid, name, kind, digest, size) VALUES(?, ?, ?, ?, ?) "+
+		storage.DialectOf(client).OnDuplicateKeyUpdate([]string{"exec_uuid", "name"}, "kind", "digest", "size")+", created_at = CURRENT_TIMESTAMP",
+		a.ExecUUID, a.Name, a.Kind, a.Digest, a.Size)
+	return err
+}
+
+// GetForExecution returns the artifacts of the given execution ordered by name.
+func GetForExecution(client storage.SQLClient, execUUID string) ([]Artifact, error) {
+	rows, err := client.Read("SELECT exec_uuid, name, kind, digest, size, created_at FROM artifact WHERE exec_uuid = ? ORDER BY name", execUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var artifacts []Artifact
+	for rows.Next() {
+		var a Artifact
+		if err := rows.Scan(&a.ExecUUID, &a.Name, &a.Kind, &a.Digest, &a.Size, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, rows.Err()
+}
+
+// Prune deletes the artifacts created before the given time from the database, and
+// removes from the store the blobs that are not used by any artifact anymore.
+// It returns the number of blobs that were removed. Collections wait for the end of the
+// pruning, and the pruning waits for the end of the collections in progress.
+func Prune(client storage.SQLClient, store *Store, before time.Time) (int, error) {
+	store.gc.Lock()
+	defer store.gc.Unlock()
+
+	rows, err := client.Read("SELECT DISTINCT digest FROM artifact WHERE created_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	var digests []string
+	for rows.Next() {
+		var digest string
+		if err := rows.Scan(&digest); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		digests = append(digests, digest)
+	}
+	rows.Close()
+
+	if _, err := client.Write("DELETE FROM artifact WHERE created_at < ?", before); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, digest := range digests {
+		used, err := isUsed(client, digest)
+		if err != nil {
+			return removed, err
+		}
+		if used {
+			continue
+		}
+		if err := store.Remove(digest); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func isUsed(client storage.SQLClient, digest string) (bool, error) {
+	rows, err := client.Read("SELECT 1 FROM artifact WHERE digest = ? LIMIT 1", digest)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
@@ -0,0 +1,177 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package artifact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+type fakeSQLClient struct {
+	writes [][]interface{}
+}
+
+func (f *fakeSQLClient) Write(_ string, args ...interface{}) (int64, error) {
+	f.writes = append(f.writes, args)
+	return int64(len(f.writes)), nil
+}
+
+func (f *fakeSQLClient) Read(string, ...interface{}) (storage.Rows, error) {
+	return nil, nil
+}
+
+func (f *fakeSQLClient) WriteContext(_ context.Context, query string, args ...interface{}) (int64, error) {
+	return f.Write(query, args...)
+}
+
+func (f *fakeSQLClient) ReadContext(_ context.Context, query string, args ...interface{}) (storage.Rows, error) {
+	return f.Read(query, args...)
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "exec-stdout.log", want: KindLog},
+		{name: "profiles/10.0.0.1/vtgate-1/cpu.pprof", want: KindProfile},
+		{name: "cpuprof_sqlparser.BenchmarkParse.out", want: KindProfile},
+		{name: "output.txt", want: KindFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt.Assert(t, kindOf(tt.name), qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	write := func(name, content string) {
+		file := filepath.Join(dir, name)
+		c.Assert(os.MkdirAll(filepath.Dir(file), 0755), qt.IsNil)
+		c.Assert(os.WriteFile(file, []byte(content), 0644), qt.IsNil)
+	}
+	write("exec-stdout.log", "PLAY [all]")
+	write(filepath.Join(DirName, "profiles", "vtgate", "cpu.pprof"), "profile")
+	write(filepath.Join(DirName, "output.txt"), "PLAY [all]")
+
+	client := &fakeSQLClient{}
+	store := NewStore(t.TempDir())
+	artifacts, err := Collect(client, store, "1234", dir, []string{"exec-stdout.log", "exec-stderr.log"})
+	c.Assert(err, qt.IsNil)
+
+	var names, kinds []string
+	for _, a := range artifacts {
+		names = append(names, a.Name)
+		kinds = append(kinds, a.Kind)
+		c.Assert(a.ExecUUID, qt.Equals, "1234")
+		f, err := store.Open(a.Digest)
+		c.Assert(err, qt.IsNil)
+		c.Assert(f.Close(), qt.IsNil)
+	}
+	c.Assert(names, qt.DeepEquals, []string{"exec-stdout.log", "output.txt", "profiles/vtgate/cpu.pprof"})
+	c.Assert(kinds, qt.DeepEquals, []string{KindLog, KindFile, KindProfile})
+	c.Assert(client.writes, qt.HasLen, 3)
+
+	// The log and the output have the same content, they share the same blob.
+	c.Assert(artifacts[0].Digest, qt.Equals, artifacts[1].Digest)
+
+	// An execution that did not collect any file only has its logs.
+	artifacts, err = Collect(client, store, "5678", t.TempDir(), []string{"exec-stdout.log"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(artifacts, qt.HasLen, 0)
+}
+
+func TestPrune(t *testing.T) {
+	c := qt.New(t)
+	client, err := (&sqlite.Config{File: filepath.Join(t.TempDir(), "arewefastyet.db")}).NewClient()
+	c.Assert(err, qt.IsNil)
+	defer client.Close()
+	store := NewStore(t.TempDir())
+
+	put := func(execUUID, name, content string) string {
+		digest, size, err := store.Put(strings.NewReader(content))
+		c.Assert(err, qt.IsNil)
+		c.Assert(insert(client, Artifact{ExecUUID: execUUID, Name: name, Kind: kindOf(name), Digest: digest, Size: size}), qt.IsNil)
+		return digest
+	}
+	old := put("1234", "exec-stdout.log", "old")
+	shared := put("1234", "output.txt", "shared")
+	put("5678", "output.txt", "shared")
+	_, err = client.Write("UPDATE artifact SET created_at = ? WHERE exec_uuid = ?", time.Now().Add(-48*time.Hour), "1234")
+	c.Assert(err, qt.IsNil)
+
+	// Collecting an artifact again replaces the previous one.
+	put("5678", "output.txt", "shared")
+	artifacts, err := GetForExecution(client, "5678")
+	c.Assert(err, qt.IsNil)
+	c.Assert(artifacts, qt.HasLen, 1)
+
+	removed, err := Prune(client, store, time.Now().Add(-24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Assert(removed, qt.Equals, 1)
+	_, err = store.Open(old)
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+	f, err := store.Open(shared)
+	c.Assert(err, qt.IsNil)
+	c.Assert(f.Close(), qt.IsNil)
+
+	artifacts, err = GetForExecution(client, "1234")
+	c.Assert(err, qt.IsNil)
+	c.Assert(artifacts, qt.HasLen, 0)
+}
+
+func TestPruneWaitsForCollect(t *testing.T) {
+	c := qt.New(t)
+	client, err := (&sqlite.Config{File: filepath.Join(t.TempDir(), "arewefastyet.db")}).NewClient()
+	c.Assert(err, qt.IsNil)
+	defer client.Close()
+	store := NewStore(t.TempDir())
+
+	// A collection is in progress, the blobs it found in the store must not be removed.
+	store.gc.RLock()
+	pruned := make(chan struct{})
+	go func() {
+		_, err := Prune(client, store, time.Now())
+		c.Check(err, qt.IsNil)
+		close(pruned)
+	}()
+	select {
+	case <-pruned:
+		c.Fatal("the artifacts were pruned during a collection")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	store.gc.RUnlock()
+	select {
+	case <-pruned:
+	case <-time.After(5 * time.Second):
+		c.Fatal("the artifacts were not pruned after the collection ended")
+	}
+}
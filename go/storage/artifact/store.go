@@ -0,0 +1,141 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package artifact stores the files produced by the executions, such as their logs and
+// profiles, in a content-addressed store on the local filesystem. The artifacts of each
+// execution are recorded in the artifact table of the SQL database.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidDigest is returned when a digest is not a hex encoded SHA-256 sum.
+var ErrInvalidDigest = errors.New("invalid artifact digest")
+
+// Store is a content-addressed store, each blob is saved in a file named after the
+// SHA-256 sum of its content. Identical files are only stored once.
+type Store struct {
+	root string
+
+	// gc is held for reading while artifacts are collected and for writing while they are
+	// pruned. Otherwise a blob could be removed after a collection found it already stored,
+	// but before the collection recorded that it uses it.
+	gc sync.RWMutex
+}
+
+// NewStore returns a Store saving its blobs in the given directory.
+func NewStore(root string) *Store {
+	return &Store{root: root}
+}
+
+// Put saves the content of the given reader in the store and returns its digest and size.
+func (s *Store) Put(r io.Reader) (digest string, size int64, err error) {
+	tmpDir := filepath.Join(s.root, "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", 0, err
+	}
+	tmp, err := os.CreateTemp(tmpDir, "blob-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err = io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, err
+	}
+
+	digest = hex.EncodeToString(hash.Sum(nil))
+	blobPath, _ := s.path(digest)
+	if _, err := os.Stat(blobPath); err == nil {
+		return digest, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), blobPath); err != nil {
+		return "", 0, err
+	}
+	return digest, size, nil
+}
+
+// PutFile saves the content of the given file in the store.
+func (s *Store) PutFile(file string) (digest string, size int64, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	return s.Put(f)
+}
+
+// Open opens the blob with the given digest.
+func (s *Store) Open(digest string) (*os.File, error) {
+	blobPath, err := s.path(digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(blobPath)
+}
+
+// Remove deletes the blob with the given digest, removing a missing blob is not an error.
+func (s *Store) Remove(digest string) error {
+	blobPath, err := s.path(digest)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the path of the blob with the given digest. Blobs are spread in
+// sub-directories named after the first two characters of their digest.
+func (s *Store) path(digest string) (string, error) {
+	if !isDigest(digest) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDigest, digest)
+	}
+	return filepath.Join(s.root, digest[:2], digest), nil
+}
+
+func isDigest(digest string) bool {
+	if len(digest) != sha256.Size*2 || strings.ToLower(digest) != digest {
+		return false
+	}
+	_, err := hex.DecodeString(digest)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package artifact
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestStore(t *testing.T) {
+	c := qt.New(t)
+	root := t.TempDir()
+	store := NewStore(root)
+
+	digest, size, err := store.Put(strings.NewReader("hello"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(digest, qt.Equals, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+	c.Assert(size, qt.Equals, int64(5))
+	_, err = os.Stat(filepath.Join(root, "2c", digest))
+	c.Assert(err, qt.IsNil)
+
+	// Storing the same content twice keeps a single blob.
+	file := filepath.Join(t.TempDir(), "hello.txt")
+	c.Assert(os.WriteFile(file, []byte("hello"), 0644), qt.IsNil)
+	sameDigest, _, err := store.PutFile(file)
+	c.Assert(err, qt.IsNil)
+	c.Assert(sameDigest, qt.Equals, digest)
+	entries, err := os.ReadDir(filepath.Join(root, "2c"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+
+	f, err := store.Open(digest)
+	c.Assert(err, qt.IsNil)
+	content, err := io.ReadAll(f)
+	c.Assert(f.Close(), qt.IsNil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(content), qt.Equals, "hello")
+
+	c.Assert(store.Remove(digest), qt.IsNil)
+	c.Assert(store.Remove(digest), qt.IsNil)
+	_, err = store.Open(digest)
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	for _, digest := range []string{"", "../../etc/passwd", strings.Repeat("z", 64), strings.ToUpper(digest)} {
+		_, err = store.Open(digest)
+		c.Assert(err, qt.ErrorIs, ErrInvalidDigest)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// WithContext returns a SQLClient whose Write and Read use the given context, for example
+// the context of an HTTP request, so the functions taking a SQLClient give up when it is done.
+// The transactions started by WithTransaction with the returned client also use the context.
+func WithContext(ctx context.Context, client SQLClient) SQLClient {
+	if client == nil {
+		return nil
+	}
+	return &contextClient{ctx: ctx, client: client}
+}
+
+type contextClient struct {
+	ctx    context.Context
+	client SQLClient
+}
+
+func (c *contextClient) Write(query string, args ...interface{}) (int64, error) {
+	return c.client.WriteContext(c.ctx, query, args...)
+}
+
+func (c *contextClient) Read(query string, args ...interface{}) (Rows, error) {
+	return c.client.ReadContext(c.ctx, query, args...)
+}
+
+func (c *contextClient) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return c.client.WriteContext(ctx, query, args...)
+}
+
+func (c *contextClient) ReadContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	return c.client.ReadContext(ctx, query, args...)
+}
+
+func (c *contextClient) Dialect() Dialect {
+	return DialectOf(c.client)
+}
+
+// RetryPolicy defines how the queries failing with a transient error are retried. The
+// delay before a retry starts at InitialBackoff and doubles after every attempt, up to
+// MaxBackoff.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Do calls fn until it succeeds, it fails with an error for which isTransient returns
+// false, the retries are exhausted, or the context is done. It returns the last error of fn.
+func (p RetryPolicy) Do(ctx context.Context, isTransient func(error) bool, fn func() error) error {
+	backoff := p.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxRetries || !isTransient(err) {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return multierr.Append(err, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestWithContext(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(WithContext(context.Background(), nil), qt.IsNil)
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	client := &fakeClient{}
+	bound := WithContext(ctx, client)
+
+	_, err := bound.Write("INSERT INTO a VALUES(1)")
+	c.Assert(err, qt.IsNil)
+	_, err = bound.Read("SELECT 1")
+	c.Assert(err, qt.IsNil)
+	c.Assert(client.contexts, qt.HasLen, 2)
+	for _, got := range client.contexts {
+		c.Assert(got, qt.Equals, ctx)
+	}
+	c.Assert(DialectOf(WithContext(ctx, sqliteClient{})), qt.Equals, DialectSQLite)
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+	isTransient := func(err error) bool { return errors.Is(err, errTransient) }
+	policy := RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		errors    []error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "Success", errors: []error{nil}, wantCalls: 1},
+		{name: "Transient then success", errors: []error{errTransient, errTransient, nil}, wantCalls: 3},
+		{name: "Permanent", errors: []error{errPermanent}, wantCalls: 1, wantErr: errPermanent},
+		{name: "Retries exhausted", errors: []error{errTransient, errTransient, errTransient, errTransient, nil}, wantCalls: 4, wantErr: errTransient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			calls := 0
+			err := policy.Do(context.Background(), isTransient, func() error {
+				calls++
+				return tt.errors[calls-1]
+			})
+			c.Assert(calls, qt.Equals, tt.wantCalls)
+			if tt.wantErr == nil {
+				c.Assert(err, qt.IsNil)
+			} else {
+				c.Assert(err, qt.ErrorIs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DoCanceled(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	policy := RetryPolicy{MaxRetries: 3, InitialBackoff: time.Hour}
+	err := policy.Do(ctx, func(error) bool { return true }, func() error {
+		calls++
+		return errors.New("transient")
+	})
+	c.Assert(calls, qt.Equals, 1)
+	c.Assert(err, qt.ErrorIs, context.Canceled)
+	c.Assert(err, qt.ErrorMatches, "transient; context canceled")
+}
@@ -0,0 +1,137 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package dataset exports executions with all their results to a self-describing file,
+// and imports such files into another database. It is used to move benchmark data between
+// environments, or to hand a dataset to someone for offline analysis.
+//
+// A dataset is a gzip compressed file of JSON lines. The first line is a Header describing
+// the dataset and the columns of each table, and each following line is an Execution.
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+const (
+	// Format identifies the dataset files.
+	Format = "arewefastyet-dataset"
+
+	// FormatVersion is the version of the format of the files, it changes when
+	// the files written by Export cannot be read by older versions of Import.
+	FormatVersion = 1
+
+	// timeFormat is the format of the datetime values, both MySQL and SQLite accept it.
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// Filter selects the executions to export. The empty fields do not filter anything.
+type Filter struct {
+	GitRef   string `json:"git_ref,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Workload string `json:"workload,omitempty"`
+
+	// From and To select the executions started in [From, To).
+	From time.Time `json:"from,omitzero"`
+	To   time.Time `json:"to,omitzero"`
+}
+
+// Header is the first line of a dataset.
+type Header struct {
+	Format        string              `json:"format"`
+	Version       int                 `json:"version"`
+	SchemaVersion int                 `json:"schema_version"`
+	ExportedAt    time.Time           `json:"exported_at"`
+	Filter        Filter              `json:"filter"`
+	Tables        map[string][]string `json:"tables"`
+}
+
+// Row is a row of a table, mapping its columns to their values.
+type Row map[string]interface{}
+
+// Execution is an execution and the rows of all the tables referencing it, the rows
+// of each table are indexed by the name of the table, including the execution table.
+type Execution struct {
+	UUID   string           `json:"uuid"`
+	Tables map[string][]Row `json:"tables"`
+}
+
+// Summary describes the executions exported or imported.
+type Summary struct {
+	// Executions is the number of executions exported or imported.
+	Executions int
+
+	// Skipped is the number of executions that were not imported because
+	// an execution with the same UUID already exists.
+	Skipped int
+
+	// Rows is the number of rows exported or imported per table.
+	Rows map[string]int64
+}
+
+func newSummary() Summary {
+	return Summary{Rows: map[string]int64{}}
+}
+
+// tableColumns returns the columns of each table, as written in the Header.
+func tableColumns() map[string][]string {
+	columns := make(map[string][]string, len(storage.ExecutionTables))
+	for _, t := range storage.ExecutionTables {
+		columns[t.Name] = t.Columns
+	}
+	return columns
+}
+
+// isReferenced returns true if the keys of the given table are referenced by another table.
+func isReferenced(name string) bool {
+	for _, t := range storage.ExecutionTables {
+		for _, ref := range t.Refs {
+			if ref == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// quote quotes the given column names, some of them such as commit and key are reserved words.
+func quote(columns []string) []string {
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, "`"+column+"`")
+	}
+	return quoted
+}
+
+// toInt64 converts a key read from a dataset to an int64.
+func toInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Int64()
+	case int64:
+		return v, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("invalid key %v", v)
+}
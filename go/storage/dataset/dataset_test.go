@@ -0,0 +1,222 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func newTestDatabase(c *qt.C) *sqlite.Client {
+	cfg := sqlite.Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}
+	client, err := cfg.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// insertExecution inserts an execution with a macrobenchmark, its results and query plan,
+// a metric and a microbenchmark with its details.
+func insertExecution(c *qt.C, client storage.SQLClient, uuid, source, gitRef string, startedAt time.Time) {
+	_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, workload, started_at, finished_at) VALUES(?, 'finished', ?, ?, 'OLTP', ?, ?)",
+		uuid, source, gitRef, startedAt, startedAt.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	macroID, err := client.Write("INSERT INTO macrobenchmark(`commit`, exec_uuid, workload, vtgate_planner_version) VALUES(?, ?, 'OLTP', 'Gen4')", gitRef, uuid)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO macrobenchmark_results(macrobenchmark_id, tps, latency, queries) VALUES(?, 1250.5, 3.25, 1000)", macroID)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO query_plans(exec_uuid, macrobenchmark_id, `key`, plan, exec_count, `rows`) VALUES(?, ?, 'select 1', '{\"OperatorType\": \"Route\"}', 10, 1)", uuid, macroID)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO metrics(exec_uuid, name, value) VALUES(?, 'vtgate.cpu', 1.5)", uuid)
+	c.Assert(err, qt.IsNil)
+	microNo, err := client.Write("INSERT INTO microbenchmark(pkg_name, name, git_ref, exec_uuid) VALUES('vitess.io/vitess/go/vt/sqlparser', 'BenchmarkParse', ?, ?)", gitRef, uuid)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO microbenchmark_details(microbenchmark_no, name, bench_type, n, ns_per_op) VALUES(?, 'BenchmarkParse', 'regular', 1000, 1234.5)", microNo)
+	c.Assert(err, qt.IsNil)
+}
+
+// executionResults returns the results of an execution joined through the keys that are remapped on import.
+func executionResults(c *qt.C, client storage.SQLClient, uuid string) []interface{} {
+	rows, err := client.Read(`SELECT e.source, e.git_ref, e.started_at, m.vtgate_planner_version, r.tps, r.latency, q.plan, q.exec_count, mt.value, mb.pkg_name, md.ns_per_op
+		FROM execution e
+		JOIN macrobenchmark m ON m.exec_uuid = e.uuid
+		JOIN macrobenchmark_results r ON r.macrobenchmark_id = m.macrobenchmark_id
+		JOIN query_plans q ON q.macrobenchmark_id = m.macrobenchmark_id
+		JOIN metrics mt ON mt.exec_uuid = e.uuid
+		JOIN microbenchmark mb ON mb.exec_uuid = e.uuid
+		JOIN microbenchmark_details md ON md.microbenchmark_no = mb.microbenchmark_no
+		WHERE e.uuid = ?`, uuid)
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	c.Assert(rows.Next(), qt.IsTrue, qt.Commentf("execution %s", uuid))
+
+	var (
+		source, gitRef, plannerVersion, plan, pkgName string
+		startedAt                                     time.Time
+		tps, latency, value, nsPerOp                  float64
+		execCount                                     int64
+	)
+	c.Assert(rows.Scan(&source, &gitRef, &startedAt, &plannerVersion, &tps, &latency, &plan, &execCount, &value, &pkgName, &nsPerOp), qt.IsNil)
+	c.Assert(rows.Next(), qt.IsFalse)
+	return []interface{}{source, gitRef, startedAt.UTC(), plannerVersion, tps, latency, plan, execCount, value, pkgName, nsPerOp}
+}
+
+func TestExportImport(t *testing.T) {
+	c := qt.New(t)
+	src := newTestDatabase(c)
+	dst := newTestDatabase(c)
+
+	startedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	insertExecution(c, src, "pr-1", "cron_pr", "abc", startedAt)
+	insertExecution(c, src, "pr-2", "cron_pr", "def", startedAt.Add(48*time.Hour))
+	insertExecution(c, src, "cron-1", "cron", "abc", startedAt)
+
+	// The rows of the destination database shift its keys, and one of the executions already exists.
+	insertExecution(c, dst, "other", "cron", "xyz", startedAt)
+	insertExecution(c, dst, "pr-2", "cron_pr", "def", startedAt.Add(48*time.Hour))
+
+	var buf bytes.Buffer
+	summary, err := Export(src, &buf, Filter{Source: "cron_pr", From: startedAt, To: startedAt.Add(72 * time.Hour)})
+	c.Assert(err, qt.IsNil)
+	c.Assert(summary.Executions, qt.Equals, 2)
+	c.Assert(summary.Rows["query_plans"], qt.Equals, int64(2))
+	c.Assert(summary.Rows["microbenchmark_details"], qt.Equals, int64(2))
+
+	exported := buf.Bytes()
+	summary, err = Import(dst, bytes.NewReader(exported))
+	c.Assert(err, qt.IsNil)
+	c.Assert(summary, qt.DeepEquals, Summary{
+		Executions: 1,
+		Skipped:    1,
+		Rows: map[string]int64{
+			"execution":              1,
+			"macrobenchmark":         1,
+			"macrobenchmark_results": 1,
+			"query_plans":            1,
+			"metrics":                1,
+			"microbenchmark":         1,
+			"microbenchmark_details": 1,
+		},
+	})
+	c.Assert(executionResults(c, dst, "pr-1"), qt.DeepEquals, executionResults(c, src, "pr-1"))
+	c.Assert(executionResults(c, dst, "pr-2"), qt.DeepEquals, executionResults(c, src, "pr-2"))
+
+	// Importing the same dataset again does not duplicate anything.
+	summary, err = Import(dst, bytes.NewReader(exported))
+	c.Assert(err, qt.IsNil)
+	c.Assert(summary.Executions, qt.Equals, 0)
+	c.Assert(summary.Skipped, qt.Equals, 2)
+	executionResults(c, dst, "pr-1")
+}
+
+func TestExportHeader(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+	insertExecution(c, client, "cron-1", "cron", "abc", time.Now())
+
+	var buf bytes.Buffer
+	_, err := Export(client, &buf, Filter{GitRef: "abc"})
+	c.Assert(err, qt.IsNil)
+
+	gz, err := gzip.NewReader(&buf)
+	c.Assert(err, qt.IsNil)
+	dec := json.NewDecoder(gz)
+	var header map[string]interface{}
+	c.Assert(dec.Decode(&header), qt.IsNil)
+	c.Assert(header["format"], qt.Equals, Format)
+	c.Assert(header["version"], qt.Equals, float64(FormatVersion))
+	c.Assert(header["schema_version"], qt.Equals, float64(11))
+	c.Assert(header["filter"], qt.DeepEquals, map[string]interface{}{"git_ref": "abc"})
+	c.Assert(header["tables"], qt.HasLen, len(storage.ExecutionTables))
+
+	var e map[string]interface{}
+	c.Assert(dec.Decode(&e), qt.IsNil)
+	c.Assert(e["uuid"], qt.Equals, "cron-1")
+	execution := e["tables"].(map[string]interface{})["execution"].([]interface{})[0].(map[string]interface{})
+	c.Assert(execution["source"], qt.Equals, "cron")
+	c.Assert(execution["profile_binary"], qt.IsNil)
+}
+
+func TestImportInvalid(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	write := func(lines ...interface{}) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		enc := json.NewEncoder(gz)
+		for _, line := range lines {
+			c.Assert(enc.Encode(line), qt.IsNil)
+		}
+		c.Assert(gz.Close(), qt.IsNil)
+		return &buf
+	}
+	header := Header{Format: Format, Version: FormatVersion, SchemaVersion: 10, Tables: tableColumns()}
+
+	_, err := Import(client, bytes.NewBufferString("not a dataset"))
+	c.Assert(err, qt.ErrorMatches, "invalid dataset: .*")
+
+	_, err = Import(client, write(Header{Format: Format, Version: FormatVersion + 1}))
+	c.Assert(err, qt.ErrorMatches, "unsupported dataset version 2, expected version 1")
+
+	unknown := header
+	unknown.Tables = map[string][]string{"execution": {"uuid", "new_column"}}
+	unknown.SchemaVersion = 11
+	_, err = Import(client, write(unknown))
+	c.Assert(err, qt.ErrorMatches, "the dataset has the unknown column execution.new_column, it was exported from a database at schema version 11")
+
+	// A row referencing a key missing from the dataset rolls back the whole execution.
+	_, err = Import(client, write(header, Execution{UUID: "1", Tables: map[string][]Row{
+		"execution":              {{"uuid": "1", "source": "cron"}},
+		"macrobenchmark_results": {{"id": 1, "macrobenchmark_id": 42, "tps": 10}},
+	}}))
+	c.Assert(err, qt.ErrorMatches, "could not import execution 1: macrobenchmark_results.macrobenchmark_id references the row 42 of macrobenchmark which is not part of the execution")
+	exists, err := executionExists(client, "1")
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.IsFalse)
+}
+
+func TestExportedColumns(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	// The datasets hold every column of the tables of the executions, a column added by a
+	// migration must be added to storage.ExecutionTables too.
+	for _, table := range storage.ExecutionTables {
+		rows, err := client.Read("SELECT name FROM pragma_table_info(?)", table.Name)
+		c.Assert(err, qt.IsNil)
+		var columns []string
+		for rows.Next() {
+			var column string
+			c.Assert(rows.Scan(&column), qt.IsNil)
+			columns = append(columns, column)
+		}
+		c.Assert(rows.Err(), qt.IsNil)
+		rows.Close()
+		c.Assert(table.Columns, qt.ContentEquals, columns, qt.Commentf("table %s", table.Name))
+	}
+}
@@ -0,0 +1,191 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package dataset
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+	"go.uber.org/multierr"
+)
+
+// Export writes the executions selected by the filter, with the rows of all
+// their tables, to w as a dataset.
+func Export(client storage.SQLClient, w io.Writer, filter Filter) (summary Summary, err error) {
+	summary = newSummary()
+	schemaVersion, err := getSchemaVersion(client)
+	if err != nil {
+		return summary, err
+	}
+	uuids, err := selectExecutions(client, filter)
+	if err != nil {
+		return summary, err
+	}
+
+	gz := gzip.NewWriter(w)
+	defer func() {
+		err = multierr.Append(err, gz.Close())
+	}()
+	enc := json.NewEncoder(gz)
+
+	err = enc.Encode(Header{
+		Format:        Format,
+		Version:       FormatVersion,
+		SchemaVersion: schemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Filter:        filter,
+		Tables:        tableColumns(),
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	for _, uuid := range uuids {
+		e := Execution{UUID: uuid, Tables: map[string][]Row{}}
+		for _, t := range storage.ExecutionTables {
+			rows, err := readRows(client, t, uuid)
+			if err != nil {
+				return summary, fmt.Errorf("could not read the rows of %s for execution %s: %w", t.Name, uuid, err)
+			}
+			if len(rows) > 0 {
+				e.Tables[t.Name] = rows
+				summary.Rows[t.Name] += int64(len(rows))
+			}
+		}
+		if err := enc.Encode(e); err != nil {
+			return summary, err
+		}
+		summary.Executions++
+	}
+	return summary, nil
+}
+
+// getSchemaVersion returns the version of the schema of the database, recorded by the migrations.
+func getSchemaVersion(client storage.SQLClient) (int, error) {
+	rows, err := client.Read("SELECT IFNULL(MAX(version), 0) FROM schema_version")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var version int
+	if rows.Next() {
+		if err := rows.Scan(&version); err != nil {
+			return 0, err
+		}
+	}
+	return version, rows.Err()
+}
+
+// selectExecutions returns the UUIDs of the executions selected by the filter, in the order they started.
+func selectExecutions(client storage.SQLClient, filter Filter) ([]string, error) {
+	var conditions []string
+	var args []interface{}
+	for column, value := range map[string]string{"git_ref": filter.GitRef, "source": filter.Source, "workload": filter.Workload} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "started_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "started_at < ?")
+		args = append(args, filter.To)
+	}
+	query := "SELECT uuid FROM execution"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at, uuid"
+
+	rows, err := client.Read(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		if err := rows.Scan(&uuid); err != nil {
+			return nil, err
+		}
+		uuids = append(uuids, uuid)
+	}
+	return uuids, rows.Err()
+}
+
+// readRows returns the rows of the given table belonging to an execution.
+func readRows(client storage.SQLClient, t storage.ExecutionTable, execUUID string) ([]Row, error) {
+	rows, err := client.Read("SELECT "+strings.Join(quote(t.Columns), ", ")+" FROM "+t.Name+" WHERE "+t.Where, execUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(t.Columns))
+	pointers := make([]interface{}, len(t.Columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var res []Row
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(Row, len(t.Columns))
+		for i, column := range t.Columns {
+			row[column] = exportValue(values[i], types[i])
+		}
+		res = append(res, row)
+	}
+	return res, rows.Err()
+}
+
+// exportValue converts a value read from the database to its JSON representation.
+// The times are written in UTC, and the decimals returned as text by MySQL as numbers.
+func exportValue(v interface{}, columnType *sql.ColumnType) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(timeFormat)
+	case []byte:
+		if columnType.DatabaseTypeName() == "DECIMAL" {
+			if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+				return f
+			}
+		}
+		return string(v)
+	}
+	return v
+}
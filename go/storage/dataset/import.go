@@ -0,0 +1,204 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package dataset
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+// Import reads a dataset from r and inserts its executions in the database. The executions
+// whose UUID already exists in the database are skipped. The auto-increment keys of the rows
+// are replaced by new ones, and the columns referencing them are updated accordingly. Each
+// execution is imported in a single transaction.
+func Import(client storage.SQLClient, r io.Reader) (Summary, error) {
+	summary := newSummary()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return summary, fmt.Errorf("invalid dataset: %w", err)
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+	dec.UseNumber()
+
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return summary, fmt.Errorf("invalid dataset header: %w", err)
+	}
+	columns, err := importedColumns(header)
+	if err != nil {
+		return summary, err
+	}
+
+	for {
+		var e Execution
+		err := dec.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			return summary, nil
+		}
+		if err != nil {
+			return summary, fmt.Errorf("invalid dataset execution: %w", err)
+		}
+
+		exists, err := executionExists(client, e.UUID)
+		if err != nil {
+			return summary, err
+		}
+		if exists {
+			summary.Skipped++
+			continue
+		}
+		err = storage.WithTransaction(client, func(tx storage.SQLClient) error {
+			return importExecution(tx, e, columns)
+		})
+		if err != nil {
+			return summary, fmt.Errorf("could not import execution %s: %w", e.UUID, err)
+		}
+		summary.Executions++
+		for name, rows := range e.Tables {
+			summary.Rows[name] += int64(len(rows))
+		}
+	}
+}
+
+// importedColumns checks that the dataset can be imported, and returns the columns of each
+// table that are inserted, which are the columns of the dataset without the auto-increment keys.
+func importedColumns(header Header) (map[string][]string, error) {
+	if header.Format != Format {
+		return nil, fmt.Errorf("invalid dataset: unknown format %q", header.Format)
+	}
+	if header.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported dataset version %d, expected version %d", header.Version, FormatVersion)
+	}
+
+	known := tableColumns()
+	res := map[string][]string{}
+	for name := range header.Tables {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("the dataset has rows of the unknown table %s", name)
+		}
+	}
+	for _, t := range storage.ExecutionTables {
+		for _, column := range header.Tables[t.Name] {
+			if !contains(t.Columns, column) {
+				return nil, fmt.Errorf("the dataset has the unknown column %s.%s, it was exported from a database at schema version %d", t.Name, column, header.SchemaVersion)
+			}
+			if column != t.Key {
+				res[t.Name] = append(res[t.Name], column)
+			}
+		}
+	}
+	return res, nil
+}
+
+func executionExists(client storage.SQLClient, uuid string) (bool, error) {
+	rows, err := client.Read("SELECT 1 FROM execution WHERE uuid = ? LIMIT 1", uuid)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+func importExecution(tx storage.SQLClient, e Execution, columns map[string][]string) error {
+	if len(e.Tables["execution"]) != 1 {
+		return fmt.Errorf("expected one execution row, got %d", len(e.Tables["execution"]))
+	}
+
+	// keys maps the keys of the rows of the dataset to the keys of the inserted rows, per table.
+	keys := map[string]map[int64]int64{}
+	for _, t := range storage.ExecutionTables {
+		var values [][]interface{}
+		for _, row := range e.Tables[t.Name] {
+			rowValues := make([]interface{}, 0, len(columns[t.Name]))
+			for _, column := range columns[t.Name] {
+				v := importValue(row[column])
+				if ref, ok := t.Refs[column]; ok && v != nil {
+					key, err := toInt64(v)
+					if err != nil {
+						return fmt.Errorf("%s.%s: %w", t.Name, column, err)
+					}
+					newKey, ok := keys[ref][key]
+					if !ok {
+						return fmt.Errorf("%s.%s references the row %d of %s which is not part of the execution", t.Name, column, key, ref)
+					}
+					v = newKey
+				}
+				rowValues = append(rowValues, v)
+			}
+			values = append(values, rowValues)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		// The tables whose keys are not referenced are inserted in bulk, the
+		// other ones row by row to map the key of each row to its new key.
+		if !isReferenced(t.Name) {
+			if err := storage.BulkInsert(tx, t.Name, columns[t.Name], values); err != nil {
+				return err
+			}
+			continue
+		}
+		keys[t.Name] = map[int64]int64{}
+		query := "INSERT INTO " + t.Name + "(" + strings.Join(quote(columns[t.Name]), ", ") + ") VALUES(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns[t.Name])), ", ") + ")"
+		for i, row := range e.Tables[t.Name] {
+			newKey, err := tx.Write(query, values[i]...)
+			if err != nil {
+				return err
+			}
+			key, err := toInt64(row[t.Key])
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name, t.Key, err)
+			}
+			keys[t.Name][key] = newKey
+		}
+	}
+	return nil
+}
+
+// importValue converts a value decoded from a dataset to a query argument.
+func importValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is the flavor of SQL understood by a database. The queries that rely on functions
+// that only exist in MySQL use the helpers of Dialect to be portable.
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite"
+)
+
+// DialectOf returns the dialect of the given client, the clients that do not
+// implement Database are considered to be MySQL clients.
+func DialectOf(client SQLClient) Dialect {
+	if db, ok := client.(interface{ Dialect() Dialect }); ok {
+		return db.Dialect()
+	}
+	return DialectMySQL
+}
+
+// DaysAgo returns an expression evaluating to the date the given number of days
+// before the current date. A negative number of days gives a date in the future.
+func (d Dialect) DaysAgo(days int) string {
+	if d == DialectSQLite {
+		return fmt.Sprintf("DATE('now', '%+d days')", -days)
+	}
+	return fmt.Sprintf("DATE_SUB(CURDATE(), INTERVAL %d DAY)", days)
+}
+
+// SecondsAgo returns an expression evaluating to the current time minus a number of
+// seconds given as the next argument of the query.
+func (d Dialect) SecondsAgo() string {
+	if d == DialectSQLite {
+		return "DATETIME('now', '-' || ? || ' seconds')"
+	}
+	return "DATE_SUB(NOW(), INTERVAL ? SECOND)"
+}
+
+// TimestampDiff returns an expression evaluating to the whole number of minutes or seconds,
+// depending on the unit, between the from and to datetime expressions.
+func (d Dialect) TimestampDiff(unit, from, to string) string {
+	if d != DialectSQLite {
+		return fmt.Sprintf("TIMESTAMPDIFF(%s, %s, %s)", unit, from, to)
+	}
+	seconds := fmt.Sprintf("(STRFTIME('%%s', %s) - STRFTIME('%%s', %s))", to, from)
+	if strings.EqualFold(unit, "MINUTE") {
+		return "(" + seconds + " / 60)"
+	}
+	return seconds
+}
+
+// FormatDay returns an expression formatting the given datetime expression as YYYYMMDD.
+func (d Dialect) FormatDay(expr string) string {
+	if d == DialectSQLite {
+		return fmt.Sprintf("STRFTIME('%%Y%%m%%d', %s)", expr)
+	}
+	return fmt.Sprintf("DATE_FORMAT(%s, '%%Y%%m%%d')", expr)
+}
+
+// InsertIgnore returns the statement inserting rows while silently skipping
+// the ones that would violate a unique key.
+func (d Dialect) InsertIgnore() string {
+	if d == DialectSQLite {
+		return "INSERT OR IGNORE"
+	}
+	return "INSERT IGNORE"
+}
+
+// OnDuplicateKeyUpdate returns the clause of an insert statement that updates the given
+// columns with the inserted values when the row already exists. The key lists the columns
+// of the unique key of the table, SQLite needs it to detect the conflict.
+func (d Dialect) OnDuplicateKeyUpdate(key []string, columns ...string) string {
+	updates := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if d == DialectSQLite {
+			updates = append(updates, column+" = excluded."+column)
+		} else {
+			updates = append(updates, column+" = VALUES("+column+")")
+		}
+	}
+	if d == DialectSQLite {
+		return "ON CONFLICT(" + strings.Join(key, ", ") + ") DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+}
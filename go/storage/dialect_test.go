@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDialect(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "MySQL days ago", got: DialectMySQL.DaysAgo(30), want: "DATE_SUB(CURDATE(), INTERVAL 30 DAY)"},
+		{name: "SQLite days ago", got: DialectSQLite.DaysAgo(30), want: "DATE('now', '-30 days')"},
+		{name: "SQLite days in the future", got: DialectSQLite.DaysAgo(-1), want: "DATE('now', '+1 days')"},
+		{name: "MySQL minutes", got: DialectMySQL.TimestampDiff("MINUTE", "a", "b"), want: "TIMESTAMPDIFF(MINUTE, a, b)"},
+		{name: "SQLite minutes", got: DialectSQLite.TimestampDiff("MINUTE", "a", "b"), want: "((STRFTIME('%s', b) - STRFTIME('%s', a)) / 60)"},
+		{name: "SQLite seconds", got: DialectSQLite.TimestampDiff("SECOND", "a", "b"), want: "(STRFTIME('%s', b) - STRFTIME('%s', a))"},
+		{name: "MySQL format day", got: DialectMySQL.FormatDay("a"), want: "DATE_FORMAT(a, '%Y%m%d')"},
+		{name: "SQLite format day", got: DialectSQLite.FormatDay("a"), want: "STRFTIME('%Y%m%d', a)"},
+		{name: "MySQL upsert", got: DialectMySQL.OnDuplicateKeyUpdate([]string{"id"}, "a", "b"), want: "ON DUPLICATE KEY UPDATE a = VALUES(a), b = VALUES(b)"},
+		{name: "SQLite upsert", got: DialectSQLite.OnDuplicateKeyUpdate([]string{"x", "y"}, "a"), want: "ON CONFLICT(x, y) DO UPDATE SET a = excluded.a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt.Assert(t, tt.got, qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestDialectOf(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(DialectOf(nil), qt.Equals, DialectMySQL)
+	c.Assert(DialectOf(sqliteClient{}), qt.Equals, DialectSQLite)
+}
+
+type sqliteClient struct{ SQLClient }
+
+func (sqliteClient) Dialect() Dialect { return DialectSQLite }
@@ -53,3 +53,20 @@ func (c *Client) Select(query string) ([]map[string]interface{}, error) {
 	}
 	return result, nil
 }
+
+// Ping checks that the InfluxDB server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	ok, err := c.influx.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("InfluxDB server at %s:%s is not reachable", c.Config.Host, c.Config.Port)
+	}
+	return nil
+}
+
+// Close releases the resources used by the client.
+func (c *Client) Close() {
+	c.influx.Close()
+}
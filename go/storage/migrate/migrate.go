@@ -0,0 +1,314 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package migrate applies the numbered migrations of the schema of the arewefastyet
+// database. The migrations are embedded in the binary, the version of the schema of
+// a database is recorded in its schema_version table.
+package migrate
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+var (
+	// ErrSchemaMismatch is returned by Check when the schema of the database is not the
+	// one of the latest migration.
+	ErrSchemaMismatch = errors.New("the schema of the database does not match the expected version")
+
+	fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+const createVersionTable = "CREATE TABLE IF NOT EXISTS schema_version (" +
+	"version int NOT NULL PRIMARY KEY, " +
+	"name varchar(255) NOT NULL, " +
+	"applied_at datetime DEFAULT CURRENT_TIMESTAMP)"
+
+// Migration is a change of the schema of the database. Up applies it and Down reverts it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	client     storage.SQLClient
+	migrations []Migration
+}
+
+// New returns a Migrator applying the migrations embedded in the binary.
+func New(client storage.SQLClient) (*Migrator, error) {
+	sub, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(client, sub)
+}
+
+func newMigrator(client storage.SQLClient, fsys fs.FS) (*Migrator, error) {
+	list, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{client: client, migrations: list}, nil
+}
+
+// load reads the migrations of the given directory. Their files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql, the versions start
+// at 1 and must not have any gap.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileNameRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	for i, m := range list {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			return nil, fmt.Errorf("migration %d (%s) must have both an up and a down file", m.Version, m.Name)
+		}
+	}
+	return list, nil
+}
+
+// Migrations returns the known migrations ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Latest returns the version of the latest migration, which is the version of
+// the schema expected by this binary.
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the version of the schema of the database, zero if no migration
+// has been applied yet or if the database has no schema_version table. It does not
+// modify the database.
+func (m *Migrator) Version() (int, error) {
+	exists, err := m.hasVersionTable()
+	if err != nil || !exists {
+		return 0, err
+	}
+	rows, err := m.client.Read("SELECT IFNULL(MAX(version), 0) FROM schema_version")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var version int
+	if rows.Next() {
+		if err := rows.Scan(&version); err != nil {
+			return 0, err
+		}
+	}
+	return version, rows.Err()
+}
+
+// hasVersionTable returns true if the schema_version table exists in the database.
+func (m *Migrator) hasVersionTable() (bool, error) {
+	query := "SELECT 1 FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_version'"
+	if storage.DialectOf(m.client) == storage.DialectSQLite {
+		query = "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'"
+	}
+	rows, err := m.client.Read(query)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+// Check returns ErrSchemaMismatch if the schema of the database is not at the
+// version of the latest migration. It does not modify the database.
+func (m *Migrator) Check() error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("%w: the database is at version %d but version %d is expected, run 'arewefastyet migrate up'", ErrSchemaMismatch, version, m.Latest())
+	}
+	return nil
+}
+
+// Up applies the migrations up to the given version, or up to the latest one if the
+// version is zero. It returns the migrations that were applied.
+func (m *Migrator) Up(to int) ([]Migration, error) {
+	if to == 0 {
+		to = m.Latest()
+	}
+	if to < 0 || to > m.Latest() {
+		return nil, fmt.Errorf("unknown version %d, the latest version is %d", to, m.Latest())
+	}
+	if _, err := m.client.Write(createVersionTable); err != nil {
+		return nil, err
+	}
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if version >= to {
+		return nil, nil
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations[version:to] {
+		if err := m.exec(migration.Up); err != nil {
+			return applied, fmt.Errorf("could not apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		if _, err := m.client.Write("INSERT INTO schema_version(version, name) VALUES(?, ?)", migration.Version, migration.Name); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the migrations applied after the given version, from the latest one.
+// It returns the migrations that were reverted.
+func (m *Migrator) Down(to int) ([]Migration, error) {
+	if to < 0 || to > m.Latest() {
+		return nil, fmt.Errorf("unknown version %d, the latest version is %d", to, m.Latest())
+	}
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if version > m.Latest() {
+		return nil, fmt.Errorf("the database is at version %d which is unknown to this binary", version)
+	}
+
+	var reverted []Migration
+	for i := version - 1; i >= to; i-- {
+		migration := m.migrations[i]
+		if err := m.exec(migration.Down); err != nil {
+			return reverted, fmt.Errorf("could not revert migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		if _, err := m.client.Write("DELETE FROM schema_version WHERE version = ?", migration.Version); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// Baseline records the migrations up to the given version as applied without running
+// them. It is used once on databases that were created before the migrations existed,
+// the databases created from the former sql/schema.sql are at version 4.
+func (m *Migrator) Baseline(to int) error {
+	if to <= 0 || to > m.Latest() {
+		return fmt.Errorf("unknown version %d, the latest version is %d", to, m.Latest())
+	}
+	if _, err := m.client.Write(createVersionTable); err != nil {
+		return err
+	}
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version != 0 {
+		return fmt.Errorf("the database is already at version %d", version)
+	}
+	for _, migration := range m.migrations[:to] {
+		if _, err := m.client.Write("INSERT INTO schema_version(version, name) VALUES(?, ?)", migration.Version, migration.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exec runs the statements of the given script one by one.
+func (m *Migrator) exec(script string) error {
+	for _, statement := range statements(script) {
+		if _, err := m.client.Write(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statements splits a script into its statements, which end with a semicolon at the
+// end of a line. The comment lines are removed.
+func statements(script string) []string {
+	var res []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			res = append(res, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		res = append(res, s)
+	}
+	return res
+}
@@ -0,0 +1,189 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package migrate
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func newTestClient(c *qt.C) *sqlite.Client {
+	client, err := (&sqlite.Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}).NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	c := qt.New(t)
+	m, err := New(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.Latest(), qt.Not(qt.Equals), 0)
+	for _, migration := range m.Migrations() {
+		c.Assert(statements(migration.Up), qt.Not(qt.HasLen), 0, qt.Commentf(migration.Name))
+		c.Assert(statements(migration.Down), qt.Not(qt.HasLen), 0, qt.Commentf(migration.Name))
+	}
+
+	// The SQLite schema must be kept at the version of the latest migration.
+	m, err = New(newTestClient(c))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.Check(), qt.IsNil)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "Ordered by version", files: []string{"0002_b.up.sql", "0002_b.down.sql", "0001_a.up.sql", "0001_a.down.sql", "README.md"}, want: []string{"a", "b"}},
+		{name: "Missing version", files: []string{"0001_a.up.sql", "0001_a.down.sql", "0003_c.up.sql", "0003_c.down.sql"}, wantErr: "migration 2 is missing"},
+		{name: "Missing down", files: []string{"0001_a.up.sql"}, wantErr: `migration 1 \(a\) must have both an up and a down file`},
+		{name: "Different names", files: []string{"0001_a.up.sql", "0001_b.down.sql"}, wantErr: "migration 1 is named both a and b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			fsys := fstest.MapFS{}
+			for _, file := range tt.files {
+				fsys[file] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+			}
+			list, err := load(fsys)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			var names []string
+			for i, m := range list {
+				c.Assert(m.Version, qt.Equals, i+1)
+				names = append(names, m.Name)
+			}
+			c.Assert(names, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	c := qt.New(t)
+	script := "-- A comment.\n\nCREATE TABLE a (\n  id int\n);\nALTER TABLE a\n  ADD COLUMN b int;\nDROP TABLE c"
+	c.Assert(statements(script), qt.DeepEquals, []string{
+		"CREATE TABLE a (\n  id int\n)",
+		"ALTER TABLE a\n  ADD COLUMN b int",
+		"DROP TABLE c",
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c)
+	_, err := client.Write("DELETE FROM schema_version")
+	c.Assert(err, qt.IsNil)
+
+	m, err := newMigrator(client, fstest.MapFS{
+		"0001_first.up.sql":    {Data: []byte("CREATE TABLE first (id int);\nCREATE TABLE other (id int);")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE other;\nDROP TABLE first;")},
+		"0002_second.up.sql":   {Data: []byte("ALTER TABLE first ADD COLUMN name text;")},
+		"0002_second.down.sql": {Data: []byte("ALTER TABLE first DROP COLUMN name;")},
+		"0003_third.up.sql":    {Data: []byte("CREATE TABLE third (id int);")},
+		"0003_third.down.sql":  {Data: []byte("DROP TABLE third;")},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.Check(), qt.ErrorIs, ErrSchemaMismatch)
+
+	applied, err := m.Up(2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.HasLen, 2)
+	_, err = client.Write("INSERT INTO first(id, name) VALUES(1, 'a')")
+	c.Assert(err, qt.IsNil)
+
+	applied, err = m.Up(0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.HasLen, 1)
+	c.Assert(applied[0].Name, qt.Equals, "third")
+	c.Assert(m.Check(), qt.IsNil)
+
+	// Nothing left to apply.
+	applied, err = m.Up(0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.HasLen, 0)
+
+	reverted, err := m.Down(1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reverted, qt.HasLen, 2)
+	c.Assert(reverted[0].Name, qt.Equals, "third")
+	version, err := m.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, 1)
+	_, err = client.Write("INSERT INTO third(id) VALUES(1)")
+	c.Assert(err, qt.ErrorMatches, ".*no such table: third.*")
+
+	_, err = m.Up(4)
+	c.Assert(err, qt.ErrorMatches, "unknown version 4, the latest version is 3")
+	c.Assert(m.Baseline(2), qt.ErrorMatches, "the database is already at version 1")
+
+	_, err = m.Down(0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.Baseline(2), qt.IsNil)
+	version, err = m.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, 2)
+
+	// The baselined migrations were not run.
+	_, err = client.Write("INSERT INTO first(id) VALUES(1)")
+	c.Assert(err, qt.ErrorMatches, ".*no such table: first.*")
+
+	// A failing migration is not recorded.
+	m.migrations[2].Up = "CREATE TABLE third (id int);\nCREATE TABLE third (id int);"
+	_, err = m.Up(0)
+	c.Assert(err, qt.ErrorMatches, `could not apply migration 3 \(third\): .*already exists.*`)
+	version, err = m.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, 2)
+}
+
+func TestMigratorCheckIsReadOnly(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c)
+	_, err := client.Write("DROP TABLE schema_version")
+	c.Assert(err, qt.IsNil)
+
+	m, err := New(client)
+	c.Assert(err, qt.IsNil)
+
+	// A database without the schema_version table is at version zero, checking it does not create the table.
+	c.Assert(m.Check(), qt.ErrorIs, ErrSchemaMismatch)
+	version, err := m.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, 0)
+	exists, err := m.hasVersionTable()
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.IsFalse)
+
+	// Baselining the database creates the table.
+	c.Assert(m.Baseline(4), qt.IsNil)
+	version, err = m.Version()
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, 4)
+}
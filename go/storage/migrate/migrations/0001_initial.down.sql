@@ -0,0 +1,7 @@
+DROP TABLE `query_plans`;
+DROP TABLE `microbenchmark_details`;
+DROP TABLE `microbenchmark`;
+DROP TABLE `metrics`;
+DROP TABLE `macrobenchmark_results`;
+DROP TABLE `macrobenchmark`;
+DROP TABLE `execution`;
@@ -0,0 +1,99 @@
+-- Tables of the first version of arewefastyet.
+
+CREATE TABLE `execution` (
+  `uuid` varchar(100) NOT NULL,
+  `status` varchar(100) DEFAULT 'created',
+  `started_at` datetime DEFAULT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  `source` varchar(100) DEFAULT NULL,
+  `git_ref` varchar(100) DEFAULT NULL,
+  `workload` varchar(100) DEFAULT NULL,
+  `pull_nb` int DEFAULT '0',
+  PRIMARY KEY (`uuid`),
+  KEY `finished_at` (`finished_at`,`status`),
+  KEY `started_at` (`started_at` DESC),
+  KEY `status` (`status`),
+  KEY `git_ref` (`git_ref`),
+  KEY `pull_nb` (`pull_nb`),
+  KEY `idx_execution_on_source` (`source`),
+  KEY `finished_at_2` (`finished_at`,`git_ref`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `macrobenchmark` (
+  `macrobenchmark_id` int NOT NULL AUTO_INCREMENT,
+  `commit` varchar(100) DEFAULT NULL,
+  `DateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  `exec_uuid` varchar(100) DEFAULT NULL,
+  `workload` varchar(100) DEFAULT NULL,
+  PRIMARY KEY (`macrobenchmark_id`),
+  KEY `exec_uuid` (`exec_uuid`),
+  KEY `commit` (`commit`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `macrobenchmark_results` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `macrobenchmark_id` int DEFAULT NULL,
+  `tps` decimal(8,2) DEFAULT NULL,
+  `latency` decimal(8,2) DEFAULT NULL,
+  `errors` decimal(8,2) DEFAULT NULL,
+  `reconnects` decimal(8,2) DEFAULT NULL,
+  `time` int DEFAULT NULL,
+  `threads` decimal(8,2) DEFAULT NULL,
+  `total_qps` decimal(8,2) DEFAULT NULL,
+  `reads_qps` decimal(8,2) DEFAULT NULL,
+  `writes_qps` decimal(8,2) DEFAULT NULL,
+  `other_qps` decimal(8,2) DEFAULT NULL,
+  `queries` int DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `macrobenchmark_id` (`macrobenchmark_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `metrics` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `exec_uuid` varchar(100) DEFAULT NULL,
+  `name` varchar(250) DEFAULT NULL,
+  `value` float DEFAULT NULL,
+  `description` varchar(250) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `exec_uuid` (`exec_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `microbenchmark` (
+  `microbenchmark_no` int NOT NULL AUTO_INCREMENT,
+  `pkg_name` varchar(255) DEFAULT NULL,
+  `name` varchar(255) DEFAULT NULL,
+  `git_ref` varchar(255) DEFAULT NULL,
+  `exec_uuid` varchar(100) DEFAULT NULL,
+  PRIMARY KEY (`microbenchmark_no`),
+  KEY `git_ref` (`git_ref`),
+  KEY `exec_uuid` (`exec_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `microbenchmark_details` (
+  `detail_no` int NOT NULL AUTO_INCREMENT,
+  `microbenchmark_no` int DEFAULT NULL,
+  `name` varchar(255) DEFAULT NULL,
+  `bench_type` varchar(255) DEFAULT NULL,
+  `n` int DEFAULT NULL,
+  `ns_per_op` decimal(22,5) DEFAULT NULL,
+  `mb_per_sec` decimal(22,5) DEFAULT NULL,
+  `bytes_per_op` decimal(22,5) DEFAULT NULL,
+  `allocs_per_op` decimal(22,5) DEFAULT NULL,
+  PRIMARY KEY (`detail_no`),
+  KEY `microbenchmark_no` (`microbenchmark_no`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `query_plans` (
+  `plan_id` int NOT NULL AUTO_INCREMENT,
+  `exec_uuid` varchar(100) DEFAULT NULL,
+  `macrobenchmark_id` int DEFAULT NULL,
+  `key` longtext,
+  `plan` longtext,
+  `exec_count` bigint DEFAULT NULL,
+  `exec_time` bigint DEFAULT NULL,
+  `rows` int DEFAULT NULL,
+  `errors` int DEFAULT NULL,
+  PRIMARY KEY (`plan_id`),
+  KEY `macrobenchmark_id` (`macrobenchmark_id`),
+  KEY `exec_uuid` (`exec_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE `macrobenchmark`
+  DROP KEY `type_vtgate_planner_version`,
+  DROP COLUMN `vtgate_planner_version`;
@@ -0,0 +1,6 @@
+-- The macrobenchmarks are run with a given version of the VTGate query planner.
+-- The benchmarks that existed before were all using the V3 planner.
+
+ALTER TABLE `macrobenchmark`
+  ADD COLUMN `vtgate_planner_version` varchar(20) NOT NULL DEFAULT 'V3' AFTER `exec_uuid`,
+  ADD KEY `type_vtgate_planner_version` (`workload`,`vtgate_planner_version`);
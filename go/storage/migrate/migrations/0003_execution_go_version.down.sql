@@ -0,0 +1,2 @@
+ALTER TABLE `execution`
+  DROP COLUMN `go_version`;
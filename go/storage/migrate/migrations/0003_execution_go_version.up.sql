@@ -0,0 +1,4 @@
+-- Version of Go used to build Vitess during the execution.
+
+ALTER TABLE `execution`
+  ADD COLUMN `go_version` varchar(16) DEFAULT NULL AFTER `pull_nb`;
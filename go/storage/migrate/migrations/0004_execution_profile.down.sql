@@ -0,0 +1,4 @@
+ALTER TABLE `execution`
+  DROP KEY `idx_execution_on_profile_binary`,
+  DROP COLUMN `profile_mode`,
+  DROP COLUMN `profile_binary`;
@@ -0,0 +1,6 @@
+-- Binary profiled by the execution and how, both are NULL for regular executions.
+
+ALTER TABLE `execution`
+  ADD COLUMN `profile_binary` varchar(20) DEFAULT NULL AFTER `go_version`,
+  ADD COLUMN `profile_mode` varchar(20) DEFAULT NULL AFTER `profile_binary`,
+  ADD KEY `idx_execution_on_profile_binary` (`profile_binary`);
@@ -0,0 +1,25 @@
+-- Persisted execution queue, restored when the server starts.
+
+CREATE TABLE `execution_queue` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `uuid` varchar(100) DEFAULT NULL,
+  `git_ref` varchar(100) DEFAULT NULL,
+  `source` varchar(100) DEFAULT NULL,
+  `workload` varchar(100) DEFAULT NULL,
+  `planner_version` varchar(20) DEFAULT NULL,
+  `pull_nb` int DEFAULT '0',
+  `pull_base_ref` varchar(100) DEFAULT NULL,
+  `version_major` int DEFAULT '0',
+  `version_minor` int DEFAULT '0',
+  `version_patch` int DEFAULT '0',
+  `profile_binary` varchar(20) DEFAULT NULL,
+  `profile_mode` varchar(20) DEFAULT NULL,
+  `compare_with` longtext,
+  `retry` int DEFAULT '0',
+  `notify_always` tinyint(1) DEFAULT '0',
+  `executing` tinyint(1) DEFAULT '0',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `uuid` (`uuid`),
+  KEY `source` (`source`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,8 @@
+ALTER TABLE `execution_queue`
+  DROP COLUMN `retry_at`,
+  DROP COLUMN `failures`,
+  ADD COLUMN `retry` int DEFAULT '0' AFTER `compare_with`;
+
+ALTER TABLE `execution`
+  DROP COLUMN `failure_reason`,
+  DROP COLUMN `failure_category`;
@@ -0,0 +1,11 @@
+-- Classified failures of the executions. The elements of the queue keep the failures
+-- of their previous attempts and are retried at a time depending on their category.
+
+ALTER TABLE `execution`
+  ADD COLUMN `failure_category` varchar(50) DEFAULT NULL AFTER `profile_mode`,
+  ADD COLUMN `failure_reason` text AFTER `failure_category`;
+
+ALTER TABLE `execution_queue`
+  DROP COLUMN `retry`,
+  ADD COLUMN `failures` longtext AFTER `compare_with`,
+  ADD COLUMN `retry_at` datetime DEFAULT NULL AFTER `failures`;
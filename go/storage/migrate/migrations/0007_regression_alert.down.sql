@@ -0,0 +1 @@
+DROP TABLE `regression_alert`;
@@ -0,0 +1,13 @@
+-- Regressions already announced on Slack, so they are only announced once.
+
+CREATE TABLE `regression_alert` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `source` varchar(100) NOT NULL,
+  `workload` varchar(100) NOT NULL,
+  `planner_version` varchar(20) NOT NULL DEFAULT '',
+  `git_ref` varchar(100) NOT NULL,
+  `compared_git_ref` varchar(100) NOT NULL,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `alert` (`source`,`workload`,`planner_version`,`git_ref`,`compared_git_ref`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
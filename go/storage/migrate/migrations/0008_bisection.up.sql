@@ -0,0 +1,21 @@
+-- Bisections of the regressions between two nightly macrobenchmarks.
+
+CREATE TABLE `bisection` (
+  `id` varchar(100) NOT NULL,
+  `workload` varchar(100) NOT NULL,
+  `planner_version` varchar(20) NOT NULL DEFAULT '',
+  `good_git_ref` varchar(100) NOT NULL,
+  `bad_git_ref` varchar(100) NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `commits` longtext,
+  `good_index` int NOT NULL DEFAULT '-1',
+  `bad_index` int NOT NULL DEFAULT '0',
+  `current_git_ref` varchar(100) DEFAULT NULL,
+  `culprit` varchar(100) DEFAULT NULL,
+  `error` text,
+  `steps` longtext,
+  `started_at` datetime DEFAULT NULL,
+  `finished_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
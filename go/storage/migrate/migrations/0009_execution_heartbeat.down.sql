@@ -0,0 +1,2 @@
+ALTER TABLE `execution`
+  DROP COLUMN `heartbeat_at`;
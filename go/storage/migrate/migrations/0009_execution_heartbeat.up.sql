@@ -0,0 +1,4 @@
+-- Last heartbeat of a started execution, used to detect the orphaned ones.
+
+ALTER TABLE `execution`
+  ADD COLUMN `heartbeat_at` datetime DEFAULT NULL AFTER `failure_reason`;
@@ -0,0 +1,15 @@
+-- Files collected during the executions, their content is saved in the artifact store.
+
+CREATE TABLE `artifact` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `exec_uuid` varchar(100) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `kind` varchar(20) NOT NULL,
+  `digest` char(64) NOT NULL,
+  `size` bigint NOT NULL,
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `exec_uuid_name` (`exec_uuid`,`name`),
+  KEY `digest` (`digest`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE `execution_queue`
+  DROP COLUMN `baseline`;
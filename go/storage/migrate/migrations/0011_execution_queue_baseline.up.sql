@@ -0,0 +1,5 @@
+-- Elements of the queue that are only created to be compared with another element,
+-- their regressions are not evaluated.
+
+ALTER TABLE `execution_queue`
+  ADD COLUMN `baseline` tinyint(1) DEFAULT '0' AFTER `notify_always`;
@@ -17,11 +17,13 @@ limitations under the License.
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/vitessio/arewefastyet/go/storage"
 )
 
 const (
@@ -50,27 +52,35 @@ func (c *Client) Close() error {
 }
 
 func (c *Client) Write(query string, args ...interface{}) (int64, error) {
+	return c.WriteContext(context.Background(), query, args...)
+}
+
+func (c *Client) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
 	if c.db == nil {
 		return 0, errors.New(ErrorClientConnectionNotInitialized)
 	}
-	stms, err := c.db.Prepare(query)
+	stms, err := c.db.PrepareContext(ctx, query)
 	if err != nil {
 		return 0, err
 	}
 	defer stms.Close()
 
-	res, err := stms.Exec(args...)
+	res, err := stms.ExecContext(ctx, args...)
 	if err != nil {
 		return 0, err
 	}
 	return res.LastInsertId()
 }
 
-func (c *Client) Read(query string, args ...interface{}) (*sql.Rows, error) {
+func (c *Client) Read(query string, args ...interface{}) (storage.Rows, error) {
+	return c.ReadContext(context.Background(), query, args...)
+}
+
+func (c *Client) ReadContext(ctx context.Context, query string, args ...interface{}) (storage.Rows, error) {
 	if c.db == nil {
 		return nil, errors.New(ErrorClientConnectionNotInitialized)
 	}
-	rows, err := c.db.Query(query, args...)
+	rows, err := c.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
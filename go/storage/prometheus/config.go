@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package prometheus
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagPrometheusURL      = "prometheus-url"
+	flagPrometheusLookback = "prometheus-lookback"
+
+	ErrorInvalidConfiguration = "invalid configuration"
+
+	// queryTimeout is the maximum duration of a request to the Prometheus server.
+	queryTimeout = 30 * time.Second
+
+	// defaultLookback is the default Lookback, it is the default retention period of Prometheus.
+	defaultLookback = 15 * 24 * time.Hour
+)
+
+// Config defines the Prometheus server queried through its HTTP API.
+type Config struct {
+	// URL is the base URL of the Prometheus server, for instance http://localhost:9090.
+	URL string
+
+	// Lookback is how far in the past the samples of an execution are searched.
+	Lookback time.Duration
+}
+
+func (cfg Config) NewClient() (*Client, error) {
+	if !cfg.IsValid() {
+		return nil, errors.New(ErrorInvalidConfiguration)
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = defaultLookback
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	if !strings.HasPrefix(cfg.URL, "http://") && !strings.HasPrefix(cfg.URL, "https://") {
+		cfg.URL = "http://" + cfg.URL
+	}
+	return &Client{
+		Config: &cfg,
+		http:   &http.Client{Timeout: queryTimeout},
+	}, nil
+}
+
+// IsValid return true if Config is ready to be used, and false otherwise.
+func (cfg Config) IsValid() bool {
+	return cfg.URL != ""
+}
+
+func (cfg *Config) AddToViper(v *viper.Viper) {
+	_ = v.UnmarshalKey(flagPrometheusURL, &cfg.URL)
+	_ = v.UnmarshalKey(flagPrometheusLookback, &cfg.Lookback)
+}
+
+// AddToCommand adds Config to the given cobra.Command.
+func (cfg *Config) AddToCommand(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cfg.URL, flagPrometheusURL, "", "URL of the Prometheus server, for instance http://localhost:9090.")
+	cmd.Flags().DurationVar(&cfg.Lookback, flagPrometheusLookback, defaultLookback, "How far in the past the samples of an execution are searched in Prometheus, it should not exceed its retention period.")
+
+	_ = viper.BindPFlag(flagPrometheusURL, cmd.Flags().Lookup(flagPrometheusURL))
+	_ = viper.BindPFlag(flagPrometheusLookback, cmd.Flags().Lookup(flagPrometheusLookback))
+}
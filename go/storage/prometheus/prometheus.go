@@ -0,0 +1,122 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package prometheus queries a Prometheus server through its HTTP API.
+// See https://prometheus.io/docs/prometheus/latest/querying/api/.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client used to query a Prometheus server.
+type Client struct {
+	Config *Config
+	http   *http.Client
+}
+
+// Sample is an element of the instant vector returned by a query.
+type Sample struct {
+	Metric map[string]string
+	Value  float64
+}
+
+// queryResponse is the body of the responses of the query endpoint.
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			// Value is a pair of the evaluation timestamp and of the value as a string.
+			Value [2]interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query evaluates the given PromQL query at the current time. The query must
+// return an instant vector, whose samples are returned.
+func (c *Client) Query(ctx context.Context, query string) ([]Sample, error) {
+	form := url.Values{"query": {query}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Config.URL+"/api/v1/query", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error executing query %q: %w", query, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res queryResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("error executing query %q: unexpected response with status %s: %w", query, resp.Status, err)
+	}
+	if res.Status != "success" {
+		return nil, fmt.Errorf("error executing query %q: %s: %s", query, res.ErrorType, res.Error)
+	}
+	if res.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("error executing query %q: expected a vector, got a %s", query, res.Data.ResultType)
+	}
+
+	samples := make([]Sample, 0, len(res.Data.Result))
+	for _, r := range res.Data.Result {
+		s, ok := r.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("error executing query %q: invalid sample value %v", query, r.Value[1])
+		}
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error executing query %q: invalid sample value: %w", query, err)
+		}
+		samples = append(samples, Sample{Metric: r.Metric, Value: value})
+	}
+	return samples, nil
+}
+
+// Ping checks that the Prometheus server is ready to serve queries.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Config.URL+"/-/ready", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Prometheus server at %s is not ready: %s", c.Config.URL, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func newTestClient(c *qt.C, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	c.Cleanup(server.Close)
+	client, err := Config{URL: server.URL + "/"}.NewClient()
+	c.Assert(err, qt.IsNil)
+	return client
+}
+
+func TestClient_Query(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c, func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, qt.Equals, http.MethodPost)
+		c.Check(r.URL.Path, qt.Equals, "/api/v1/query")
+		switch r.FormValue("query") {
+		case "up":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"__name__":"up","job":"vtgate"},"value":[1700000000.123,"1"]},
+				{"metric":{"__name__":"up","job":"vttablet"},"value":[1700000000.123,"0.5"]}
+			]}}`))
+		case "up[5m]":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	})
+
+	samples, err := client.Query(context.Background(), "up")
+	c.Assert(err, qt.IsNil)
+	c.Assert(samples, qt.DeepEquals, []Sample{
+		{Metric: map[string]string{"__name__": "up", "job": "vtgate"}, Value: 1},
+		{Metric: map[string]string{"__name__": "up", "job": "vttablet"}, Value: 0.5},
+	})
+
+	_, err = client.Query(context.Background(), "up[5m]")
+	c.Assert(err, qt.ErrorMatches, `error executing query "up\[5m\]": expected a vector, got a matrix`)
+
+	_, err = client.Query(context.Background(), "up{")
+	c.Assert(err, qt.ErrorMatches, `error executing query "up{": bad_data: parse error`)
+}
+
+func TestClient_Ping(t *testing.T) {
+	c := qt.New(t)
+	ready := true
+	client := newTestClient(c, func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, qt.Equals, "/-/ready")
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	c.Assert(client.Ping(context.Background()), qt.IsNil)
+	ready = false
+	c.Assert(client.Ping(context.Background()), qt.ErrorMatches, "Prometheus server at .* is not ready: 503 Service Unavailable")
+}
+
+func TestConfig_NewClient(t *testing.T) {
+	c := qt.New(t)
+	_, err := Config{}.NewClient()
+	c.Assert(err, qt.ErrorMatches, ErrorInvalidConfiguration)
+
+	client, err := Config{URL: "localhost:9090/"}.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Assert(client.Config.URL, qt.Equals, "http://localhost:9090")
+	c.Assert(client.Config.Lookback, qt.Equals, defaultLookback)
+}
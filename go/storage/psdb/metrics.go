@@ -0,0 +1,40 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package psdb
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueryDuration measures the latency of the queries sent to the database, it is labeled
+// by operation: "read" or "write". It is exported so it can be registered by the server.
+var QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "arewefastyet",
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Latency of the queries sent to the database.",
+	Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+}, []string{"operation"})
+
+// observeQuery records the duration of a query of the given operation that started at start.
+func observeQuery(operation string, start time.Time) {
+	QueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
@@ -19,6 +19,7 @@
 package psdb
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/storage"
 )
 
 const (
@@ -36,6 +38,8 @@ const (
 	flagPsdbUserRead      = "planetscale-db-user-read"
 	flagPsdbHost          = "planetscale-db-host"
 	flagPsdbDatabase      = "planetscale-db-database"
+	flagPsdbQueryTimeout  = "planetscale-db-query-timeout"
+	flagPsdbMaxRetries    = "planetscale-db-max-retries"
 
 	errorClientConnectionNotInitialized = "the client connection to the database is not initialized"
 
@@ -58,6 +62,11 @@ type (
 		hostname     string
 		authWrite    auth
 		authRead     auth
+
+		// queryTimeout is the maximum duration of a query, zero disables the timeout.
+		// maxRetries is how many times a query failing with a transient error is retried.
+		queryTimeout time.Duration
+		maxRetries   int
 	}
 
 	Client struct {
@@ -80,6 +89,8 @@ func (cfg *Config) AddToViper(v *viper.Viper) {
 	_ = v.UnmarshalKey(flagPsdbOrg, &cfg.organisation)
 	_ = v.UnmarshalKey(flagPsdbHost, &cfg.hostname)
 	_ = v.UnmarshalKey(flagPsdbDatabase, &cfg.database)
+	_ = v.UnmarshalKey(flagPsdbQueryTimeout, &cfg.queryTimeout)
+	_ = v.UnmarshalKey(flagPsdbMaxRetries, &cfg.maxRetries)
 
 	// Write authentication
 	_ = v.UnmarshalKey(flagPsdbPasswordWrite, &cfg.authWrite.password)
@@ -99,6 +110,12 @@ func (cfg *Config) AddToCommand(cmd *cobra.Command) {
 	_ = viper.BindPFlag(flagPsdbHost, cmd.Flags().Lookup(flagPsdbHost))
 	_ = viper.BindPFlag(flagPsdbDatabase, cmd.Flags().Lookup(flagPsdbDatabase))
 
+	// Timeouts and retries
+	cmd.Flags().DurationVar(&cfg.queryTimeout, flagPsdbQueryTimeout, 30*time.Second, "Maximum duration of a query to PlanetScaleDB, including the time spent reading its rows. Zero disables the timeout.")
+	cmd.Flags().IntVar(&cfg.maxRetries, flagPsdbMaxRetries, 3, "Number of times a query to PlanetScaleDB failing with a transient error, such as a connection reset or a deadlock, is retried.")
+	_ = viper.BindPFlag(flagPsdbQueryTimeout, cmd.Flags().Lookup(flagPsdbQueryTimeout))
+	_ = viper.BindPFlag(flagPsdbMaxRetries, cmd.Flags().Lookup(flagPsdbMaxRetries))
+
 	// Write authentication
 	cmd.Flags().StringVar(&cfg.authWrite.username, flagPsdbUserWrite, "", "Username used to authenticate to the write servers of PlanetScaleDB.")
 	cmd.Flags().StringVar(&cfg.authWrite.password, flagPsdbPasswordWrite, "", "Password used to authenticate to the write servers of PlanetScaleDB.")
@@ -155,30 +172,150 @@ func (c *Client) Close() error {
 	return c.readDB.Close()
 }
 
+// Ping checks that both the write and the read-only servers can be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.writeDB == nil || c.readDB == nil {
+		return errors.New(errorClientConnectionNotInitialized)
+	}
+	if err := c.writeDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("write servers: %w", err)
+	}
+	if err := c.readDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("read-only servers: %w", err)
+	}
+	return nil
+}
+
+// Dialect returns the SQL dialect of PlanetScaleDB.
+func (c *Client) Dialect() storage.Dialect {
+	return storage.DialectMySQL
+}
+
 func (c *Client) Write(query string, args ...interface{}) (int64, error) {
+	return c.WriteContext(context.Background(), query, args...)
+}
+
+// WriteContext executes the given query on the write servers. The query is retried if it
+// fails with an error guaranteeing that it was not applied, such as a deadlock.
+func (c *Client) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
 	if c.writeDB == nil {
 		return 0, errors.New(errorClientConnectionNotInitialized)
 	}
-	stms, err := c.writeDB.Prepare(query)
-	if err != nil {
-		return 0, err
+	defer observeQuery("write", time.Now())
+
+	var id int64
+	err := c.config.retryPolicy().Do(ctx, isTransientWriteError, func() error {
+		queryCtx, cancel := c.config.withQueryTimeout(ctx)
+		defer cancel()
+
+		stms, err := c.writeDB.PrepareContext(queryCtx, query)
+		if err != nil {
+			return err
+		}
+		defer stms.Close()
+
+		res, err := stms.ExecContext(queryCtx, args...)
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	return id, err
+}
+
+func (c *Client) Read(query string, args ...interface{}) (storage.Rows, error) {
+	return c.ReadContext(context.Background(), query, args...)
+}
+
+// ReadContext executes the given query on the read-only servers, it is retried if it fails
+// with a transient error. The query timeout also bounds the time spent reading the rows.
+func (c *Client) ReadContext(ctx context.Context, query string, args ...interface{}) (storage.Rows, error) {
+	if c.readDB == nil {
+		return nil, errors.New(errorClientConnectionNotInitialized)
 	}
-	defer stms.Close()
+	defer observeQuery("read", time.Now())
 
-	res, err := stms.Exec(args...)
+	var rows storage.Rows
+	err := c.config.retryPolicy().Do(ctx, isTransientReadError, func() error {
+		queryCtx, cancel := c.config.withQueryTimeout(ctx)
+		sqlRows, err := c.readDB.QueryContext(queryCtx, query, args...)
+		if err != nil {
+			cancel()
+			return err
+		}
+		// The rows are read after ReadContext returns, the context
+		// is released once they are closed.
+		rows = &cancelRows{Rows: sqlRows, cancel: cancel}
+		return nil
+	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return res.LastInsertId()
+	return rows, nil
 }
 
-func (c *Client) Read(query string, args ...interface{}) (*sql.Rows, error) {
-	if c.readDB == nil {
+// cancelRows are rows whose query context is canceled, along with its timeout, when they are closed.
+type cancelRows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+func (r *cancelRows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+// Begin starts a transaction on the write servers. The reads of the transaction are
+// also sent to the write servers so they see its writes.
+func (c *Client) Begin(ctx context.Context) (storage.Tx, error) {
+	if c.writeDB == nil {
 		return nil, errors.New(errorClientConnectionNotInitialized)
 	}
-	rows, err := c.readDB.Query(query, args...)
+	tx, err := c.writeDB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	return rows, nil
+	return &Tx{tx: tx}, nil
+}
+
+// Tx is a transaction of a Client. Its queries are not retried since a deadlock
+// rolls back the whole transaction.
+type Tx struct {
+	tx *sql.Tx
+}
+
+func (t *Tx) Write(query string, args ...interface{}) (int64, error) {
+	return t.WriteContext(context.Background(), query, args...)
+}
+
+func (t *Tx) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	defer observeQuery("write", time.Now())
+	res, err := t.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (t *Tx) Read(query string, args ...interface{}) (storage.Rows, error) {
+	return t.ReadContext(context.Background(), query, args...)
+}
+
+func (t *Tx) ReadContext(ctx context.Context, query string, args ...interface{}) (storage.Rows, error) {
+	defer observeQuery("read", time.Now())
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Dialect returns the SQL dialect of PlanetScaleDB.
+func (t *Tx) Dialect() storage.Dialect {
+	return storage.DialectMySQL
 }
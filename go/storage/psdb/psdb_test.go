@@ -0,0 +1,47 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package psdb
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	_ "modernc.org/sqlite"
+)
+
+func TestCancelRowsClose(t *testing.T) {
+	c := qt.New(t)
+	db, err := sql.Open("sqlite", filepath.Join(c.TempDir(), "psdb.db"))
+	c.Assert(err, qt.IsNil)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sqlRows, err := db.QueryContext(ctx, "SELECT 1")
+	c.Assert(err, qt.IsNil)
+	rows := &cancelRows{Rows: sqlRows, cancel: cancel}
+
+	// The context of the query lives as long as its rows are read.
+	c.Assert(rows.Next(), qt.IsTrue)
+	c.Assert(ctx.Err(), qt.IsNil)
+	c.Assert(rows.Close(), qt.IsNil)
+	c.Assert(ctx.Err(), qt.Equals, context.Canceled)
+}
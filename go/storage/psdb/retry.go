@@ -0,0 +1,81 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package psdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+const (
+	// MySQL error numbers of the failures after which a statement can be retried: the
+	// statement, and the transaction it belonged to, were rolled back.
+	errLockWaitTimeout = 1205
+	errLockDeadlock    = 1213
+
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 2 * time.Second
+)
+
+func (cfg *Config) retryPolicy() storage.RetryPolicy {
+	return storage.RetryPolicy{
+		MaxRetries:     cfg.maxRetries,
+		InitialBackoff: initialBackoff,
+		MaxBackoff:     maxBackoff,
+	}
+}
+
+// withQueryTimeout returns a context bounded by the query timeout, if any.
+func (cfg *Config) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cfg.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.queryTimeout)
+}
+
+// isTransientWriteError returns true if the given error guarantees that the write was not
+// applied, so that it can be retried without being applied twice.
+func isTransientWriteError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == errLockWaitTimeout || mysqlErr.Number == errLockDeadlock
+	}
+	// database/sql returns ErrBadConn when the query could not be sent on any connection.
+	return errors.Is(err, driver.ErrBadConn)
+}
+
+// isTransientReadError returns true if the read failed because of a lock or because of the
+// connection to the server, in which case it can be retried.
+func isTransientReadError(err error) bool {
+	if isTransientWriteError(err) {
+		return true
+	}
+	return errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package psdb
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantWrite bool
+		wantRead  bool
+	}{
+		{name: "Deadlock", err: &mysql.MySQLError{Number: errLockDeadlock}, wantWrite: true, wantRead: true},
+		{name: "Lock wait timeout", err: fmt.Errorf("insert: %w", &mysql.MySQLError{Number: errLockWaitTimeout}), wantWrite: true, wantRead: true},
+		{name: "Bad connection", err: driver.ErrBadConn, wantWrite: true, wantRead: true},
+		{name: "Connection reset", err: fmt.Errorf("read tcp: %w", syscall.ECONNRESET), wantRead: true},
+		{name: "Invalid connection", err: mysql.ErrInvalidConn, wantRead: true},
+		{name: "Duplicate entry", err: &mysql.MySQLError{Number: 1062}},
+		{name: "Other", err: errors.New("syntax error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(isTransientWriteError(tt.err), qt.Equals, tt.wantWrite)
+			c.Assert(isTransientReadError(tt.err), qt.Equals, tt.wantRead)
+		})
+	}
+}
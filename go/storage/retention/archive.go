@@ -0,0 +1,129 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+	"go.uber.org/multierr"
+)
+
+// archive writes the rows removed from each table to a gzip compressed file of the
+// archive directory, with one JSON object per line.
+type archive struct {
+	dir   string
+	files map[string]*archiveFile
+}
+
+type archiveFile struct {
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// newArchive returns an archive writing its files to the given directory,
+// which is only created once a row is archived.
+func newArchive(dir string) *archive {
+	return &archive{dir: dir, files: map[string]*archiveFile{}}
+}
+
+// path returns the path of the archive file of the given table.
+func (a *archive) path(table string) string {
+	return filepath.Join(a.dir, table+".jsonl.gz")
+}
+
+// write archives the given rows of the table and returns how many were written.
+func (a *archive) write(table string, rows storage.Rows) (int64, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return n, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[column] = values[i]
+		}
+		f, err := a.file(table)
+		if err != nil {
+			return n, err
+		}
+		if err := f.enc.Encode(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+func (a *archive) file(table string) (*archiveFile, error) {
+	if f, ok := a.files[table]; ok {
+		return f, nil
+	}
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return nil, err
+	}
+	// The files are appended to, gzip streams written one after the other form a valid gzip file.
+	file, err := os.OpenFile(a.path(table), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	f := &archiveFile{file: file, gz: gz, enc: json.NewEncoder(gz)}
+	a.files[table] = f
+	return f, nil
+}
+
+// sync writes the archived rows to the disk, it is called before the rows are deleted.
+func (a *archive) sync() error {
+	for _, f := range a.files {
+		if err := f.gz.Flush(); err != nil {
+			return err
+		}
+		if err := f.file.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close finishes the gzip streams and closes the files.
+func (a *archive) close() (err error) {
+	for _, f := range a.files {
+		err = multierr.Append(err, f.gz.Close())
+		err = multierr.Append(err, f.file.Close())
+	}
+	return err
+}
@@ -0,0 +1,232 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package retention removes the old rows of the database following rules per source and
+// age. The removed rows are archived as compressed JSON files on the local disk before
+// they are deleted. The artifacts of the executions are left to their own retention period.
+package retention
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+// tables lists the tables of an execution in the order their rows are deleted, the
+// rows referencing another table are deleted before the rows they reference.
+var tables = deletionOrder(storage.ExecutionTables)
+
+// deletionOrder returns the given tables in reverse order.
+func deletionOrder(executionTables []storage.ExecutionTable) []storage.ExecutionTable {
+	res := slices.Clone(executionTables)
+	slices.Reverse(res)
+	return res
+}
+
+// Report describes the rows removed by Prune, or that would be removed for Plan.
+type Report struct {
+	// Executions is the number of executions removed with all their rows.
+	Executions int
+
+	// Rows is the number of rows removed from each table.
+	Rows map[string]int64
+
+	// Rules is the number of executions each rule applied to.
+	Rules map[Rule]int
+
+	// Archive is the directory of the archive files, it is empty if no row was archived.
+	Archive string
+}
+
+// prunedExecution is an execution with rows to remove.
+type prunedExecution struct {
+	uuid   string
+	tables []storage.ExecutionTable
+}
+
+// Plan returns the report of what Prune would remove with the given rules, without
+// removing anything.
+func Plan(client storage.SQLClient, rules []Rule) (Report, error) {
+	report := newReport()
+	executions, err := expiredExecutions(client, rules, time.Now(), &report)
+	if err != nil {
+		return report, err
+	}
+	for _, e := range executions {
+		for _, t := range e.tables {
+			count, err := countRows(client, t, e.uuid)
+			if err != nil {
+				return report, err
+			}
+			report.Rows[t.Name] += count
+		}
+	}
+	return report, nil
+}
+
+// Prune removes the rows that are older than allowed by the given rules, after archiving
+// them in a sub-directory of archiveDir named after the current time. The rows of each
+// execution are archived and deleted in a single transaction, which is retried on the
+// next run if it fails, in which case the rows are archived twice.
+func Prune(client storage.SQLClient, rules []Rule, archiveDir string) (report Report, err error) {
+	report = newReport()
+	now := time.Now()
+	executions, err := expiredExecutions(client, rules, now, &report)
+	if err != nil || len(executions) == 0 {
+		return report, err
+	}
+
+	a := newArchive(filepath.Join(archiveDir, now.UTC().Format("20060102T150405Z")))
+	defer func() {
+		if closeErr := a.close(); err == nil {
+			err = closeErr
+		}
+		if len(a.files) > 0 {
+			report.Archive = a.dir
+		}
+	}()
+
+	for _, e := range executions {
+		removed := map[string]int64{}
+		err := storage.WithTransaction(client, func(tx storage.SQLClient) error {
+			for _, t := range e.tables {
+				n, err := archiveRows(tx, a, t, e.uuid)
+				if err != nil {
+					return err
+				}
+				removed[t.Name] = n
+			}
+			if err := a.sync(); err != nil {
+				return err
+			}
+			for _, t := range e.tables {
+				if _, err := tx.Write("DELETE FROM "+t.Name+" WHERE "+t.Where, e.uuid); err != nil {
+					return fmt.Errorf("could not delete the rows of %s for execution %s: %w", t.Name, e.uuid, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return report, err
+		}
+		for name, n := range removed {
+			report.Rows[name] += n
+		}
+	}
+	return report, nil
+}
+
+func newReport() Report {
+	return Report{Rows: map[string]int64{}, Rules: map[Rule]int{}}
+}
+
+// expiredExecutions returns the executions having rows older than allowed by the rules,
+// and counts the executions each rule applies to in the report. The detail tables of an
+// execution are only returned if the execution still has rows in them.
+func expiredExecutions(client storage.SQLClient, rules []Rule, now time.Time, report *Report) ([]prunedExecution, error) {
+	type candidate struct {
+		uuid, source string
+		startedAt    time.Time
+	}
+	rows, err := client.Read("SELECT uuid, IFNULL(source, ''), started_at FROM execution WHERE started_at IS NOT NULL ORDER BY started_at")
+	if err != nil {
+		return nil, err
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.uuid, &c.source, &c.startedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var executions []prunedExecution
+	for _, c := range candidates {
+		age := now.Sub(c.startedAt)
+		expired := func(r Rule) bool { return r.MaxAge != Forever && age > r.MaxAge }
+
+		if r, ok := ruleFor(rules, "", c.source); ok && expired(r) {
+			executions = append(executions, prunedExecution{uuid: c.uuid, tables: tables})
+			report.Rules[r]++
+			report.Executions++
+			continue
+		}
+		e := prunedExecution{uuid: c.uuid}
+		for _, t := range tables {
+			if !isDetailTable(t.Name) {
+				continue
+			}
+			r, ok := ruleFor(rules, t.Name, c.source)
+			if !ok || !expired(r) {
+				continue
+			}
+			count, err := countRows(client, t, c.uuid)
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				continue
+			}
+			e.tables = append(e.tables, t)
+			report.Rules[r]++
+		}
+		if len(e.tables) > 0 {
+			executions = append(executions, e)
+		}
+	}
+	return executions, nil
+}
+
+func countRows(client storage.SQLClient, t storage.ExecutionTable, execUUID string) (int64, error) {
+	rows, err := client.Read("SELECT COUNT(*) FROM "+t.Name+" WHERE "+t.Where, execUUID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int64
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+	return count, rows.Err()
+}
+
+func archiveRows(client storage.SQLClient, a *archive, t storage.ExecutionTable, execUUID string) (int64, error) {
+	rows, err := client.Read("SELECT * FROM "+t.Name+" WHERE "+t.Where, execUUID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n, err := a.write(t.Name, rows)
+	if err != nil {
+		return n, fmt.Errorf("could not archive the rows of %s for execution %s: %w", t.Name, execUUID, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,162 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
+)
+
+func newTestDatabase(c *qt.C) *sqlite.Client {
+	cfg := sqlite.Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}
+	client, err := cfg.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// insertExecution inserts an execution with one macrobenchmark, one query plan and one metric.
+func insertExecution(c *qt.C, client storage.SQLClient, uuid, source string, age time.Duration) {
+	_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, started_at) VALUES(?, 'finished', ?, 'abc', ?)", uuid, source, time.Now().Add(-age))
+	c.Assert(err, qt.IsNil)
+	id, err := client.Write("INSERT INTO macrobenchmark(`commit`, exec_uuid, workload) VALUES('abc', ?, 'OLTP')", uuid)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO macrobenchmark_results(macrobenchmark_id, tps) VALUES(?, 100)", id)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO query_plans(exec_uuid, macrobenchmark_id, `key`, plan) VALUES(?, ?, 'select 1', '{}')", uuid, id)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO metrics(exec_uuid, name, value) VALUES(?, 'cpu', 1.5)", uuid)
+	c.Assert(err, qt.IsNil)
+}
+
+func countAll(c *qt.C, client storage.SQLClient, table string) int64 {
+	rows, err := client.Read("SELECT COUNT(*) FROM " + table)
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	var count int64
+	c.Assert(rows.Next(), qt.IsTrue)
+	c.Assert(rows.Scan(&count), qt.IsNil)
+	return count
+}
+
+func readArchive(c *qt.C, path string) []map[string]interface{} {
+	f, err := os.Open(path)
+	c.Assert(err, qt.IsNil)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	c.Assert(err, qt.IsNil)
+
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var row map[string]interface{}
+		c.Assert(json.Unmarshal(scanner.Bytes(), &row), qt.IsNil)
+		rows = append(rows, row)
+	}
+	c.Assert(scanner.Err(), qt.IsNil)
+	return rows
+}
+
+func TestPrune(t *testing.T) {
+	c := qt.New(t)
+	client := newTestDatabase(c)
+
+	day := 24 * time.Hour
+	insertExecution(c, client, "tag", "cron_tags_v15.0.0", 400*day)
+	insertExecution(c, client, "old-pr", "cron_pr", 100*day)
+	insertExecution(c, client, "recent-pr", "cron_pr", 40*day)
+	insertExecution(c, client, "new-pr", "cron_pr", day)
+	insertExecution(c, client, "bisect", "bisect", 400*day)
+
+	rules := []Rule{
+		{Source: "cron_tags_*", MaxAge: Forever},
+		{Source: "cron_pr", MaxAge: 90 * day},
+		{Table: "query_plans", Source: "*", MaxAge: 30 * day},
+		{Table: "query_plans", Source: "cron_tags_*", MaxAge: Forever},
+	}
+	want := Report{
+		Executions: 1,
+		Rows: map[string]int64{
+			"execution":              1,
+			"macrobenchmark":         1,
+			"macrobenchmark_results": 1,
+			"metrics":                1,
+			"microbenchmark":         0,
+			"microbenchmark_details": 0,
+			"query_plans":            3,
+		},
+		Rules: map[Rule]int{rules[1]: 1, rules[2]: 2},
+	}
+
+	// The dry-run does not remove anything.
+	report, err := Plan(client, rules)
+	c.Assert(err, qt.IsNil)
+	c.Assert(report, qt.DeepEquals, want)
+	c.Assert(countAll(c, client, "execution"), qt.Equals, int64(5))
+	c.Assert(countAll(c, client, "query_plans"), qt.Equals, int64(5))
+
+	archiveDir := c.TempDir()
+	report, err = Prune(client, rules, archiveDir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(filepath.Dir(report.Archive), qt.Equals, archiveDir)
+	want.Archive = report.Archive
+	c.Assert(report, qt.DeepEquals, want)
+
+	rows, err := client.Read("SELECT uuid FROM execution ORDER BY uuid")
+	c.Assert(err, qt.IsNil)
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		c.Assert(rows.Scan(&uuid), qt.IsNil)
+		uuids = append(uuids, uuid)
+	}
+	rows.Close()
+	c.Assert(uuids, qt.DeepEquals, []string{"bisect", "new-pr", "recent-pr", "tag"})
+	c.Assert(countAll(c, client, "macrobenchmark"), qt.Equals, int64(4))
+	c.Assert(countAll(c, client, "macrobenchmark_results"), qt.Equals, int64(4))
+	c.Assert(countAll(c, client, "metrics"), qt.Equals, int64(4))
+	c.Assert(countAll(c, client, "query_plans"), qt.Equals, int64(2))
+
+	executions := readArchive(c, filepath.Join(report.Archive, "execution.jsonl.gz"))
+	c.Assert(executions, qt.HasLen, 1)
+	c.Assert(executions[0]["uuid"], qt.Equals, "old-pr")
+	c.Assert(executions[0]["source"], qt.Equals, "cron_pr")
+	plans := readArchive(c, filepath.Join(report.Archive, "query_plans.jsonl.gz"))
+	c.Assert(plans, qt.HasLen, 3)
+	c.Assert(plans[0]["key"], qt.Equals, "select 1")
+	_, err = os.Stat(filepath.Join(report.Archive, "microbenchmark.jsonl.gz"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+
+	// Nothing is left to prune and no archive is created.
+	report, err = Prune(client, rules, archiveDir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(report.Executions, qt.Equals, 0)
+	c.Assert(report.Rows["query_plans"], qt.Equals, int64(0))
+	c.Assert(report.Rules, qt.HasLen, 0)
+	c.Assert(report.Archive, qt.Equals, "")
+}
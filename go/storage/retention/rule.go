@@ -0,0 +1,150 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Forever is the age of a rule keeping its rows forever.
+const Forever time.Duration = 0
+
+// Rule defines how long the rows of the executions of a source are kept.
+type Rule struct {
+	// Table is the table pruned by the rule, one of DetailTables. The rule prunes the
+	// whole executions, with the rows of all their tables, if it is empty.
+	Table string
+
+	// Source matches the source of the executions, a trailing '*' matches any suffix.
+	Source string
+
+	// MaxAge is how long the rows are kept after the execution started.
+	MaxAge time.Duration
+}
+
+// DetailTables are the tables that can be pruned on their own by a rule, without
+// removing the executions they belong to.
+var DetailTables = []string{"query_plans", "microbenchmark_details", "metrics"}
+
+// ParseRule parses a rule written as [<table>:]<source>=<age>. The age is either "forever",
+// a number of days such as "90d", or a duration such as "12h".
+func ParseRule(s string) (Rule, error) {
+	var r Rule
+	selector, age, ok := strings.Cut(s, "=")
+	if !ok {
+		return r, fmt.Errorf("invalid rule %q: expected [<table>:]<source>=<age>", s)
+	}
+	if table, source, ok := strings.Cut(selector, ":"); ok {
+		if !isDetailTable(table) {
+			return r, fmt.Errorf("invalid rule %q: table %q cannot be pruned on its own, expected one of %s", s, table, strings.Join(DetailTables, ", "))
+		}
+		r.Table, selector = table, source
+	}
+	if selector == "" || strings.Contains(strings.TrimSuffix(selector, "*"), "*") {
+		return r, fmt.Errorf("invalid rule %q: the source must be a name optionally followed by '*'", s)
+	}
+	r.Source = selector
+
+	maxAge, err := parseAge(age)
+	if err != nil {
+		return r, fmt.Errorf("invalid rule %q: %w", s, err)
+	}
+	r.MaxAge = maxAge
+	return r, nil
+}
+
+func parseAge(age string) (time.Duration, error) {
+	if age == "forever" {
+		return Forever, nil
+	}
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(age)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q", age)
+	}
+	return d, nil
+}
+
+func isDetailTable(table string) bool {
+	for _, t := range DetailTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the rule in the format read by ParseRule.
+func (r Rule) String() string {
+	s := r.Source + "="
+	switch {
+	case r.MaxAge == Forever:
+		s += "forever"
+	case r.MaxAge%(24*time.Hour) == 0:
+		s += strconv.Itoa(int(r.MaxAge/(24*time.Hour))) + "d"
+	default:
+		s += r.MaxAge.String()
+	}
+	if r.Table != "" {
+		s = r.Table + ":" + s
+	}
+	return s
+}
+
+// matches returns true if the rule applies to the given source.
+func (r Rule) matches(source string) bool {
+	if prefix, ok := strings.CutSuffix(r.Source, "*"); ok {
+		return strings.HasPrefix(source, prefix)
+	}
+	return r.Source == source
+}
+
+// specificity orders the rules matching the same source, an exact name is
+// more specific than any pattern, and a longer pattern than a shorter one.
+func (r Rule) specificity() int {
+	if strings.HasSuffix(r.Source, "*") {
+		return len(r.Source) - 1
+	}
+	return int(^uint(0) >> 1)
+}
+
+// ruleFor returns the most specific of the rules of the given table matching the
+// source, and false if there is none.
+func ruleFor(rules []Rule, table, source string) (Rule, bool) {
+	var best Rule
+	found := false
+	for _, r := range rules {
+		if r.Table != table || !r.matches(source) {
+			continue
+		}
+		if !found || r.specificity() > best.specificity() {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
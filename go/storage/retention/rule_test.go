@@ -0,0 +1,88 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Rule
+		wantErr string
+	}{
+		{rule: "cron_tags_*=forever", want: Rule{Source: "cron_tags_*", MaxAge: Forever}},
+		{rule: "cron_pr=90d", want: Rule{Source: "cron_pr", MaxAge: 90 * 24 * time.Hour}},
+		{rule: "query_plans:*=30d", want: Rule{Table: "query_plans", Source: "*", MaxAge: 30 * 24 * time.Hour}},
+		{rule: "metrics:cron=12h", want: Rule{Table: "metrics", Source: "cron", MaxAge: 12 * time.Hour}},
+		{rule: "cron_pr", wantErr: `invalid rule "cron_pr": expected .*`},
+		{rule: "execution:cron=1d", wantErr: `invalid rule "execution:cron=1d": table "execution" cannot be pruned on its own, .*`},
+		{rule: "cron_*_branch=1d", wantErr: `invalid rule .*: the source must be a name optionally followed by '\*'`},
+		{rule: "=1d", wantErr: `invalid rule .*: the source must be .*`},
+		{rule: "cron=0d", wantErr: `invalid rule "cron=0d": invalid age "0d"`},
+		{rule: "cron=soon", wantErr: `invalid rule "cron=soon": invalid age "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			c := qt.New(t)
+			got, err := ParseRule(tt.rule)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.Equals, tt.want)
+			parsed, err := ParseRule(got.String())
+			c.Assert(err, qt.IsNil)
+			c.Assert(parsed, qt.Equals, got)
+		})
+	}
+}
+
+func TestRuleFor(t *testing.T) {
+	c := qt.New(t)
+	rules := []Rule{
+		{Source: "*", MaxAge: 365 * 24 * time.Hour},
+		{Source: "cron_*", MaxAge: 180 * 24 * time.Hour},
+		{Source: "cron_tags_*", MaxAge: Forever},
+		{Source: "cron_pr", MaxAge: 90 * 24 * time.Hour},
+		{Table: "query_plans", Source: "*", MaxAge: 30 * 24 * time.Hour},
+	}
+
+	for source, want := range map[string]Rule{
+		"cron_tags_v15.0.0":   rules[2],
+		"cron_pr":             rules[3],
+		"cron_v15.0.0-branch": rules[1],
+		"bisect":              rules[0],
+	} {
+		got, ok := ruleFor(rules, "", source)
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(got, qt.Equals, want, qt.Commentf("source %s", source))
+	}
+
+	got, ok := ruleFor(rules, "query_plans", "cron_pr")
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(got, qt.Equals, rules[4])
+	_, ok = ruleFor(rules, "metrics", "cron_pr")
+	c.Assert(ok, qt.IsFalse)
+}
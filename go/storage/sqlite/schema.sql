@@ -0,0 +1,178 @@
+-- Portable version of the schema built by the MySQL migrations of go/storage/migrate, used
+-- by the SQLite client. It is applied every time a client is created, every statement must
+-- therefore be idempotent. It is always at the version of the latest migration, which is
+-- recorded at the end of the file.
+
+CREATE TABLE IF NOT EXISTS artifact (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exec_uuid varchar(100) NOT NULL,
+  name varchar(255) NOT NULL,
+  kind varchar(20) NOT NULL,
+  digest char(64) NOT NULL,
+  size bigint NOT NULL,
+  created_at datetime DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (exec_uuid, name)
+);
+CREATE INDEX IF NOT EXISTS artifact_digest ON artifact (digest);
+CREATE INDEX IF NOT EXISTS artifact_created_at ON artifact (created_at);
+
+CREATE TABLE IF NOT EXISTS bisection (
+  id varchar(100) NOT NULL PRIMARY KEY,
+  workload varchar(100) NOT NULL,
+  planner_version varchar(20) NOT NULL DEFAULT '',
+  good_git_ref varchar(100) NOT NULL,
+  bad_git_ref varchar(100) NOT NULL,
+  status varchar(20) NOT NULL,
+  commits text,
+  good_index int NOT NULL DEFAULT -1,
+  bad_index int NOT NULL DEFAULT 0,
+  current_git_ref varchar(100) DEFAULT NULL,
+  culprit varchar(100) DEFAULT NULL,
+  error text,
+  steps text,
+  started_at datetime DEFAULT NULL,
+  finished_at datetime DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS bisection_status ON bisection (status);
+
+CREATE TABLE IF NOT EXISTS execution (
+  uuid varchar(100) NOT NULL PRIMARY KEY,
+  status varchar(100) DEFAULT 'created',
+  started_at datetime DEFAULT NULL,
+  finished_at datetime DEFAULT NULL,
+  source varchar(100) DEFAULT NULL,
+  git_ref varchar(100) DEFAULT NULL,
+  workload varchar(100) DEFAULT NULL,
+  pull_nb int DEFAULT 0,
+  go_version varchar(16) DEFAULT NULL,
+  profile_binary varchar(20) DEFAULT NULL,
+  profile_mode varchar(20) DEFAULT NULL,
+  failure_category varchar(50) DEFAULT NULL,
+  failure_reason text,
+  heartbeat_at datetime DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS execution_finished_at ON execution (finished_at, status);
+CREATE INDEX IF NOT EXISTS execution_started_at ON execution (started_at DESC);
+CREATE INDEX IF NOT EXISTS execution_status ON execution (status);
+CREATE INDEX IF NOT EXISTS execution_git_ref ON execution (git_ref);
+CREATE INDEX IF NOT EXISTS execution_pull_nb ON execution (pull_nb);
+CREATE INDEX IF NOT EXISTS idx_execution_on_source ON execution (source);
+CREATE INDEX IF NOT EXISTS idx_execution_on_profile_binary ON execution (profile_binary);
+CREATE INDEX IF NOT EXISTS execution_finished_at_2 ON execution (finished_at, git_ref);
+
+CREATE TABLE IF NOT EXISTS execution_queue (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  uuid varchar(100) DEFAULT NULL,
+  git_ref varchar(100) DEFAULT NULL,
+  source varchar(100) DEFAULT NULL,
+  workload varchar(100) DEFAULT NULL,
+  planner_version varchar(20) DEFAULT NULL,
+  pull_nb int DEFAULT 0,
+  pull_base_ref varchar(100) DEFAULT NULL,
+  version_major int DEFAULT 0,
+  version_minor int DEFAULT 0,
+  version_patch int DEFAULT 0,
+  profile_binary varchar(20) DEFAULT NULL,
+  profile_mode varchar(20) DEFAULT NULL,
+  compare_with text,
+  failures text,
+  retry_at datetime DEFAULT NULL,
+  notify_always tinyint(1) DEFAULT 0,
+  baseline tinyint(1) DEFAULT 0,
+  executing tinyint(1) DEFAULT 0,
+  created_at datetime DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS execution_queue_uuid ON execution_queue (uuid);
+CREATE INDEX IF NOT EXISTS execution_queue_source ON execution_queue (source);
+
+CREATE TABLE IF NOT EXISTS macrobenchmark (
+  macrobenchmark_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  "commit" varchar(100) DEFAULT NULL,
+  DateTime timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  exec_uuid varchar(100) DEFAULT NULL,
+  vtgate_planner_version varchar(20) NOT NULL DEFAULT 'V3',
+  workload varchar(100) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS macrobenchmark_exec_uuid ON macrobenchmark (exec_uuid);
+CREATE INDEX IF NOT EXISTS macrobenchmark_commit ON macrobenchmark ("commit");
+CREATE INDEX IF NOT EXISTS type_vtgate_planner_version ON macrobenchmark (workload, vtgate_planner_version);
+
+CREATE TABLE IF NOT EXISTS macrobenchmark_results (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  macrobenchmark_id int DEFAULT NULL UNIQUE,
+  tps decimal(8,2) DEFAULT NULL,
+  latency decimal(8,2) DEFAULT NULL,
+  errors decimal(8,2) DEFAULT NULL,
+  reconnects decimal(8,2) DEFAULT NULL,
+  time int DEFAULT NULL,
+  threads decimal(8,2) DEFAULT NULL,
+  total_qps decimal(8,2) DEFAULT NULL,
+  reads_qps decimal(8,2) DEFAULT NULL,
+  writes_qps decimal(8,2) DEFAULT NULL,
+  other_qps decimal(8,2) DEFAULT NULL,
+  queries int DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS metrics (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exec_uuid varchar(100) DEFAULT NULL,
+  name varchar(250) DEFAULT NULL,
+  value float DEFAULT NULL,
+  description varchar(250) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS metrics_exec_uuid ON metrics (exec_uuid);
+
+CREATE TABLE IF NOT EXISTS microbenchmark (
+  microbenchmark_no INTEGER PRIMARY KEY AUTOINCREMENT,
+  pkg_name varchar(255) DEFAULT NULL,
+  name varchar(255) DEFAULT NULL,
+  git_ref varchar(255) DEFAULT NULL,
+  exec_uuid varchar(100) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS microbenchmark_git_ref ON microbenchmark (git_ref);
+CREATE INDEX IF NOT EXISTS microbenchmark_exec_uuid ON microbenchmark (exec_uuid);
+
+CREATE TABLE IF NOT EXISTS microbenchmark_details (
+  detail_no INTEGER PRIMARY KEY AUTOINCREMENT,
+  microbenchmark_no int DEFAULT NULL,
+  name varchar(255) DEFAULT NULL,
+  bench_type varchar(255) DEFAULT NULL,
+  n int DEFAULT NULL,
+  ns_per_op decimal(22,5) DEFAULT NULL,
+  mb_per_sec decimal(22,5) DEFAULT NULL,
+  bytes_per_op decimal(22,5) DEFAULT NULL,
+  allocs_per_op decimal(22,5) DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS microbenchmark_details_microbenchmark_no ON microbenchmark_details (microbenchmark_no);
+
+CREATE TABLE IF NOT EXISTS query_plans (
+  plan_id INTEGER PRIMARY KEY AUTOINCREMENT,
+  exec_uuid varchar(100) DEFAULT NULL,
+  macrobenchmark_id int DEFAULT NULL,
+  "key" text,
+  plan text,
+  exec_count bigint DEFAULT NULL,
+  exec_time bigint DEFAULT NULL,
+  "rows" int DEFAULT NULL,
+  errors int DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS query_plans_macrobenchmark_id ON query_plans (macrobenchmark_id);
+CREATE INDEX IF NOT EXISTS query_plans_exec_uuid ON query_plans (exec_uuid);
+
+CREATE TABLE IF NOT EXISTS regression_alert (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  source varchar(100) NOT NULL,
+  workload varchar(100) NOT NULL,
+  planner_version varchar(20) NOT NULL DEFAULT '',
+  git_ref varchar(100) NOT NULL,
+  compared_git_ref varchar(100) NOT NULL,
+  created_at datetime DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (source, workload, planner_version, git_ref, compared_git_ref)
+);
+
+CREATE TABLE IF NOT EXISTS schema_version (
+  version int NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  applied_at datetime DEFAULT CURRENT_TIMESTAMP
+);
+INSERT OR IGNORE INTO schema_version (version, name) VALUES (11, 'execution_queue_baseline');
@@ -0,0 +1,232 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package sqlite implements storage.Database with a single file SQLite database,
+// which lets developers run the server locally without a MySQL server.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vitessio/arewefastyet/go/storage"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	flagSQLiteDatabase = "sqlite-database"
+
+	errorClientConnectionNotInitialized = "the client connection to the database is not initialized"
+
+	// timeFormat is the format of the datetime values written by SQLite's CURRENT_TIMESTAMP,
+	// the time arguments of the queries use it so they can be compared with these values.
+	timeFormat = "2006-01-02 15:04:05"
+
+	// busyTimeout is how long a connection waits for the lock of the database
+	// held by another connection, possibly of another process, to be released.
+	busyTimeout = 10 * time.Second
+)
+
+//go:embed schema.sql
+var schema string
+
+// Config defines the file of the SQLite database.
+type Config struct {
+	File string
+}
+
+// Client is a storage.Database using a SQLite database.
+type Client struct {
+	db *sql.DB
+}
+
+var (
+	_ storage.Database = (*Client)(nil)
+	_ storage.Tx       = (*Tx)(nil)
+)
+
+// IsValid returns true if a database file is configured.
+func (cfg *Config) IsValid() bool {
+	return cfg.File != ""
+}
+
+func (cfg *Config) AddToViper(v *viper.Viper) {
+	_ = v.UnmarshalKey(flagSQLiteDatabase, &cfg.File)
+}
+
+func (cfg *Config) AddToCommand(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&cfg.File, flagSQLiteDatabase, "", "Path to a SQLite database file used instead of PlanetScaleDB, for local development. The file is created if it does not exist.")
+	_ = viper.BindPFlag(flagSQLiteDatabase, cmd.Flags().Lookup(flagSQLiteDatabase))
+}
+
+// NewClient opens the database file, creating it if needed, and creates the tables
+// of the schema that do not exist yet.
+func (cfg *Config) NewClient() (*Client, error) {
+	if !cfg.IsValid() {
+		return nil, errors.New("no SQLite database file configured")
+	}
+	dsn := fmt.Sprintf("file:%s?_pragma=%s&_pragma=%s&_pragma=%s", cfg.File,
+		url.QueryEscape(fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds())),
+		url.QueryEscape("journal_mode(WAL)"),
+		url.QueryEscape("foreign_keys(ON)"),
+	)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create the schema of %s: %w", cfg.File, err)
+	}
+	return &Client{db: db}, nil
+}
+
+func (c *Client) Close() error {
+	if c.db == nil {
+		return errors.New(errorClientConnectionNotInitialized)
+	}
+	return c.db.Close()
+}
+
+// Ping checks that the database can be reached.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.db == nil {
+		return errors.New(errorClientConnectionNotInitialized)
+	}
+	return c.db.PingContext(ctx)
+}
+
+// Dialect returns the SQL dialect of SQLite.
+func (c *Client) Dialect() storage.Dialect {
+	return storage.DialectSQLite
+}
+
+// Write executes the given query and returns the ID of the inserted row. Like MySQL,
+// it returns zero if the query did not insert any row.
+func (c *Client) Write(query string, args ...interface{}) (int64, error) {
+	return c.WriteContext(context.Background(), query, args...)
+}
+
+func (c *Client) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	if c.db == nil {
+		return 0, errors.New(errorClientConnectionNotInitialized)
+	}
+	return write(ctx, c.db, query, args)
+}
+
+func (c *Client) Read(query string, args ...interface{}) (storage.Rows, error) {
+	return c.ReadContext(context.Background(), query, args...)
+}
+
+func (c *Client) ReadContext(ctx context.Context, query string, args ...interface{}) (storage.Rows, error) {
+	if c.db == nil {
+		return nil, errors.New(errorClientConnectionNotInitialized)
+	}
+	return c.db.QueryContext(ctx, query, convertArgs(args)...)
+}
+
+// Begin starts a transaction.
+func (c *Client) Begin(ctx context.Context) (storage.Tx, error) {
+	if c.db == nil {
+		return nil, errors.New(errorClientConnectionNotInitialized)
+	}
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx}, nil
+}
+
+// Tx is a transaction of a Client.
+type Tx struct {
+	tx *sql.Tx
+}
+
+func (t *Tx) Write(query string, args ...interface{}) (int64, error) {
+	return t.WriteContext(context.Background(), query, args...)
+}
+
+func (t *Tx) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return write(ctx, t.tx, query, args)
+}
+
+func (t *Tx) Read(query string, args ...interface{}) (storage.Rows, error) {
+	return t.ReadContext(context.Background(), query, args...)
+}
+
+func (t *Tx) ReadContext(ctx context.Context, query string, args ...interface{}) (storage.Rows, error) {
+	return t.tx.QueryContext(ctx, query, convertArgs(args)...)
+}
+
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Dialect returns the SQL dialect of SQLite.
+func (t *Tx) Dialect() storage.Dialect {
+	return storage.DialectSQLite
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func write(ctx context.Context, db execer, query string, args []interface{}) (int64, error) {
+	res, err := db.ExecContext(ctx, query, convertArgs(args)...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected == 0 {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// convertArgs formats the time arguments of a query the way SQLite stores the current
+// time, in UTC, so they can be compared with the values of the datetime columns.
+func convertArgs(args []interface{}) []interface{} {
+	res := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			res[i] = v.UTC().Format(timeFormat)
+		case sql.NullTime:
+			if v.Valid {
+				res[i] = v.Time.UTC().Format(timeFormat)
+			} else {
+				res[i] = nil
+			}
+		default:
+			res[i] = arg
+		}
+	}
+	return res
+}
@@ -0,0 +1,133 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/storage"
+)
+
+func newTestClient(c *qt.C) *Client {
+	cfg := Config{File: filepath.Join(c.TempDir(), "arewefastyet.db")}
+	client, err := cfg.NewClient()
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	c := qt.New(t)
+	file := filepath.Join(c.TempDir(), "arewefastyet.db")
+
+	_, err := (&Config{}).NewClient()
+	c.Assert(err, qt.ErrorMatches, "no SQLite database file configured")
+
+	// The schema can be applied again to an existing database.
+	for i := 0; i < 2; i++ {
+		client, err := (&Config{File: file}).NewClient()
+		c.Assert(err, qt.IsNil)
+		c.Assert(client.Ping(context.Background()), qt.IsNil)
+		c.Assert(client.Close(), qt.IsNil)
+	}
+}
+
+func TestClient_Write(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c)
+
+	_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref) VALUES(?, ?, ?, ?)", "1234", "created", "cron", "abcd")
+	c.Assert(err, qt.IsNil)
+
+	// The reserved words used as column names by the MySQL queries are accepted.
+	id, err := client.Write("INSERT INTO macrobenchmark(exec_uuid, `commit`, vtgate_planner_version, workload) VALUES(NULLIF(?, ''), ?, ?, ?)", "1234", "abcd", "Gen4", "OLTP")
+	c.Assert(err, qt.IsNil)
+	c.Assert(id, qt.Equals, int64(1))
+	_, err = client.Write("INSERT INTO query_plans(`exec_uuid`, `macrobenchmark_id`, `key`, `plan`, `exec_count`, `exec_time`, `rows`, `errors`) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		"1234", id, "select 1", "{}", 1, 2, 3, 0)
+	c.Assert(err, qt.IsNil)
+
+	// Like MySQL, nothing was inserted so the returned ID is zero.
+	id, err = client.Write("INSERT OR IGNORE INTO execution(uuid, status, source, git_ref) VALUES(?, ?, ?, ?)", "1234", "created", "cron", "abcd")
+	c.Assert(err, qt.IsNil)
+	c.Assert(id, qt.Equals, int64(0))
+}
+
+func TestClient_ReadTime(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c)
+
+	startedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	_, err := client.Write("INSERT INTO execution(uuid, status, source, git_ref, started_at) VALUES(?, ?, ?, ?, ?)", "1234", "started", "cron", "abcd", startedAt)
+	c.Assert(err, qt.IsNil)
+	_, err = client.Write("INSERT INTO execution(uuid, status, source, git_ref, started_at) VALUES(?, ?, ?, ?, CURRENT_TIMESTAMP)", "5678", "started", "cron", "abcd")
+	c.Assert(err, qt.IsNil)
+
+	// The time arguments are compared with the values of CURRENT_TIMESTAMP.
+	rows, err := client.Read("SELECT uuid, started_at FROM execution WHERE started_at < ?", time.Now().Add(-time.Hour))
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	var uuids []string
+	for rows.Next() {
+		var uuid string
+		var t time.Time
+		c.Assert(rows.Scan(&uuid, &t), qt.IsNil)
+		c.Assert(t.Equal(startedAt), qt.IsTrue, qt.Commentf("%v", t))
+		uuids = append(uuids, uuid)
+	}
+	c.Assert(rows.Err(), qt.IsNil)
+	c.Assert(uuids, qt.DeepEquals, []string{"1234"})
+}
+
+func TestClient_Begin(t *testing.T) {
+	c := qt.New(t)
+	client := newTestClient(c)
+
+	count := func() int {
+		rows, err := client.Read("SELECT COUNT(*) FROM metrics")
+		c.Assert(err, qt.IsNil)
+		defer rows.Close()
+		var n int
+		c.Assert(rows.Next(), qt.IsTrue)
+		c.Assert(rows.Scan(&n), qt.IsNil)
+		return n
+	}
+	rows := [][]interface{}{{"1234", "a", 1.0}, {"1234", "b", 2.0}}
+
+	err := storage.WithTransaction(client, func(tx storage.SQLClient) error {
+		c.Assert(storage.DialectOf(tx), qt.Equals, storage.DialectSQLite)
+		if err := storage.BulkInsert(tx, "metrics", []string{"exec_uuid", "name", "value"}, rows); err != nil {
+			return err
+		}
+		return errors.New("failure")
+	})
+	c.Assert(err, qt.ErrorMatches, "failure")
+	c.Assert(count(), qt.Equals, 0)
+
+	err = storage.WithTransaction(client, func(tx storage.SQLClient) error {
+		return storage.BulkInsert(tx, "metrics", []string{"exec_uuid", "name", "value"}, rows)
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(count(), qt.Equals, 2)
+}
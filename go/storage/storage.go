@@ -18,9 +18,48 @@
 
 package storage
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
+
+// Rows are the rows returned by a query, they must be closed once read. *sql.Rows implements it.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Columns() ([]string, error)
+	ColumnTypes() ([]*sql.ColumnType, error)
+	Err() error
+	Close() error
+}
 
 type SQLClient interface {
 	Write(query string, args ...interface{}) (int64, error)
-	Read(query string, args ...interface{}) (*sql.Rows, error)
+	Read(query string, args ...interface{}) (Rows, error)
+
+	// WriteContext and ReadContext are like Write and Read, but they give up when the
+	// given context is done. See WithContext to use a context with Write and Read.
+	WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error)
+	ReadContext(ctx context.Context, query string, args ...interface{}) (Rows, error)
+}
+
+// Database is a SQLClient backed by a pool of connections to a database.
+type Database interface {
+	SQLClient
+	Ping(ctx context.Context) error
+	Close() error
+	Dialect() Dialect
+
+	// Begin starts a transaction that is rolled back if the context is done before
+	// it is committed, see WithTransaction.
+	Begin(ctx context.Context) (Tx, error)
+}
+
+// Tx is a transaction of a Database. Its writes are only visible to the other clients
+// once it is committed, and are all discarded if it is rolled back.
+type Tx interface {
+	SQLClient
+	Commit() error
+	Rollback() error
+	Dialect() Dialect
 }
@@ -0,0 +1,83 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+// ExecutionTable is a table holding rows of the executions.
+type ExecutionTable struct {
+	Name    string
+	Columns []string
+
+	// Key is the auto-increment primary key of the table, it is empty for the execution table.
+	Key string
+
+	// Refs maps the columns holding the key of another table to the name of that table.
+	Refs map[string]string
+
+	// Where selects the rows of an execution, its UUID is the only argument.
+	Where string
+}
+
+// ExecutionTables lists the tables holding the rows of the executions. The tables referenced
+// by another table come before it: rows are inserted in this order and deleted in reverse order.
+var ExecutionTables = []ExecutionTable{
+	{
+		Name:    "execution",
+		Columns: []string{"uuid", "status", "started_at", "finished_at", "source", "git_ref", "workload", "pull_nb", "go_version", "profile_binary", "profile_mode", "failure_category", "failure_reason", "heartbeat_at"},
+		Where:   "uuid = ?",
+	},
+	{
+		Name:    "macrobenchmark",
+		Columns: []string{"macrobenchmark_id", "commit", "DateTime", "exec_uuid", "vtgate_planner_version", "workload"},
+		Key:     "macrobenchmark_id",
+		Where:   "exec_uuid = ?",
+	},
+	{
+		Name:    "macrobenchmark_results",
+		Columns: []string{"id", "macrobenchmark_id", "tps", "latency", "errors", "reconnects", "time", "threads", "total_qps", "reads_qps", "writes_qps", "other_qps", "queries"},
+		Key:     "id",
+		Refs:    map[string]string{"macrobenchmark_id": "macrobenchmark"},
+		Where:   "macrobenchmark_id IN (SELECT macrobenchmark_id FROM macrobenchmark WHERE exec_uuid = ?)",
+	},
+	{
+		Name:    "query_plans",
+		Columns: []string{"plan_id", "exec_uuid", "macrobenchmark_id", "key", "plan", "exec_count", "exec_time", "rows", "errors"},
+		Key:     "plan_id",
+		Refs:    map[string]string{"macrobenchmark_id": "macrobenchmark"},
+		Where:   "exec_uuid = ?",
+	},
+	{
+		Name:    "metrics",
+		Columns: []string{"id", "exec_uuid", "name", "value", "description"},
+		Key:     "id",
+		Where:   "exec_uuid = ?",
+	},
+	{
+		Name:    "microbenchmark",
+		Columns: []string{"microbenchmark_no", "pkg_name", "name", "git_ref", "exec_uuid"},
+		Key:     "microbenchmark_no",
+		Where:   "exec_uuid = ?",
+	},
+	{
+		Name:    "microbenchmark_details",
+		Columns: []string{"detail_no", "microbenchmark_no", "name", "bench_type", "n", "ns_per_op", "mb_per_sec", "bytes_per_op", "allocs_per_op"},
+		Key:     "detail_no",
+		Refs:    map[string]string{"microbenchmark_no": "microbenchmark"},
+		Where:   "microbenchmark_no IN (SELECT microbenchmark_no FROM microbenchmark WHERE exec_uuid = ?)",
+	},
+}
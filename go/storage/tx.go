@@ -0,0 +1,103 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// bulkInsertBatchSize is the maximum number of rows inserted by a single statement of
+// BulkInsert, it keeps the number of placeholders of a statement under the limits of
+// MySQL and SQLite.
+const bulkInsertBatchSize = 500
+
+// WithTransaction calls fn with a transaction of the given client, which is committed if fn
+// succeeds and rolled back otherwise, in which case none of the writes of fn are kept. fn is
+// called with the client itself if the client does not support transactions. The transaction
+// uses the context of a client returned by WithContext.
+func WithTransaction(client SQLClient, fn func(tx SQLClient) error) (err error) {
+	ctx, db := context.Background(), client
+	if c, ok := client.(*contextClient); ok {
+		ctx, db = c.ctx, c.client
+	}
+	beginner, ok := db.(interface {
+		Begin(ctx context.Context) (Tx, error)
+	})
+	if !ok {
+		return fn(client)
+	}
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return multierr.Append(err, fmt.Errorf("rollback: %w", rollbackErr))
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// BulkInsert inserts the given rows in the columns of the table with multi-row INSERT
+// statements, instead of sending one statement per row. Each row has one value per column.
+// The rows are inserted in batches, to insert all or none of them the client must be a Tx.
+func BulkInsert(client SQLClient, table string, columns []string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, "`"+column+"`")
+	}
+	prefix := "INSERT INTO " + table + "(" + strings.Join(quoted, ", ") + ") VALUES "
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	for start := 0; start < len(rows); start += bulkInsertBatchSize {
+		end := min(start+bulkInsertBatchSize, len(rows))
+		var query strings.Builder
+		query.WriteString(prefix)
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for i, row := range rows[start:end] {
+			if len(row) != len(columns) {
+				return fmt.Errorf("row %d of %s has %d values for %d columns", start+i, table, len(row), len(columns))
+			}
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteString(placeholders)
+			args = append(args, row...)
+		}
+		if _, err := client.Write(query.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
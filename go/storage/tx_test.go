@@ -0,0 +1,147 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type fakeClient struct {
+	queries  []string
+	args     [][]interface{}
+	contexts []context.Context
+	failures int
+}
+
+func (f *fakeClient) Write(query string, args ...interface{}) (int64, error) {
+	if f.failures > 0 {
+		f.failures--
+		return 0, errors.New("write failed")
+	}
+	f.queries = append(f.queries, query)
+	f.args = append(f.args, args)
+	return int64(len(f.queries)), nil
+}
+
+func (f *fakeClient) Read(string, ...interface{}) (Rows, error) {
+	return nil, nil
+}
+
+func (f *fakeClient) WriteContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	f.contexts = append(f.contexts, ctx)
+	return f.Write(query, args...)
+}
+
+func (f *fakeClient) ReadContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	f.contexts = append(f.contexts, ctx)
+	return f.Read(query, args...)
+}
+
+type fakeTx struct {
+	fakeClient
+	committed, rolledBack bool
+}
+
+func (f *fakeTx) Commit() error    { f.committed = true; return nil }
+func (f *fakeTx) Rollback() error  { f.rolledBack = true; return nil }
+func (f *fakeTx) Dialect() Dialect { return DialectMySQL }
+
+type fakeDatabase struct {
+	fakeClient
+	tx    *fakeTx
+	txCtx context.Context
+}
+
+func (f *fakeDatabase) Begin(ctx context.Context) (Tx, error) {
+	f.tx = &fakeTx{}
+	f.txCtx = ctx
+	return f.tx, nil
+}
+
+func TestWithTransaction(t *testing.T) {
+	c := qt.New(t)
+
+	db := &fakeDatabase{}
+	err := WithTransaction(db, func(tx SQLClient) error {
+		_, err := tx.Write("INSERT INTO a VALUES(1)")
+		return err
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(db.queries, qt.HasLen, 0)
+	c.Assert(db.tx.queries, qt.DeepEquals, []string{"INSERT INTO a VALUES(1)"})
+	c.Assert(db.tx.committed, qt.IsTrue)
+
+	err = WithTransaction(db, func(tx SQLClient) error {
+		return errors.New("failure")
+	})
+	c.Assert(err, qt.ErrorMatches, "failure")
+	c.Assert(db.tx.committed, qt.IsFalse)
+	c.Assert(db.tx.rolledBack, qt.IsTrue)
+
+	c.Assert(func() {
+		_ = WithTransaction(db, func(tx SQLClient) error { panic("boom") })
+	}, qt.PanicMatches, "boom")
+	c.Assert(db.tx.rolledBack, qt.IsTrue)
+
+	// The transactions started with a client bound to a context use that context.
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	err = WithTransaction(WithContext(ctx, db), func(tx SQLClient) error { return nil })
+	c.Assert(err, qt.IsNil)
+	c.Assert(db.txCtx, qt.Equals, ctx)
+
+	// The clients that do not support transactions, including the transactions
+	// themselves, are used as is.
+	client := &fakeClient{}
+	err = WithTransaction(client, func(tx SQLClient) error {
+		c.Assert(tx, qt.Equals, SQLClient(client))
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+}
+
+func TestBulkInsert(t *testing.T) {
+	c := qt.New(t)
+	client := &fakeClient{}
+
+	c.Assert(BulkInsert(client, "t", []string{"a", "b"}, nil), qt.IsNil)
+	c.Assert(client.queries, qt.HasLen, 0)
+
+	rows := make([][]interface{}, bulkInsertBatchSize+1)
+	for i := range rows {
+		rows[i] = []interface{}{i, "b"}
+	}
+	c.Assert(BulkInsert(client, "t", []string{"a", "b"}, rows), qt.IsNil)
+	c.Assert(client.queries, qt.HasLen, 2)
+	c.Assert(strings.HasPrefix(client.queries[0], "INSERT INTO t(`a`, `b`) VALUES (?, ?), (?, ?), "), qt.IsTrue)
+	c.Assert(client.args[0], qt.HasLen, 2*bulkInsertBatchSize)
+	c.Assert(client.queries[1], qt.Equals, "INSERT INTO t(`a`, `b`) VALUES (?, ?)")
+	c.Assert(client.args[1], qt.DeepEquals, []interface{}{bulkInsertBatchSize, "b"})
+
+	err := BulkInsert(client, "t", []string{"a", "b"}, [][]interface{}{{1, 2}, {3}})
+	c.Assert(err, qt.ErrorMatches, "row 1 of t has 1 values for 2 columns")
+
+	client.failures = 1
+	c.Assert(BulkInsert(client, "t", []string{"a"}, [][]interface{}{{1}}), qt.ErrorMatches, "write failed")
+}
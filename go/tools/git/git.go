@@ -330,6 +330,17 @@ func GetBranchesForCommit(repoDir, sha string) (branches []string, err error) {
 	return
 }
 
+// GetCommitsBetween returns the commits that are reachable from newSHA but not from oldSHA,
+// following only the first parent of merge commits. The commits are ordered from the oldest
+// to the newest, the last commit being newSHA.
+func GetCommitsBetween(repoDir, oldSHA, newSHA string) ([]string, error) {
+	out, err := ExecCmd(repoDir, "git", "rev-list", "--reverse", "--first-parent", oldSHA+".."+newSHA)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
 // ShortenSHA will return the first 7 characters of a SHA.
 // If the given SHA is too short, it will be returned untouched.
 func ShortenSHA(sha string) string {
@@ -391,3 +402,12 @@ func GetVersionForCommitSHA(repoDir, sha string) (Version, error) {
 	}
 	return Version{}, fmt.Errorf("release not found for %s", sha)
 }
+
+// GetCommitHashOfBranch returns the commit hash of the head of the given remote branch.
+func GetCommitHashOfBranch(repoDir, branch string) (string, error) {
+	out, err := ExecCmd(repoDir, "git", "rev-parse", "origin/"+branch)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -47,6 +48,31 @@ func TestShortenSHA(t *testing.T) {
 	}
 }
 
+func TestGetCommitsBetween(t *testing.T) {
+	c := qt.New(t)
+
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		out, err := ExecCmd(repoDir, "git", args...)
+		c.Assert(err, qt.IsNil)
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "--quiet")
+	var commits []string
+	for i := 0; i < 4; i++ {
+		run("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+		commits = append(commits, run("rev-parse", "HEAD"))
+	}
+
+	got, err := GetCommitsBetween(repoDir, commits[0], commits[3])
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, commits[1:])
+
+	got, err = GetCommitsBetween(repoDir, commits[3], commits[3])
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.HasLen, 0)
+}
+
 func TestGetAllVitessReleaseCommitHashOrdering(t *testing.T) {
 	tmpDir, vitessPath, err := createTemporaryVitessClone()
 	defer os.RemoveAll(tmpDir)
@@ -0,0 +1,64 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package github
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+)
+
+// UpsertPullRequestComment comments on the given pull request of vitessio/vitess. The marker
+// is added at the top of the comment and is used to find the comment in later calls: if a
+// comment made by a bot already contains the marker, it is edited instead of creating a new one.
+// The marker should be an HTML comment so that it is not rendered, i.e "<!-- my-marker -->".
+func (a *App) UpsertPullRequestComment(ctx context.Context, prNumber int, marker, body string) error {
+	body = marker + "\n" + body
+
+	existing, err := a.findPullRequestComment(ctx, prNumber, marker)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		_, _, err = a.client.Issues.EditComment(ctx, "vitessio", "vitess", existing.GetID(), &github.IssueComment{Body: &body})
+		return err
+	}
+	_, _, err = a.client.Issues.CreateComment(ctx, "vitessio", "vitess", prNumber, &github.IssueComment{Body: &body})
+	return err
+}
+
+func (a *App) findPullRequestComment(ctx context.Context, prNumber int, marker string) (*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := a.client.Issues.ListComments(ctx, "vitessio", "vitess", prNumber, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if comment.GetUser().GetType() == "Bot" && strings.Contains(comment.GetBody(), marker) {
+				return comment, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/go-github/v63/github"
+)
+
+func TestApp_UpsertPullRequestComment(t *testing.T) {
+	const marker = "<!-- test-marker -->"
+
+	tests := []struct {
+		name         string
+		comments     string
+		wantMethod   string
+		wantEndpoint string
+	}{
+		{
+			name:         "No existing comment",
+			comments:     `[{"id": 1, "body": "LGTM", "user": {"type": "User"}}]`,
+			wantMethod:   http.MethodPost,
+			wantEndpoint: "/repos/vitessio/vitess/issues/42/comments",
+		},
+		{
+			name:         "Marker copied by a user",
+			comments:     `[{"id": 1, "body": "<!-- test-marker --> old", "user": {"type": "User"}}]`,
+			wantMethod:   http.MethodPost,
+			wantEndpoint: "/repos/vitessio/vitess/issues/42/comments",
+		},
+		{
+			name:         "Existing comment from the bot",
+			comments:     `[{"id": 1, "body": "LGTM", "user": {"type": "User"}}, {"id": 7, "body": "<!-- test-marker -->\nold", "user": {"type": "Bot"}}]`,
+			wantMethod:   http.MethodPatch,
+			wantEndpoint: "/repos/vitessio/vitess/issues/comments/7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			var gotMethod, gotEndpoint, gotBody string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(tt.comments))
+					return
+				}
+				var comment github.IssueComment
+				c.Check(json.NewDecoder(r.Body).Decode(&comment), qt.IsNil)
+				gotMethod, gotEndpoint, gotBody = r.Method, r.URL.Path, comment.GetBody()
+				_, _ = w.Write([]byte(`{"id": 7}`))
+			}))
+			defer srv.Close()
+
+			client := github.NewClient(nil)
+			client.BaseURL, _ = url.Parse(srv.URL + "/")
+			a := &App{client: client}
+
+			err := a.UpsertPullRequestComment(context.Background(), 42, marker, "new")
+			c.Assert(err, qt.IsNil)
+			c.Assert(gotMethod, qt.Equals, tt.wantMethod)
+			c.Assert(gotEndpoint, qt.Equals, tt.wantEndpoint)
+			c.Assert(gotBody, qt.Equals, marker+"\nnew")
+		})
+	}
+}
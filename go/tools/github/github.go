@@ -20,6 +20,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"os"
 	"time"
 
@@ -55,9 +56,9 @@ const (
 // AddToCommand adds the GitHub App flags to Cobra
 func (a *App) AddToCommand(cmd *cobra.Command) {
 	cmd.Flags().IntVar(&a.appID, flagAppID, 0, "ID of the GitHub App")
-	cmd.Flags().StringVar(&a.webHookSecret, flagWebHookSecret, "", "Secrets used to verify the webhooks")
+	cmd.Flags().StringVar(&a.webHookSecret, flagWebHookSecret, "", "Secret used to verify the webhooks sent by GitHub. Webhooks are only received when it is set.")
 	cmd.Flags().StringVar(&a.secretKey, flagSecretKey, "", "Secret key used to authenticate")
-	cmd.Flags().StringVar(&a.port, flagPort, "8181", "Port on which to run the github app")
+	cmd.Flags().StringVar(&a.port, flagPort, "8181", "Port on which the GitHub webhooks are received.")
 	cmd.Flags().IntVar(&a.installationID, flagInstallationID, 0, "GitHub installation ID of this app")
 
 	_ = viper.BindPFlag(flagAppID, cmd.Flags().Lookup(flagAppID))
@@ -112,6 +113,16 @@ func (a *App) Init() error {
 	return nil
 }
 
+// CheckAuthentication checks that the App is authenticated as its installation. It queries
+// the rate limits of the installation, which does not count against these rate limits.
+func (a *App) CheckAuthentication(ctx context.Context) error {
+	if a.client == nil {
+		return errors.New("the GitHub App is not initialized")
+	}
+	_, _, err := a.client.RateLimit.Get(ctx)
+	return err
+}
+
 type PRInfo struct {
 	ID         int
 	Author     string
@@ -0,0 +1,132 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v63/github"
+)
+
+const (
+	PullRequestActionLabeled     = "labeled"
+	PullRequestActionUnlabeled   = "unlabeled"
+	PullRequestActionSynchronize = "synchronize"
+	PullRequestActionClosed      = "closed"
+)
+
+var (
+	// ErrWebhookNotConfigured is returned when a webhook is received but no webhook
+	// secret is configured. We refuse every webhook in that case as we cannot verify them.
+	ErrWebhookNotConfigured = errors.New("no GitHub webhook secret is configured")
+
+	// ErrInvalidWebhookSignature is returned when the signature of a webhook does not
+	// match its payload.
+	ErrInvalidWebhookSignature = errors.New("invalid GitHub webhook signature")
+)
+
+// PullRequestEvent contains the information of a pull_request webhook event that
+// are needed to schedule benchmarks.
+type PullRequestEvent struct {
+	Action string
+
+	// Repo is the full name of the repository, i.e "vitessio/vitess".
+	Repo   string
+	Number int
+
+	// Label is the label that was added or removed, it is only set
+	// for the "labeled" and "unlabeled" actions.
+	Label string
+
+	// Labels are all the labels of the pull request after the event.
+	Labels []string
+
+	HeadSHA string
+	BaseSHA string
+}
+
+// HasLabel returns true if the pull request has the given label after the event.
+func (e PullRequestEvent) HasLabel(label string) bool {
+	for _, l := range e.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEnabled returns true if the App is configured to receive webhooks.
+func (a *App) WebhookEnabled() bool {
+	return a.webHookSecret != ""
+}
+
+// Port returns the port on which the App receives webhooks.
+func (a *App) Port() string {
+	return a.port
+}
+
+// ParsePullRequestWebhook verifies the signature of the webhook using the App's
+// webhook secret and parses it, see ParsePullRequestWebhook.
+func (a *App) ParsePullRequestWebhook(r *http.Request) (*PullRequestEvent, error) {
+	return ParsePullRequestWebhook(r, []byte(a.webHookSecret))
+}
+
+// ParsePullRequestWebhook verifies the signature of the webhook with the given secret
+// and parses it. A nil event and a nil error are returned if the webhook is valid but
+// is not a pull_request event.
+func ParsePullRequestWebhook(r *http.Request, secret []byte) (*PullRequestEvent, error) {
+	if len(secret) == 0 {
+		return nil, ErrWebhookNotConfigured
+	}
+	payload, err := github.ValidatePayload(r, secret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	if github.WebHookType(r) != "pull_request" {
+		return nil, nil
+	}
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		return nil, err
+	}
+	prEvent, ok := event.(*github.PullRequestEvent)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payload type %T for a pull_request event", event)
+	}
+
+	pr := prEvent.GetPullRequest()
+	res := &PullRequestEvent{
+		Action:  prEvent.GetAction(),
+		Repo:    prEvent.GetRepo().GetFullName(),
+		Number:  prEvent.GetNumber(),
+		Label:   prEvent.GetLabel().GetName(),
+		HeadSHA: pr.GetHead().GetSHA(),
+		BaseSHA: pr.GetBase().GetSHA(),
+	}
+	if res.Number == 0 {
+		res.Number = pr.GetNumber()
+	}
+	for _, label := range pr.Labels {
+		res.Labels = append(res.Labels, label.GetName())
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const testPullRequestPayload = `{
+	"action": "labeled",
+	"number": 42,
+	"label": {"name": "Benchmark me"},
+	"repository": {"full_name": "vitessio/vitess"},
+	"pull_request": {
+		"number": 42,
+		"labels": [{"name": "Benchmark me"}, {"name": "Type: Bug"}],
+		"head": {"sha": "head-sha"},
+		"base": {"sha": "base-sha"}
+	}
+}`
+
+func newWebhookRequest(event, payload, secret string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", event)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestParsePullRequestWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *http.Request
+		secret  string
+		want    *PullRequestEvent
+		wantErr error
+	}{
+		{
+			name:    "Valid pull request event",
+			request: newWebhookRequest("pull_request", testPullRequestPayload, "secret"),
+			secret:  "secret",
+			want: &PullRequestEvent{
+				Action:  PullRequestActionLabeled,
+				Repo:    "vitessio/vitess",
+				Number:  42,
+				Label:   "Benchmark me",
+				Labels:  []string{"Benchmark me", "Type: Bug"},
+				HeadSHA: "head-sha",
+				BaseSHA: "base-sha",
+			},
+		},
+		{
+			name:    "Other event",
+			request: newWebhookRequest("ping", `{"zen": "Keep it logically awesome."}`, "secret"),
+			secret:  "secret",
+		},
+		{
+			name:    "Wrong secret",
+			request: newWebhookRequest("pull_request", testPullRequestPayload, "other"),
+			secret:  "secret",
+			wantErr: ErrInvalidWebhookSignature,
+		},
+		{
+			name:    "No secret configured",
+			request: newWebhookRequest("pull_request", testPullRequestPayload, ""),
+			wantErr: ErrWebhookNotConfigured,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			got, err := ParsePullRequestWebhook(tt.request, []byte(tt.secret))
+			if tt.wantErr != nil {
+				c.Assert(err, qt.ErrorIs, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
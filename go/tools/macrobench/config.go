@@ -22,8 +22,8 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/vitessio/arewefastyet/go/exec/metrics"
 	"github.com/vitessio/arewefastyet/go/storage"
-	"github.com/vitessio/arewefastyet/go/storage/influxdb"
 	"github.com/vitessio/arewefastyet/go/storage/psdb"
 
 	"github.com/spf13/cobra"
@@ -45,10 +45,10 @@ type Config struct {
 	// not be saved to a database, though the program won't fail.
 	DatabaseConfig *psdb.Config
 
-	// MetricsDatabaseConfig points to the required configuration to create
-	// a *influxdb.Client. If no configuration is provided results will not be
-	// saved to the database and the program will not fail.
-	MetricsDatabaseConfig *influxdb.Config
+	// MetricsConfig points to the configuration used to create the
+	// metrics.MetricsSource of the execution. If no configuration is provided the
+	// metrics of the execution are not fetched and the program will not fail.
+	MetricsConfig *metrics.Config
 
 	// M contains all metadata used to parameter sysbench execution.
 	// This key value map stores the value of each CLI parameters.
@@ -103,7 +103,7 @@ const (
 // the given *cobra.Command.
 func (mabcfg *Config) AddToCommand(cmd *cobra.Command) {
 	mabcfg.DatabaseConfig.AddToCommand(cmd)
-	mabcfg.MetricsDatabaseConfig.AddToCommand(cmd)
+	mabcfg.MetricsConfig.AddToCommand(cmd)
 
 	cmd.Flags().StringVar(&mabcfg.WorkloadPath, flagSysbenchPath, "", "Path to the workload used by sysbench.")
 	cmd.Flags().StringVar(&mabcfg.SysbenchExec, flagSysbenchExecutable, "", "Path to the sysbench binary.")
@@ -142,7 +142,7 @@ func (mabcfg Config) insertBenchmarkToSQL(client storage.SQLClient) (newMacroBen
 	if client == nil {
 		return 0, errors.New(mysql.ErrorClientConnectionNotInitialized)
 	}
-	query := "INSERT INTO macrobenchmark(exec_uuid, commit, vtgate_planner_version, workload) VALUES(NULLIF(?, ''), ?, ?, ?)"
+	query := "INSERT INTO macrobenchmark(exec_uuid, `commit`, vtgate_planner_version, workload) VALUES(NULLIF(?, ''), ?, ?, ?)"
 	res, err := client.Write(query, mabcfg.execUUID, mabcfg.GitRef, mabcfg.VtgatePlannerVersion, mabcfg.Workload.ToUpper().String())
 	if err != nil {
 		return 0, err
@@ -27,7 +27,7 @@ import (
 	"strings"
 
 	"github.com/vitessio/arewefastyet/go/exec/metrics"
-	"github.com/vitessio/arewefastyet/go/storage/influxdb"
+	"github.com/vitessio/arewefastyet/go/storage"
 	"github.com/vitessio/arewefastyet/go/storage/psdb"
 )
 
@@ -98,19 +98,13 @@ func Run(mabcfg Config) error {
 	}
 	defer sqlClient.Close()
 
-	// get metrics database client
-	metricsClient, err := createMetricsDatabaseClient(mabcfg.MetricsDatabaseConfig)
+	// get metrics source
+	metricsSource, err := createMetricsSource(mabcfg.MetricsConfig)
 	if err != nil {
 		return err
 	}
-
-	// Create new macro benchmark in MySQL
-	var macrobenchID int
-	if sqlClient != nil {
-		macrobenchID, err = mabcfg.insertBenchmarkToSQL(sqlClient)
-		if err != nil {
-			return err
-		}
+	if metricsSource != nil {
+		defer metricsSource.Close()
 	}
 
 	// Prepare
@@ -137,27 +131,60 @@ func Run(mabcfg Config) error {
 		}
 	}
 
-	err = handleResults(mabcfg, resStr, sqlClient, metricsClient, macrobenchID)
+	err = handleResults(mabcfg, resStr, sqlClient, metricsSource)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func handleResults(mabcfg Config, resStr []byte, sqlClient *psdb.Client, metricsClient *influxdb.Client, macrobenchID int) error {
-	sysbenchResults, err := handleSysBenchResults(resStr, sqlClient, macrobenchID)
+// handleResults gathers the results of the macro benchmark, its metrics and the query
+// plans of VTGate, and saves them in a single transaction so that a failure does not
+// leave the results of the benchmark partially written. No metrics are saved if there
+// is no metrics source.
+func handleResults(mabcfg Config, resStr []byte, sqlClient *psdb.Client, metricsSource metrics.MetricsSource) error {
+	sysbenchResults, err := parseSysBenchResults(resStr)
+	if err != nil {
+		return err
+	}
+	var execMetrics *metrics.ExecutionMetrics
+	if metricsSource != nil {
+		m, err := metrics.GetExecutionMetrics(metricsSource, mabcfg.execUUID, sysbenchResults.Queries)
+		if err != nil {
+			return err
+		}
+		execMetrics = &m
+	}
+	plans, err := getVTGatesQueryPlans(mabcfg.vtgateWebPorts)
 	if err != nil {
 		return err
 	}
-	err = handleMetricsResults(metricsClient, sqlClient, mabcfg.execUUID, sysbenchResults.Queries)
+	if sqlClient == nil {
+		return nil
+	}
+	return storage.WithTransaction(sqlClient, func(tx storage.SQLClient) error {
+		return insertResults(tx, mabcfg, sysbenchResults, execMetrics, plans)
+	})
+}
+
+// insertResults creates the macro benchmark in the database and inserts its results.
+// The metrics are not inserted if execMetrics is nil.
+func insertResults(client storage.SQLClient, mabcfg Config, sysbenchResults sysbenchResult, execMetrics *metrics.ExecutionMetrics, plans VTGateQueryPlanMap) error {
+	macrobenchID, err := mabcfg.insertBenchmarkToSQL(client)
 	if err != nil {
 		return err
 	}
-	err = handleVTGateResults(mabcfg.vtgateWebPorts, sqlClient, mabcfg.execUUID, macrobenchID)
+	err = sysbenchResults.insertToMySQL(macrobenchID, client)
 	if err != nil {
 		return err
 	}
-	return nil
+	if execMetrics != nil {
+		err = metrics.InsertExecutionMetrics(client, mabcfg.execUUID, *execMetrics)
+		if err != nil {
+			return err
+		}
+	}
+	return insertVTGateQueryMapToMySQL(client, mabcfg.execUUID, plans, macrobenchID)
 }
 
 func createSQLClient(dbConfig *psdb.Config) (client *psdb.Client, err error) {
@@ -170,9 +197,9 @@ func createSQLClient(dbConfig *psdb.Config) (client *psdb.Client, err error) {
 	return
 }
 
-func createMetricsDatabaseClient(dbConfig *influxdb.Config) (client *influxdb.Client, err error) {
-	if dbConfig != nil && dbConfig.IsValid() {
-		client, err = dbConfig.NewClient()
+func createMetricsSource(metricsConfig *metrics.Config) (source metrics.MetricsSource, err error) {
+	if metricsConfig != nil && metricsConfig.IsValid() {
+		source, err = metricsConfig.NewSource()
 		if err != nil {
 			return
 		}
@@ -180,27 +207,7 @@ func createMetricsDatabaseClient(dbConfig *influxdb.Config) (client *influxdb.Cl
 	return
 }
 
-func handleVTGateResults(ports []string, sqlClient *psdb.Client, execUUID string, macrobenchID int) error {
-	plans, err := getVTGatesQueryPlans(ports)
-	if err != nil {
-		return err
-	}
-	return insertVTGateQueryMapToMySQL(sqlClient, execUUID, plans, macrobenchID)
-}
-
-func handleMetricsResults(client *influxdb.Client, sqlClient *psdb.Client, execUUID string, queries int) error {
-	execMetrics, err := metrics.GetExecutionMetrics(*client, execUUID, queries)
-	if err != nil {
-		return err
-	}
-	err = metrics.InsertExecutionMetrics(sqlClient, execUUID, execMetrics)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func handleSysBenchResults(resStr []byte, sqlClient *psdb.Client, macrobenchID int) (sysbenchResult, error) {
+func parseSysBenchResults(resStr []byte) (sysbenchResult, error) {
 	// Parse results
 	var results []sysbenchResult
 	err := json.Unmarshal(resStr, &results)
@@ -210,13 +217,5 @@ func handleSysBenchResults(resStr []byte, sqlClient *psdb.Client, macrobenchID i
 	if len(results) == 0 {
 		return sysbenchResult{}, errors.New(ErrorNoSysBenchResult)
 	}
-
-	// Save results
-	if sqlClient != nil {
-		err = results[0].insertToMySQL(macrobenchID, sqlClient)
-		if err != nil {
-			return sysbenchResult{}, err
-		}
-	}
 	return results[0], nil
 }
@@ -19,9 +19,14 @@
 package macrobench
 
 import (
-	qt "github.com/frankban/quicktest"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vitessio/arewefastyet/go/exec/metrics"
+	"github.com/vitessio/arewefastyet/go/storage"
+	"github.com/vitessio/arewefastyet/go/storage/sqlite"
 )
 
 func TestBuildSysbenchArgString(t *testing.T) {
@@ -51,3 +56,58 @@ func TestBuildSysbenchArgString(t *testing.T) {
 		})
 	}
 }
+
+func TestInsertResults(t *testing.T) {
+	c := qt.New(t)
+	client, err := (&sqlite.Config{File: filepath.Join(t.TempDir(), "arewefastyet.db")}).NewClient()
+	c.Assert(err, qt.IsNil)
+	defer client.Close()
+
+	count := func(table string) int {
+		rows, err := client.Read("SELECT COUNT(*) FROM " + table)
+		c.Assert(err, qt.IsNil)
+		defer rows.Close()
+		var n int
+		c.Assert(rows.Next(), qt.IsTrue)
+		c.Assert(rows.Scan(&n), qt.IsNil)
+		return n
+	}
+
+	mabcfg := Config{GitRef: "abcd", VtgatePlannerVersion: string(Gen4Planner), Workload: "oltp", execUUID: "1234"}
+	result := sysbenchResult{Queries: 10, TPS: 1.5, QPS: sysbenchQPS{Total: 100}}
+	execMetrics := metrics.ExecutionMetrics{
+		TotalComponentsCPUTime: 1,
+		ComponentsCPUTime:      map[string]float64{"vtgate": 0.5, "vttablet": 0.5},
+	}
+	plans := VTGateQueryPlanMap{
+		"select 1":         {Instructions: "Route", ExecCount: 10},
+		"select * from t1": {Instructions: "Route", ExecCount: 5},
+	}
+
+	insert := func() error {
+		return storage.WithTransaction(client, func(tx storage.SQLClient) error {
+			return insertResults(tx, mabcfg, result, &execMetrics, plans)
+		})
+	}
+	c.Assert(insert(), qt.IsNil)
+	c.Assert(count("macrobenchmark"), qt.Equals, 1)
+	c.Assert(count("macrobenchmark_results"), qt.Equals, 1)
+	c.Assert(count("metrics"), qt.Equals, 4)
+	c.Assert(count("query_plans"), qt.Equals, 2)
+
+	// Without a metrics source, no metrics are saved.
+	c.Assert(storage.WithTransaction(client, func(tx storage.SQLClient) error {
+		return insertResults(tx, mabcfg, result, nil, plans)
+	}), qt.IsNil)
+	c.Assert(count("macrobenchmark"), qt.Equals, 2)
+	c.Assert(count("metrics"), qt.Equals, 4)
+	c.Assert(count("query_plans"), qt.Equals, 4)
+
+	// Nothing is saved when one of the inserts fails.
+	_, err = client.Write("DROP TABLE query_plans")
+	c.Assert(err, qt.IsNil)
+	c.Assert(insert(), qt.ErrorMatches, ".*no such table: query_plans.*")
+	c.Assert(count("macrobenchmark"), qt.Equals, 2)
+	c.Assert(count("macrobenchmark_results"), qt.Equals, 2)
+	c.Assert(count("metrics"), qt.Equals, 4)
+}
@@ -131,6 +131,7 @@ func getExecutionGroupResults(workload string, ref string, planner PlannerVersio
 }
 
 func getExecutionGroupResultsFromLast30Days(workload string, planner PlannerVersion, client storage.SQLClient) ([]executionGroupResults, error) {
+	dialect := storage.DialectOf(client)
 	query := `
         SELECT 
             IFNULL(e.uuid, '') AS exec_uuid, 
@@ -156,7 +157,7 @@ func getExecutionGroupResultsFromLast30Days(workload string, planner PlannerVers
         LEFT JOIN 
             metrics AS m ON e.uuid = m.exec_uuid
         WHERE 
-            e.finished_at BETWEEN DATE(NOW()) - INTERVAL 30 DAY AND DATE(NOW() + INTERVAL 1 DAY)
+            e.finished_at BETWEEN ` + dialect.DaysAgo(30) + ` AND ` + dialect.DaysAgo(-1) + `
             AND e.profile_binary IS NULL
             AND e.source = 'cron'
             AND e.status = 'finished'
@@ -253,6 +254,7 @@ func getExecutionGroupResultsFromLast30Days(workload string, planner PlannerVers
 }
 
 func getSummaryLast30Days(workload string, planner PlannerVersion, client storage.SQLClient) ([]executionGroupResults, error) {
+	dialect := storage.DialectOf(client)
 	query := `
         SELECT 
             e.git_ref, 
@@ -264,7 +266,7 @@ func getSummaryLast30Days(workload string, planner PlannerVersion, client storag
         JOIN 
             macrobenchmark_results AS results ON info.macrobenchmark_id = results.macrobenchmark_id
         WHERE 
-            e.finished_at BETWEEN DATE(NOW()) - INTERVAL 30 DAY AND DATE(NOW() + INTERVAL 1 DAY)
+            e.finished_at BETWEEN ` + dialect.DaysAgo(30) + ` AND ` + dialect.DaysAgo(-1) + `
             AND e.profile_binary IS NULL
             AND e.status = "finished" 
             AND e.source = "cron" 
@@ -17,6 +17,7 @@ limitations under the License.
 package macrobench
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/aclements/go-moremath/mathx"
@@ -200,3 +201,39 @@ func performAnalysis(old, new executionGroupResultsAsSlice) StatisticalCompareRe
 	}
 	return scr
 }
+
+// Regression returns a string containing the reason of the regression of the comparison,
+// if no regression was evaluated, the reason will be an empty string. Only the statistically
+// significant changes bigger than the given threshold, in percent, are considered. QPS and TPS
+// regress when they decrease, while latency, CPU time and memory usage regress when they increase.
+// The format of a single metric regression's reason is like this:
+//
+// "- {metric name} {decreased|increased} by {percentage}% (p={p-value})\n"
+func (scr StatisticalCompareResults) Regression(threshold float64) (reason string) {
+	if scr.MissingResults {
+		return ""
+	}
+	metrics := []struct {
+		name           string
+		result         StatisticalResult
+		higherIsBetter bool
+	}{
+		{name: "total QPS", result: scr.TotalQPS, higherIsBetter: true},
+		{name: "TPS", result: scr.TPS, higherIsBetter: true},
+		{name: "latency", result: scr.Latency},
+		{name: "total CPU time", result: scr.TotalComponentsCPUTime},
+		{name: "total memory allocated", result: scr.TotalComponentsMemStatsAllocBytes},
+	}
+	for _, m := range metrics {
+		if m.result.Insignificant {
+			continue
+		}
+		switch {
+		case m.higherIsBetter && m.result.Delta < -threshold:
+			reason += fmt.Sprintf("- %s decreased by %.2f%% (p=%.3f)\n", m.name, -m.result.Delta, m.result.P)
+		case !m.higherIsBetter && m.result.Delta > threshold:
+			reason += fmt.Sprintf("- %s increased by %.2f%% (p=%.3f)\n", m.name, m.result.Delta, m.result.P)
+		}
+	}
+	return reason
+}
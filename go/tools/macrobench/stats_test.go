@@ -0,0 +1,70 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package macrobench
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestStatisticalCompareResults_Regression(t *testing.T) {
+	tests := []struct {
+		name       string
+		results    StatisticalCompareResults
+		threshold  float64
+		wantReason string
+	}{
+		{name: "No change", results: StatisticalCompareResults{}, threshold: 5},
+		{name: "Missing results", results: StatisticalCompareResults{MissingResults: true, TotalQPS: StatisticalResult{Delta: -50}}, threshold: 5},
+		{
+			name: "Insignificant regressions",
+			results: StatisticalCompareResults{
+				TotalQPS: StatisticalResult{Delta: -20, P: 0.2, Insignificant: true},
+				Latency:  StatisticalResult{Delta: 20, P: 0.2, Insignificant: true},
+			},
+			threshold: 5,
+		},
+		{
+			name: "Improvements",
+			results: StatisticalCompareResults{
+				TotalQPS: StatisticalResult{Delta: 20, P: 0.001},
+				Latency:  StatisticalResult{Delta: -20, P: 0.001},
+			},
+			threshold: 5,
+		},
+		{
+			name: "Regressions",
+			results: StatisticalCompareResults{
+				TotalQPS:                          StatisticalResult{Delta: -7.5, P: 0.001},
+				TPS:                               StatisticalResult{Delta: -4, P: 0.001},
+				Latency:                           StatisticalResult{Delta: 12, P: 0.002},
+				TotalComponentsMemStatsAllocBytes: StatisticalResult{Delta: 5.5, P: 0.03},
+			},
+			threshold:  5,
+			wantReason: "- total QPS decreased by 7.50% (p=0.001)\n- latency increased by 12.00% (p=0.002)\n- total memory allocated increased by 5.50% (p=0.030)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(tt.results.Regression(tt.threshold), qt.Equals, tt.wantReason)
+		})
+	}
+}
@@ -230,7 +230,7 @@ func GetVTGateSelectQueryPlansWithFilter(gitRef string, workload Workload, plann
 		"and ex.uuid = qp.exec_uuid " +
 		"and ex.uuid = ma.exec_uuid " +
 		"and ex.workload = ? " +
-		"and ma.commit = ? " +
+		"and ma.`commit` = ? " +
 		"and ma.vtgate_planner_version = ? " +
 		"group by " +
 		"qp.`key`, qp.plan " +
@@ -282,13 +282,11 @@ func insertVTGateQueryMapToMySQL(client storage.SQLClient, execUUID string, resu
 		return errors.New(mysql.ErrorClientConnectionNotInitialized)
 	}
 
-	query := "INSERT INTO query_plans(`exec_uuid`, `macrobenchmark_id`, `key`, `plan`, `exec_count`, `exec_time`, `rows`, `errors`) VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	columns := []string{"exec_uuid", "macrobenchmark_id", "key", "plan", "exec_count", "exec_time", "rows", "errors"}
+	rows := make([][]interface{}, 0, len(result))
 	for key, value := range result {
 		normalizeVTGateQueryPlan(&value)
-		_, err := client.Write(query, execUUID, macrobenchmarkID, key, fmt.Sprintf("%v", value.Instructions), value.ExecCount, value.ExecTime, value.RowsReturned, value.Errors)
-		if err != nil {
-			return err
-		}
+		rows = append(rows, []interface{}{execUUID, macrobenchmarkID, key, fmt.Sprintf("%v", value.Instructions), value.ExecCount, value.ExecTime, value.RowsReturned, value.Errors})
 	}
-	return nil
+	return storage.BulkInsert(client, "query_plans", columns, rows)
 }
@@ -42,6 +42,9 @@ func Compare(client storage.SQLClient, right string, left string) (ComparisonArr
 	return microsMatrix, nil
 }
 
+// DefaultRegressionThreshold is the decrease, in percent, above which a metric is considered as regressed.
+const DefaultRegressionThreshold = 10.0
+
 // Regression returns a string containing the reason of the regression of the given ComparisonArray,
 // if no regression was evaluated, the reason will be an empty string.
 // The format of a single benchmark regression's reason is like this:
@@ -49,6 +52,12 @@ func Compare(client storage.SQLClient, right string, left string) (ComparisonArr
 // "- {pkg name}/{benchmark name} decreased by {decrease percentage}%\n"
 //
 func (microsMatrix ComparisonArray) Regression() (reason string) {
+	return microsMatrix.RegressionWithThreshold(DefaultRegressionThreshold)
+}
+
+// RegressionWithThreshold works like Regression, but only the metrics that decreased
+// by more than the given threshold, in percent, are considered as regressed.
+func (microsMatrix ComparisonArray) RegressionWithThreshold(threshold float64) (reason string) {
 	for _, micro := range microsMatrix {
 		m := []struct{
 			value float64
@@ -62,7 +71,7 @@ func (microsMatrix ComparisonArray) Regression() (reason string) {
 		}
 
 		for _, s := range m {
-			if s.value < -10 {
+			if s.value < -threshold {
 				reason += fmt.Sprintf("- %s/%s: metric: %s, decreased by %.2f%%\n", micro.PkgName, micro.SubBenchmarkName, s.name, -1*s.value)
 			}
 		}
@@ -59,3 +59,13 @@ func TestMicroBenchmarkComparisonArray_Regression(t *testing.T) {
 		})
 	}
 }
+
+func TestMicroBenchmarkComparisonArray_RegressionWithThreshold(t *testing.T) {
+	c := qt.New(t)
+	microsMatrix := ComparisonArray{
+		{BenchmarkId: BenchmarkId{PkgName: "pkg1", Name: "bench1", SubBenchmarkName: "bench1-pkg1"}, Diff: Result{NSPerOp: -4}},
+		{BenchmarkId: BenchmarkId{PkgName: "pkg1", Name: "bench2", SubBenchmarkName: "bench2-pkg1"}, Diff: Result{NSPerOp: -6}},
+	}
+	c.Assert(microsMatrix.Regression(), qt.Equals, "")
+	c.Assert(microsMatrix.RegressionWithThreshold(5), qt.Equals, "- pkg1/bench2-pkg1: metric: nanosecond per operation, decreased by 6.00%\n")
+}
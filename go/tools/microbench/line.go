@@ -70,7 +70,6 @@ type lineRun struct {
 	Output  string
 	Elapsed string
 
-	id        int64
 	name      string
 	benchType microType
 	submatch  []string
@@ -156,12 +155,13 @@ func (line *lineRun) parseGeneralBenchmark() error {
 	return nil
 }
 
-func (line *lineRun) InsertToMySQL(microBenchID int64, client storage.SQLClient) error {
-	query := "INSERT INTO microbenchmark_details(microbenchmark_no, name, bench_type, n, ns_per_op, mb_per_sec, bytes_per_op, allocs_per_op) VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
-	res, err := client.Write(query, microBenchID, line.name, line.benchType, line.results.Op, line.results.NanosecondPerOp, line.results.MBs, line.results.BytesPerOp, line.results.AllocsPerOp)
-	if err != nil {
-		return err
+// insertLinesToMySQL inserts the results of the given lines, which belong to the
+// microbenchmark of the given ID.
+func insertLinesToMySQL(client storage.SQLClient, microBenchID int64, lines []lineRun) error {
+	columns := []string{"microbenchmark_no", "name", "bench_type", "n", "ns_per_op", "mb_per_sec", "bytes_per_op", "allocs_per_op"}
+	rows := make([][]interface{}, 0, len(lines))
+	for _, line := range lines {
+		rows = append(rows, []interface{}{microBenchID, line.name, line.benchType, line.results.Op, line.results.NanosecondPerOp, line.results.MBs, line.results.BytesPerOp, line.results.AllocsPerOp})
 	}
-	line.id = res
-	return nil
+	return storage.BulkInsert(client, "microbenchmark_details", columns, rows)
 }
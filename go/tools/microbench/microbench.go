@@ -70,12 +70,7 @@ func (b *benchmark) execute(rootDir string, w *os.File) error {
 		return err
 	}
 
-	if b.sql != nil {
-		if err := b.registerToMySQL(b.sql); err != nil {
-			return err
-		}
-	}
-
+	var benchLines []lineRun
 	lines := strings.Split(string(out), "\n")
 	for _, line := range lines {
 		var benchLine lineRun
@@ -92,15 +87,20 @@ func (b *benchmark) execute(rootDir string, w *os.File) error {
 		if benchLine.benchType != "" {
 			log.Printf("%s - %s %f ns/op\n", b.pkgName, benchLine.name, benchLine.results.NanosecondPerOp)
 			fmt.Fprintf(w, "%s - %s %f ns/op\n", b.pkgName, benchLine.name, benchLine.results.NanosecondPerOp)
-			if b.sql != nil {
-				err = benchLine.InsertToMySQL(b.id, b.sql)
-				if err != nil {
-					return err
-				}
-			}
+			benchLines = append(benchLines, benchLine)
 		}
 	}
-	return nil
+
+	if b.sql == nil {
+		return nil
+	}
+	// The benchmark and its results are saved together, or not at all.
+	return storage.WithTransaction(b.sql, func(tx storage.SQLClient) error {
+		if err := b.registerToMySQL(tx); err != nil {
+			return err
+		}
+		return insertLinesToMySQL(tx, b.id, benchLines)
+	})
 }
 
 func (b benchmark) executeProfile(rootDir, profileType string, w *os.File) error {
@@ -0,0 +1,220 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+// Package pprof compares the profiles collected during two executions at the function level.
+package pprof
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// FunctionDiff is the difference of the values sampled in a function between two profiles.
+// The flat value only counts the samples of which the function is the leaf, the cumulative
+// value counts all the samples in which the function appears.
+type FunctionDiff struct {
+	Name      string `json:"name"`
+	OldFlat   int64  `json:"old_flat"`
+	NewFlat   int64  `json:"new_flat"`
+	FlatDelta int64  `json:"flat_delta"`
+	OldCum    int64  `json:"old_cum"`
+	NewCum    int64  `json:"new_cum"`
+	CumDelta  int64  `json:"cum_delta"`
+}
+
+// Diff is the comparison of two profiles for one sample type.
+type Diff struct {
+	SampleType string         `json:"sample_type"`
+	Unit       string         `json:"unit"`
+	OldTotal   int64          `json:"old_total"`
+	NewTotal   int64          `json:"new_total"`
+	Functions  []FunctionDiff `json:"functions"`
+}
+
+// Compare computes the function level difference between the old and the new profile for
+// the given sample type, or for the default sample type of the profiles if it is empty.
+// The functions are sorted by decreasing absolute flat delta, and only the top ones are
+// kept. A top lower or equal to zero keeps all of them.
+func Compare(old, new *profile.Profile, sampleType string, top int) (*Diff, error) {
+	oldIdx, newIdx, err := sampleIndexes(old, new, sampleType)
+	if err != nil {
+		return nil, err
+	}
+	diff := &Diff{
+		SampleType: new.SampleType[newIdx].Type,
+		Unit:       new.SampleType[newIdx].Unit,
+		Functions:  []FunctionDiff{},
+	}
+
+	functions := map[string]*FunctionDiff{}
+	get := func(name string) *FunctionDiff {
+		f, ok := functions[name]
+		if !ok {
+			f = &FunctionDiff{Name: name}
+			functions[name] = f
+		}
+		return f
+	}
+	diff.OldTotal = aggregate(old, oldIdx, func(f string, flat, cum int64) {
+		fd := get(f)
+		fd.OldFlat += flat
+		fd.OldCum += cum
+	})
+	diff.NewTotal = aggregate(new, newIdx, func(f string, flat, cum int64) {
+		fd := get(f)
+		fd.NewFlat += flat
+		fd.NewCum += cum
+	})
+
+	for _, f := range functions {
+		f.FlatDelta = f.NewFlat - f.OldFlat
+		f.CumDelta = f.NewCum - f.OldCum
+		if f.FlatDelta == 0 && f.CumDelta == 0 {
+			continue
+		}
+		diff.Functions = append(diff.Functions, *f)
+	}
+	sort.Slice(diff.Functions, func(i, j int) bool {
+		fi, fj := diff.Functions[i], diff.Functions[j]
+		if abs(fi.FlatDelta) != abs(fj.FlatDelta) {
+			return abs(fi.FlatDelta) > abs(fj.FlatDelta)
+		}
+		if abs(fi.CumDelta) != abs(fj.CumDelta) {
+			return abs(fi.CumDelta) > abs(fj.CumDelta)
+		}
+		return fi.Name < fj.Name
+	})
+	if top > 0 && len(diff.Functions) > top {
+		diff.Functions = diff.Functions[:top]
+	}
+	return diff, nil
+}
+
+// FoldedStacks returns the stacks of the old and new profiles in the folded format used by
+// differential flame graphs: the frames go from the root to the leaf and are separated by
+// semicolons, they are followed by the value of the stack in the old and in the new profile.
+func FoldedStacks(old, new *profile.Profile, sampleType string) ([]string, error) {
+	oldIdx, newIdx, err := sampleIndexes(old, new, sampleType)
+	if err != nil {
+		return nil, err
+	}
+	type values struct{ old, new int64 }
+	stacks := map[string]*values{}
+	fold := func(p *profile.Profile, idx int, isNew bool) {
+		for _, s := range p.Sample {
+			frames := frames(s)
+			for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+				frames[i], frames[j] = frames[j], frames[i]
+			}
+			stack := strings.Join(frames, ";")
+			v, ok := stacks[stack]
+			if !ok {
+				v = &values{}
+				stacks[stack] = v
+			}
+			if isNew {
+				v.new += s.Value[idx]
+			} else {
+				v.old += s.Value[idx]
+			}
+		}
+	}
+	fold(old, oldIdx, false)
+	fold(new, newIdx, true)
+
+	folded := make([]string, 0, len(stacks))
+	for stack, v := range stacks {
+		folded = append(folded, fmt.Sprintf("%s %d %d", stack, v.old, v.new))
+	}
+	sort.Strings(folded)
+	return folded, nil
+}
+
+// sampleIndexes returns the index of the given sample type in the old and new profiles.
+func sampleIndexes(old, new *profile.Profile, sampleType string) (oldIdx, newIdx int, err error) {
+	newIdx, err = new.SampleIndexByName(sampleType)
+	if err != nil {
+		return 0, 0, fmt.Errorf("new profile: %w", err)
+	}
+	if sampleType == "" {
+		sampleType = new.SampleType[newIdx].Type
+	}
+	oldIdx, err = old.SampleIndexByName(sampleType)
+	if err != nil {
+		return 0, 0, fmt.Errorf("old profile: %w", err)
+	}
+	if oldUnit, newUnit := old.SampleType[oldIdx].Unit, new.SampleType[newIdx].Unit; oldUnit != newUnit {
+		return 0, 0, fmt.Errorf("the sample type %s is measured in %s in the old profile and in %s in the new one", sampleType, oldUnit, newUnit)
+	}
+	return oldIdx, newIdx, nil
+}
+
+// aggregate calls fn with the flat and cumulative value of every function of every sample
+// of the profile, and returns the total value of the samples. A function that appears
+// several times in the same stack is only counted once in the cumulative value.
+func aggregate(p *profile.Profile, idx int, fn func(function string, flat, cum int64)) (total int64) {
+	for _, s := range p.Sample {
+		v := s.Value[idx]
+		total += v
+		frames := frames(s)
+		seen := make(map[string]bool, len(frames))
+		for i, f := range frames {
+			var flat, cum int64
+			if i == 0 {
+				flat = v
+			}
+			if !seen[f] {
+				seen[f] = true
+				cum = v
+			}
+			fn(f, flat, cum)
+		}
+	}
+	return total
+}
+
+// frames returns the name of the functions of the stack of the given sample, from the
+// leaf to the root. Inlined functions are part of the stack, and locations that are not
+// symbolized are named after their address.
+func frames(s *profile.Sample) []string {
+	var frames []string
+	for _, loc := range s.Location {
+		if len(loc.Line) == 0 {
+			frames = append(frames, fmt.Sprintf("0x%x", loc.Address))
+			continue
+		}
+		for _, line := range loc.Line {
+			if line.Function == nil {
+				frames = append(frames, fmt.Sprintf("0x%x", loc.Address))
+				continue
+			}
+			frames = append(frames, line.Function.Name)
+		}
+	}
+	return frames
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
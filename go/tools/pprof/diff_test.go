@@ -0,0 +1,140 @@
+/*
+ *
+ * Copyright 2026 The Vitess Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * /
+ */
+
+package pprof
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/pprof/profile"
+)
+
+// newProfile builds a CPU profile from stacks of function names going from the leaf to the root.
+func newProfile(stacks map[string][]string, values map[string]int64) *profile.Profile {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+	}
+	functions := map[string]*profile.Function{}
+	locations := map[string]*profile.Location{}
+	for name, stack := range stacks {
+		s := &profile.Sample{Value: []int64{1, values[name]}}
+		for _, fn := range stack {
+			loc, ok := locations[fn]
+			if !ok {
+				f := &profile.Function{ID: uint64(len(functions) + 1), Name: fn}
+				functions[fn] = f
+				p.Function = append(p.Function, f)
+				loc = &profile.Location{ID: uint64(len(locations) + 1), Line: []profile.Line{{Function: f}}}
+				locations[fn] = loc
+				p.Location = append(p.Location, loc)
+			}
+			s.Location = append(s.Location, loc)
+		}
+		p.Sample = append(p.Sample, s)
+	}
+	return p
+}
+
+func TestCompare(t *testing.T) {
+	old := newProfile(
+		map[string][]string{"a": {"parse", "handle", "main"}, "b": {"execute", "handle", "main"}},
+		map[string]int64{"a": 100, "b": 300},
+	)
+	new := newProfile(
+		map[string][]string{"a": {"parse", "handle", "main"}, "b": {"execute", "handle", "main"}, "c": {"handle", "main"}},
+		map[string]int64{"a": 250, "b": 200, "c": 20},
+	)
+
+	tests := []struct {
+		name       string
+		sampleType string
+		top        int
+		want       *Diff
+		wantErr    string
+	}{
+		{name: "Default sample type", want: &Diff{
+			SampleType: "cpu", Unit: "nanoseconds", OldTotal: 400, NewTotal: 470,
+			Functions: []FunctionDiff{
+				{Name: "parse", OldFlat: 100, NewFlat: 250, FlatDelta: 150, OldCum: 100, NewCum: 250, CumDelta: 150},
+				{Name: "execute", OldFlat: 300, NewFlat: 200, FlatDelta: -100, OldCum: 300, NewCum: 200, CumDelta: -100},
+				{Name: "handle", OldFlat: 0, NewFlat: 20, FlatDelta: 20, OldCum: 400, NewCum: 470, CumDelta: 70},
+				{Name: "main", OldFlat: 0, NewFlat: 0, FlatDelta: 0, OldCum: 400, NewCum: 470, CumDelta: 70},
+			},
+		}},
+		{name: "Top functions", top: 1, want: &Diff{
+			SampleType: "cpu", Unit: "nanoseconds", OldTotal: 400, NewTotal: 470,
+			Functions: []FunctionDiff{
+				{Name: "parse", OldFlat: 100, NewFlat: 250, FlatDelta: 150, OldCum: 100, NewCum: 250, CumDelta: 150},
+			},
+		}},
+		{name: "Other sample type", sampleType: "samples", top: 2, want: &Diff{
+			SampleType: "samples", Unit: "count", OldTotal: 2, NewTotal: 3,
+			Functions: []FunctionDiff{
+				{Name: "handle", OldFlat: 0, NewFlat: 1, FlatDelta: 1, OldCum: 2, NewCum: 3, CumDelta: 1},
+				{Name: "main", OldFlat: 0, NewFlat: 0, FlatDelta: 0, OldCum: 2, NewCum: 3, CumDelta: 1},
+			},
+		}},
+		{name: "Unknown sample type", sampleType: "alloc_space", wantErr: "new profile: sample_index \"alloc_space\" must be one of: .*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			got, err := Compare(old, new, tt.sampleType, tt.top)
+			if tt.wantErr != "" {
+				c.Assert(err, qt.ErrorMatches, tt.wantErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestCompareRecursiveFunction(t *testing.T) {
+	c := qt.New(t)
+	old := newProfile(map[string][]string{"a": {"walk", "walk", "main"}}, map[string]int64{"a": 10})
+	new := newProfile(map[string][]string{"a": {"walk", "walk", "main"}}, map[string]int64{"a": 30})
+
+	got, err := Compare(old, new, "", 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got.Functions, qt.DeepEquals, []FunctionDiff{
+		{Name: "walk", OldFlat: 10, NewFlat: 30, FlatDelta: 20, OldCum: 10, NewCum: 30, CumDelta: 20},
+		{Name: "main", OldCum: 10, NewCum: 30, CumDelta: 20},
+	})
+}
+
+func TestFoldedStacks(t *testing.T) {
+	c := qt.New(t)
+	old := newProfile(
+		map[string][]string{"a": {"parse", "handle", "main"}, "b": {"execute", "handle", "main"}},
+		map[string]int64{"a": 100, "b": 300},
+	)
+	new := newProfile(
+		map[string][]string{"a": {"parse", "handle", "main"}, "c": {"handle", "main"}},
+		map[string]int64{"a": 250, "c": 20},
+	)
+
+	got, err := FoldedStacks(old, new, "cpu")
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, []string{
+		"main;handle 0 20",
+		"main;handle;execute 300 0",
+		"main;handle;parse 100 250",
+	})
+}